@@ -0,0 +1,66 @@
+package entdomain
+
+import (
+	"context"
+	"fmt"
+)
+
+// Access is the part of an owned resource the caller may reach: the rows
+// whose owner field holds Owner, or every row when All is set.
+type Access struct {
+	// All grants every row, e.g. to administrators and background jobs.
+	All bool
+
+	// Owner is compared with the owner field, so it must have the field's
+	// Go type, e.g. uuid.UUID for a UUID user_id.
+	Owner any
+}
+
+// AccessPolicy decides which rows of an owned resource the caller in ctx may
+// reach. Generated services of entities declared with
+// DomainConfig.WithOwnerField consult it on every read and write by ID, and
+// add the ownership predicate to their queries. resource is the same
+// snake_case name Authorizer sees.
+type AccessPolicy interface {
+	Access(ctx context.Context, resource string) (Access, error)
+}
+
+// AccessPolicyFunc adapts an ordinary function to the AccessPolicy interface.
+type AccessPolicyFunc func(ctx context.Context, resource string) (Access, error)
+
+// Access calls f(ctx, resource).
+func (f AccessPolicyFunc) Access(ctx context.Context, resource string) (Access, error) {
+	return f(ctx, resource)
+}
+
+// ResolveAccess returns the Access of the caller in ctx to resource under
+// policy. A nil policy is treated according to mode, as by Authorize:
+// permissive grants every row, strict denies with ErrForbidden. An Access
+// granting neither All nor an Owner is denied as well.
+func ResolveAccess(ctx context.Context, policy AccessPolicy, mode AuthorizationMode, resource string) (Access, error) {
+	if policy == nil {
+		if mode == AuthorizationStrict {
+			return Access{}, fmt.Errorf("%w: no access policy configured for %s", ErrForbidden, resource)
+		}
+		return Access{All: true}, nil
+	}
+	access, err := policy.Access(ctx, resource)
+	if err != nil {
+		return Access{}, err
+	}
+	if !access.All && access.Owner == nil {
+		return Access{}, fmt.Errorf("%w: no owner for %s", ErrForbidden, resource)
+	}
+	return access, nil
+}
+
+// AccessOwner returns the Owner of access as T, the Go type of the owner
+// field. An owner of another type is a wiring mistake and returns an error.
+func AccessOwner[T any](access Access) (T, error) {
+	owner, ok := access.Owner.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("access owner %T is not a %T", access.Owner, zero)
+	}
+	return owner, nil
+}
@@ -0,0 +1,48 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestResolveAccess(t *testing.T) {
+	ctx := context.Background()
+
+	if a, err := ResolveAccess(ctx, nil, AuthorizationPermissive, "post"); err != nil || !a.All {
+		t.Errorf("permissive mode with nil policy: got %+v, %v, want All", a, err)
+	}
+	if _, err := ResolveAccess(ctx, nil, AuthorizationStrict, "post"); !IsForbidden(err) {
+		t.Errorf("strict mode with nil policy: got %v, want ErrForbidden", err)
+	}
+
+	var gotResource string
+	policy := AccessPolicyFunc(func(_ context.Context, resource string) (Access, error) {
+		gotResource = resource
+		return Access{Owner: 7}, nil
+	})
+	a, err := ResolveAccess(ctx, policy, AuthorizationStrict, "post")
+	if err != nil || a.Owner != 7 || gotResource != "post" {
+		t.Errorf("ResolveAccess() = %+v, %v; policy saw %q", a, err, gotResource)
+	}
+
+	empty := AccessPolicyFunc(func(context.Context, string) (Access, error) { return Access{}, nil })
+	if _, err := ResolveAccess(ctx, empty, AuthorizationPermissive, "post"); !IsForbidden(err) {
+		t.Errorf("empty access: got %v, want ErrForbidden", err)
+	}
+
+	fail := errors.New("fail")
+	failing := AccessPolicyFunc(func(context.Context, string) (Access, error) { return Access{}, fail })
+	if _, err := ResolveAccess(ctx, failing, AuthorizationPermissive, "post"); !errors.Is(err, fail) {
+		t.Errorf("failing policy: got %v, want its error", err)
+	}
+}
+
+func TestAccessOwner(t *testing.T) {
+	if owner, err := AccessOwner[int](Access{Owner: 7}); err != nil || owner != 7 {
+		t.Errorf("AccessOwner[int]() = %v, %v", owner, err)
+	}
+	if _, err := AccessOwner[string](Access{Owner: 7}); err == nil {
+		t.Error("AccessOwner[string]() of an int owner should fail")
+	}
+}
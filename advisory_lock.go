@@ -0,0 +1,110 @@
+package entdomain
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// ErrLockNotAcquired indicates an advisory lock could not be obtained in time.
+var ErrLockNotAcquired = errors.New("advisory lock not acquired")
+
+// AdvisoryLocker holds a database-level advisory lock named key while fn runs,
+// coordinating critical sections across processes that share a database.
+// The lock is released when fn returns, whether or not it fails.
+type AdvisoryLocker interface {
+	WithLock(ctx context.Context, key string, fn func(ctx context.Context) error) error
+}
+
+// WithAdvisoryLock runs fn while holding the advisory lock key on locker.
+// It fails fast when locker is nil instead of running fn unprotected.
+func WithAdvisoryLock(ctx context.Context, locker AdvisoryLocker, key string, fn func(ctx context.Context) error) error {
+	if locker == nil {
+		return fmt.Errorf("advisory lock %q: no locker configured", key)
+	}
+	return locker.WithLock(ctx, key, fn)
+}
+
+// AdvisoryLockKey maps a lock name to the 64-bit integer key Postgres advisory
+// locks require, using a stable FNV-1a hash.
+func AdvisoryLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// PostgresAdvisoryLocker implements AdvisoryLocker with pg_advisory_xact_lock.
+// Each WithLock call opens a short transaction that holds the lock; Postgres
+// releases it automatically when that transaction ends, even if the process dies.
+// Note that fn's own queries do not run in that transaction.
+type PostgresAdvisoryLocker struct {
+	DB *sql.DB
+}
+
+// WithLock implements AdvisoryLocker. It blocks until the lock is available
+// or ctx is done.
+func (l PostgresAdvisoryLocker) WithLock(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("advisory lock %q: %w", key, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", AdvisoryLockKey(key)); err != nil {
+		return fmt.Errorf("advisory lock %q: %w", key, err)
+	}
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("advisory lock %q: release failed: %w", key, err)
+	}
+	return nil
+}
+
+// MySQLAdvisoryLocker implements AdvisoryLocker with GET_LOCK/RELEASE_LOCK.
+// Both calls run on the same pooled connection, which is held for the
+// duration of fn.
+type MySQLAdvisoryLocker struct {
+	DB *sql.DB
+
+	// Timeout bounds how long GET_LOCK waits. Zero means fail immediately
+	// when the lock is held elsewhere.
+	Timeout time.Duration
+}
+
+// mysqlLockNameMax is the maximum lock name length accepted by GET_LOCK.
+const mysqlLockNameMax = 64
+
+// WithLock implements AdvisoryLocker. It returns ErrLockNotAcquired when the
+// lock is still held elsewhere after Timeout.
+func (l MySQLAdvisoryLocker) WithLock(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	name := key
+	if len(name) > mysqlLockNameMax {
+		name = fmt.Sprintf("entdomain:%x", uint64(AdvisoryLockKey(key)))
+	}
+
+	conn, err := l.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("advisory lock %q: %w", key, err)
+	}
+	defer conn.Close()
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, int64(l.Timeout/time.Second)).Scan(&acquired); err != nil {
+		return fmt.Errorf("advisory lock %q: %w", key, err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return fmt.Errorf("%w: %q", ErrLockNotAcquired, key)
+	}
+	defer func() {
+		// Release on a fresh context so a cancelled ctx does not leak the lock.
+		var released sql.NullInt64
+		_ = conn.QueryRowContext(context.WithoutCancel(ctx), "SELECT RELEASE_LOCK(?)", name).Scan(&released)
+	}()
+
+	return fn(ctx)
+}
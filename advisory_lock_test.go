@@ -0,0 +1,206 @@
+package entdomain
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingDriver is a minimal database/sql driver that records every
+// statement and answers queries with a configurable single-value row.
+type recordingDriver struct {
+	mu      sync.Mutex
+	log     []string
+	answers map[string]driver.Value // query prefix → scalar result
+}
+
+func (d *recordingDriver) record(s string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, s)
+}
+
+func (d *recordingDriver) statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.log...)
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d: d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (c *recordingConn) Close() error { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.d.record("BEGIN")
+	return recordingTx{d: c.d}, nil
+}
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query)
+	return driver.RowsAffected(0), nil
+}
+func (c *recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.d.record(query)
+	for prefix, v := range c.d.answers {
+		if strings.HasPrefix(query, prefix) {
+			return &scalarRows{v: v}, nil
+		}
+	}
+	return &scalarRows{v: int64(1)}, nil
+}
+
+type recordingTx struct{ d *recordingDriver }
+
+func (t recordingTx) Commit() error   { t.d.record("COMMIT"); return nil }
+func (t recordingTx) Rollback() error { t.d.record("ROLLBACK"); return nil }
+
+type scalarRows struct {
+	v    driver.Value
+	done bool
+}
+
+func (r *scalarRows) Columns() []string { return []string{"v"} }
+func (r *scalarRows) Close() error      { return nil }
+func (r *scalarRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.v
+	return nil
+}
+
+var driverSeq struct {
+	sync.Mutex
+	n int
+}
+
+// openRecordingDB registers a fresh recordingDriver and opens a *sql.DB on it.
+func openRecordingDB(t *testing.T, answers map[string]driver.Value) (*sql.DB, *recordingDriver) {
+	t.Helper()
+	d := &recordingDriver{answers: answers}
+	driverSeq.Lock()
+	driverSeq.n++
+	name := fmt.Sprintf("entdomain-recording-%d", driverSeq.n)
+	driverSeq.Unlock()
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestAdvisoryLockKey(t *testing.T) {
+	if AdvisoryLockKey("user:1") != AdvisoryLockKey("user:1") {
+		t.Error("AdvisoryLockKey should be deterministic")
+	}
+	if AdvisoryLockKey("user:1") == AdvisoryLockKey("user:2") {
+		t.Error("different names should produce different keys")
+	}
+}
+
+func TestWithAdvisoryLock_NilLocker(t *testing.T) {
+	called := false
+	err := WithAdvisoryLock(context.Background(), nil, "k", func(context.Context) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected error for nil locker")
+	}
+	if called {
+		t.Error("fn must not run without a lock")
+	}
+}
+
+func TestPostgresAdvisoryLocker(t *testing.T) {
+	t.Run("commits after fn succeeds", func(t *testing.T) {
+		db, d := openRecordingDB(t, nil)
+		ran := false
+		err := WithAdvisoryLock(context.Background(), PostgresAdvisoryLocker{DB: db}, "user:1", func(context.Context) error {
+			ran = true
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WithLock() error = %v", err)
+		}
+		if !ran {
+			t.Error("fn was not called")
+		}
+		got := strings.Join(d.statements(), "; ")
+		want := "BEGIN; SELECT pg_advisory_xact_lock($1); COMMIT"
+		if got != want {
+			t.Errorf("statements = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("rolls back and returns fn error", func(t *testing.T) {
+		db, d := openRecordingDB(t, nil)
+		boom := errors.New("boom")
+		err := PostgresAdvisoryLocker{DB: db}.WithLock(context.Background(), "user:1", func(context.Context) error {
+			return boom
+		})
+		if !errors.Is(err, boom) {
+			t.Fatalf("WithLock() error = %v, want boom", err)
+		}
+		stmts := d.statements()
+		if stmts[len(stmts)-1] != "ROLLBACK" {
+			t.Errorf("last statement = %q, want ROLLBACK", stmts[len(stmts)-1])
+		}
+	})
+}
+
+func TestMySQLAdvisoryLocker(t *testing.T) {
+	t.Run("acquires and releases", func(t *testing.T) {
+		db, d := openRecordingDB(t, map[string]driver.Value{"SELECT GET_LOCK": int64(1)})
+		ran := false
+		err := MySQLAdvisoryLocker{DB: db}.WithLock(context.Background(), "user:1", func(context.Context) error {
+			ran = true
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WithLock() error = %v", err)
+		}
+		if !ran {
+			t.Error("fn was not called")
+		}
+		got := strings.Join(d.statements(), "; ")
+		want := "SELECT GET_LOCK(?, ?); SELECT RELEASE_LOCK(?)"
+		if got != want {
+			t.Errorf("statements = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("timeout returns ErrLockNotAcquired", func(t *testing.T) {
+		db, d := openRecordingDB(t, map[string]driver.Value{"SELECT GET_LOCK": int64(0)})
+		err := MySQLAdvisoryLocker{DB: db}.WithLock(context.Background(), "user:1", func(context.Context) error {
+			t.Error("fn must not run when the lock is not acquired")
+			return nil
+		})
+		if !errors.Is(err, ErrLockNotAcquired) {
+			t.Fatalf("WithLock() error = %v, want ErrLockNotAcquired", err)
+		}
+		if len(d.statements()) != 1 {
+			t.Errorf("expected only GET_LOCK, got %v", d.statements())
+		}
+	})
+
+	t.Run("NULL result returns ErrLockNotAcquired", func(t *testing.T) {
+		db, _ := openRecordingDB(t, map[string]driver.Value{"SELECT GET_LOCK": nil})
+		err := MySQLAdvisoryLocker{DB: db}.WithLock(context.Background(), "user:1", func(context.Context) error { return nil })
+		if !errors.Is(err, ErrLockNotAcquired) {
+			t.Fatalf("WithLock() error = %v, want ErrLockNotAcquired", err)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package entdomain
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"entgo.io/ent/entc/gen"
+)
+
+// aggregatedKinds are the generated file kinds ExtensionConfig.AggregatedOutput
+// consolidates, in the order their files are written. Example tests,
+// benchmarks and service extension scaffolds stay per entity.
+var aggregatedKinds = []string{"dto", "base_service", "base_handler", "permissions", "domain_mocks", "domain_tracing", "importer"}
+
+// aggregatedFile collects the rendered files of one kind for all entities, to
+// be written as a single file.
+type aggregatedFile struct {
+	pkg     string
+	imports []string
+	paths   map[string]bool
+	bodies  [][]byte
+}
+
+// add appends the declarations of content, a rendered file of entity, and
+// merges its imports. Each file's "custom" keep region is renamed after the
+// entity, so the regions stay distinct in the aggregated file.
+func (a *aggregatedFile) add(entity string, content []byte) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", content, parser.ImportsOnly)
+	if err != nil {
+		return err
+	}
+	if a.pkg == "" {
+		a.pkg = f.Name.Name
+		a.paths = make(map[string]bool)
+	}
+	for _, spec := range f.Imports {
+		if a.paths[spec.Path.Value] {
+			continue
+		}
+		a.paths[spec.Path.Value] = true
+		line := spec.Path.Value
+		if spec.Name != nil {
+			line = spec.Name.Name + " " + line
+		}
+		a.imports = append(a.imports, line)
+	}
+
+	end := f.Name.End()
+	if n := len(f.Decls); n > 0 {
+		end = f.Decls[n-1].End()
+	}
+	body := content[fset.Position(end).Offset:]
+	custom := []byte(keepBeginMarker + " custom")
+	body = bytes.ReplaceAll(body, custom, append(custom, " "+strings.ToLower(entity)...))
+	a.bodies = append(a.bodies, body)
+	return nil
+}
+
+// bytes returns the aggregated file; unused imports are left to goimports.
+func (a *aggregatedFile) bytes() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s. DO NOT EDIT.\n\npackage %s\n\nimport (\n", generatedHeader, a.pkg)
+	for _, line := range a.imports {
+		fmt.Fprintf(&b, "\t%s\n", line)
+	}
+	b.WriteString(")\n")
+	for _, body := range a.bodies {
+		b.Write(body)
+	}
+	return b.Bytes()
+}
+
+// aggregatedFilename returns the file name of an aggregated kind, e.g.
+// "entdomain_dto.go", or "entdomain_dto.gen.go" when GenSuffix is set.
+func (e *Extension) aggregatedFilename(kind string) string {
+	if e.Config.GenSuffix {
+		return "entdomain_" + kind + ".gen.go"
+	}
+	return "entdomain_" + kind + ".go"
+}
+
+// writeAggregatedFiles writes the files collected by writeGeneratedFile in
+// aggregated mode and resets the collection.
+func (e *Extension) writeAggregatedFiles(g *gen.Graph) error {
+	defer func() { e.aggregated = nil }()
+	for _, kind := range aggregatedKinds {
+		file, ok := e.aggregated[kind]
+		if !ok {
+			continue
+		}
+		if err := e.writeGeneratedPath(g, e.aggregatedFilename(kind), file.bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeOtherOutputMode deletes the files of the output mode not in use, left
+// behind when AggregatedOutput was toggled, as their declarations would
+// clash with the new files. Files with code in their keep regions are not
+// deleted but reported, so that code can be moved first.
+func (e *Extension) removeOtherOutputMode(g *gen.Graph) error {
+	var names []string
+	for _, kind := range aggregatedKinds {
+		if e.Config.AggregatedOutput {
+			for _, node := range g.Nodes {
+				name := fmt.Sprintf("%s_%s", strings.ToLower(node.Name), kind)
+				names = append(names, name+".go", name+".gen.go")
+			}
+		} else {
+			names = append(names, "entdomain_"+kind+".go", "entdomain_"+kind+".gen.go")
+		}
+	}
+	for _, name := range names {
+		path := filepath.Join(g.Config.Target, name)
+		if !isGeneratedFile(path) {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if hasKeptCode(content) {
+			return fmt.Errorf("%s holds code in keep regions; move it before switching AggregatedOutput", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// hasKeptCode reports whether src has a keep region with a non-blank line.
+func hasKeptCode(src []byte) bool {
+	regions, err := parseKeepRegions(src)
+	if err != nil {
+		return true
+	}
+	for _, r := range regions {
+		for _, line := range r.body {
+			if strings.TrimSpace(line) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
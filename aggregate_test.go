@@ -0,0 +1,73 @@
+package entdomain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAggregatedFile(t *testing.T) {
+	var file aggregatedFile
+	user := `// Code generated by entdomain extension from schema "User". DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+
+	entdomain "github.com/githonllc/entdomain"
+)
+
+type UserResponse struct{}
+
+// entdomain:begin keep custom
+// entdomain:end keep
+`
+	post := `package ent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/githonllc/entdomain"
+)
+
+type PostResponse struct{}
+`
+	if err := file.add("User", []byte(user)); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.add("Post", []byte(post)); err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(file.bytes())
+	for _, want := range []string{
+		generatedHeader + ". DO NOT EDIT.\n\npackage ent\n",
+		"\t\"fmt\"\n\tentdomain \"github.com/githonllc/entdomain\"\n\t\"time\"\n)\n",
+		"type UserResponse struct{}",
+		"// entdomain:begin keep custom user\n",
+		"type PostResponse struct{}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("aggregated file lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "github.com/githonllc/entdomain") != 1 {
+		t.Errorf("import of entdomain not merged:\n%s", got)
+	}
+
+	if err := file.add("Broken", []byte("not go")); err == nil {
+		t.Error("add() of invalid source should fail")
+	}
+}
+
+func TestHasKeptCode(t *testing.T) {
+	empty := "// entdomain:begin keep custom\n\n// entdomain:end keep\n"
+	if hasKeptCode([]byte(empty)) {
+		t.Error("blank keep region should not count as kept code")
+	}
+	kept := "// entdomain:begin keep custom\nfunc helper() {}\n// entdomain:end keep\n"
+	if !hasKeptCode([]byte(kept)) {
+		t.Error("keep region with code should count")
+	}
+}
@@ -41,8 +41,11 @@ const (
 var AllFieldScopes = []FieldScope{ScopeCreate, ScopeUpdate, ScopeQuery, ScopeResponse}
 
 // FieldMetadata holds field metadata for future documentation and API spec generation.
-// RESERVED: These fields are stored in annotations but not yet consumed by code generation
-// templates. They will be used when OpenAPI/Swagger spec generation is implemented.
+// Generated code reads Format for the time layouts of QueryParams and importers,
+// Title for the CSV headers of importers, and Deprecated for the warnings of
+// entdomain.Result. The other fields are RESERVED: they are stored in
+// annotations but will only be used when OpenAPI/Swagger spec generation is
+// implemented.
 type FieldMetadata struct {
 	// Title is the user-friendly field name
 	Title string `json:"title,omitempty"`
@@ -111,6 +114,19 @@ type DomainField struct {
 	// Searchable indicates whether the field is searchable (affects QueryParams and query method generation)
 	Searchable bool `json:"searchable,omitempty"`
 
+	// FullTextSearchable matches the string field in Search with full-text
+	// search instead of a substring match, together with the other full-text fields
+	FullTextSearchable bool `json:"full_text_searchable,omitempty"`
+
+	// SearchAlias names a logical query field matching this string field and the
+	// others with the same alias: a substring of any of them matches
+	SearchAlias string `json:"search_alias,omitempty"`
+
+	// CaseInsensitive compares the string field ignoring case in Search:
+	// EqualFold for equality filters and ContainsFold for the text query.
+	// The DeleteBy and CountBy field mutations still match exactly
+	CaseInsensitive bool `json:"case_insensitive,omitempty"`
+
 	// Sortable indicates whether the field is sortable (affects sorting-related API and query method generation)
 	Sortable bool `json:"sortable,omitempty"`
 
@@ -123,6 +139,17 @@ type DomainField struct {
 	// RangeLookup marks the field for generating FindByXRange methods (for time/numeric fields)
 	RangeLookup bool `json:"range_lookup,omitempty"`
 
+	// DefaultSort makes the field the default ordering of the generated List
+	// when the request has no SortBy: "asc" or "desc"
+	DefaultSort string `json:"default_sort,omitempty"`
+
+	// ShardKey marks the field whose value selects the shard (ent client) an entity lives on
+	ShardKey bool `json:"shard_key,omitempty"`
+
+	// Audited records the field's old and new values in the audit trail of
+	// the generated service's writes
+	Audited bool `json:"audited,omitempty"`
+
 	// Metadata contains additional field metadata for documentation and API spec generation
 	Metadata *FieldMetadata `json:"metadata,omitempty"`
 }
@@ -132,12 +159,88 @@ func (DomainField) Name() string {
 	return "DomainField"
 }
 
+// IDGeneratorName names a built-in IDGenerator for DomainConfig.WithGeneratedID.
+type IDGeneratorName string
+
+const (
+	// ULID assigns ULIDGenerator IDs to string ID fields.
+	ULID IDGeneratorName = "ulid"
+
+	// Snowflake assigns DefaultSnowflake IDs to int, int64 and uint64 ID fields.
+	Snowflake IDGeneratorName = "snowflake"
+)
+
 // DomainConfig is the entity-level configuration annotation.
-// Currently used only for entity naming. Feature flags (soft delete, caching, etc.)
-// will be added when templates actually consume them.
+// Currently used for entity naming and resource paths. Feature flags (soft delete,
+// caching, etc.) will be added when templates actually consume them.
 type DomainConfig struct {
 	// EntityName overrides the default entity name derived from the schema.
 	EntityName string `json:"entity_name,omitempty"`
+
+	// Plural overrides the automatic pluralization of the entity name
+	// (e.g. "people" for Person), used for collection names.
+	Plural string `json:"plural,omitempty"`
+
+	// URLPath overrides the collection path segment used in generated routes
+	// (e.g. "persons"). Defaults to the kebab-cased plural.
+	URLPath string `json:"url_path,omitempty"`
+
+	// APIVersions overrides the extension-wide API version (WithAPIVersion)
+	// for this entity. The first entry is the current version; listing more
+	// (e.g. "v2", "v1") generates routes for each so that several versions
+	// can be served side by side during a migration.
+	APIVersions []string `json:"api_versions,omitempty"`
+
+	// Deprecation marks the entity's API as deprecated with a notice such as
+	// "use AccountV2". Generated DTOs and handlers carry a "Deprecated:" doc
+	// comment, and handlers can emit Deprecation/Sunset response headers.
+	Deprecation string `json:"deprecation,omitempty"`
+
+	// Sunset is the date ("2006-01-02") after which a deprecated API is
+	// removed, advertised through the Sunset response header.
+	Sunset string `json:"sunset,omitempty"`
+
+	// IDGenerator names the generator that assigns IDs to new entities in
+	// the generated Create: "ulid" for string ID fields, "snowflake" for
+	// int, int64 and uint64 ones. A service can replace it by setting its
+	// IDGenerator field.
+	IDGenerator string `json:"id_generator,omitempty"`
+
+	// IDPrefix makes the entity's IDs public in Stripe-style prefixed form
+	// ("usr_01H..."): Response DTOs carry the prefixed ID, and the generated
+	// Parse{Entity}ID strips the prefix again before the ID is queried.
+	IDPrefix string `json:"id_prefix,omitempty"`
+
+	// OptimisticLock names an integer field holding the row version. The
+	// generated Update then requires the expected version in its request,
+	// only updates a row still at that version, increments it, and returns
+	// ErrConflict when another write came first.
+	OptimisticLock string `json:"optimistic_lock,omitempty"`
+
+	// FieldMutations generates DeleteBy{Field} and CountBy{Field} for every
+	// filterable field, for bulk maintenance such as removing all disabled
+	// accounts.
+	FieldMutations bool `json:"field_mutations,omitempty"`
+
+	// Cached generates {Entity}CachedService, which serves GetByID from an
+	// entdomain.Cache and invalidates entries on Update and Delete.
+	Cached bool `json:"cached,omitempty"`
+
+	// OwnerField names the field holding the owner of each row, e.g.
+	// "user_id". Generated services then limit reads and writes by ID to the
+	// rows their AccessPolicy grants the caller.
+	OwnerField string `json:"owner_field,omitempty"`
+
+	// ArchivableField names the optional, nillable time field recording when
+	// a row was archived, e.g. "archived_at". Generated services then get
+	// Archive and Unarchive, and leave archived rows out of List and Search
+	// unless ListRequest.IncludeArchived is set.
+	ArchivableField string `json:"archivable_field,omitempty"`
+
+	// Events makes generated services publish {Entity}Created,
+	// {Entity}Updated and {Entity}Deleted events to their entdomain.EventBus
+	// after successful writes.
+	Events bool `json:"events,omitempty"`
 }
 
 // Name implements the schema.Annotation interface.
@@ -145,6 +248,93 @@ func (DomainConfig) Name() string {
 	return "DomainConfig"
 }
 
+// Deprecated marks the entity's API as deprecated with the given notice.
+func (c DomainConfig) Deprecated(notice string) DomainConfig {
+	c.Deprecation = notice
+	return c
+}
+
+// WithSunset sets the date ("2006-01-02") on which a deprecated API is removed.
+func (c DomainConfig) WithSunset(date string) DomainConfig {
+	c.Sunset = date
+	return c
+}
+
+// WithIDGenerator makes generated services assign IDs on Create with the
+// named generator (e.g. "ulid").
+func (c DomainConfig) WithIDGenerator(name string) DomainConfig {
+	c.IDGenerator = name
+	return c
+}
+
+// WithGeneratedID is WithIDGenerator for the built-in generators:
+// DomainConfig{}.WithGeneratedID(entdomain.Snowflake).
+func (c DomainConfig) WithGeneratedID(name IDGeneratorName) DomainConfig {
+	return c.WithIDGenerator(string(name))
+}
+
+// WithIDPrefix exposes the entity's IDs with the given prefix, e.g. "usr".
+func (c DomainConfig) WithIDPrefix(prefix string) DomainConfig {
+	c.IDPrefix = prefix
+	return c
+}
+
+// WithOptimisticLock guards updates with the version held in the named
+// integer field, e.g. "version".
+func (c DomainConfig) WithOptimisticLock(field string) DomainConfig {
+	c.OptimisticLock = field
+	return c
+}
+
+// WithFieldMutations generates DeleteBy{Field} and CountBy{Field} methods for
+// the entity's filterable fields.
+func (c DomainConfig) WithFieldMutations() DomainConfig {
+	c.FieldMutations = true
+	return c
+}
+
+// WithCache generates a caching {Entity}CachedService wrapping the base
+// service.
+func (c DomainConfig) WithCache() DomainConfig {
+	c.Cached = true
+	return c
+}
+
+// WithOwnerField makes the named field, e.g. "user_id", the owner of each
+// row, so that callers only reach the rows their AccessPolicy grants.
+func (c DomainConfig) WithOwnerField(field string) DomainConfig {
+	c.OwnerField = field
+	return c
+}
+
+// WithArchivableField records archiving in the named field, e.g.
+// "archived_at". Unlike soft-deleted rows, archived ones stay readable by ID.
+func (c DomainConfig) WithArchivableField(field string) DomainConfig {
+	c.ArchivableField = field
+	return c
+}
+
+// WithEvents publishes the entity's Created, Updated and Deleted domain
+// events from its generated service.
+func (c DomainConfig) WithEvents() DomainConfig {
+	c.Events = true
+	return c
+}
+
+// DomainEdge is the edge-level annotation exposing an ent edge in the domain
+// layer. The Response DTO nests the edge's entities, and generated services
+// eager load it by name in GetByIDWithEdges and through ListRequest.Edges:
+//
+//	edge.To("posts", Post.Type).Annotations(entdomain.DomainEdge{})
+//
+// Edges whose foreign key field has ScopeResponse are exposed without it.
+type DomainEdge struct{}
+
+// Name implements the schema.Annotation interface.
+func (DomainEdge) Name() string {
+	return "DomainEdge"
+}
+
 // Core annotation builder functions
 
 // NewDomainField creates an empty domain field annotation
@@ -274,6 +464,30 @@ func (d DomainField) AsSearchable() DomainField {
 	return d
 }
 
+// AsFullTextSearchable marks the string field for full-text search: on
+// PostgreSQL, Search matches it with to_tsvector and websearch_to_tsquery, and
+// elsewhere falls back to LIKE. It also marks the field as searchable.
+func (d DomainField) AsFullTextSearchable() DomainField {
+	d.FullTextSearchable = true
+	d.Searchable = true
+	return d
+}
+
+// WithSearchAlias adds the string field to the logical query field alias, so
+// fields such as first_name and last_name can be searched together as "name".
+// The alias becomes a QueryParams parameter and a Search Filters key.
+func (d DomainField) WithSearchAlias(alias string) DomainField {
+	d.SearchAlias = alias
+	return d
+}
+
+// AsCaseInsensitive makes Search compare the string field ignoring case. The
+// DeleteBy and CountBy field mutations still match it exactly.
+func (d DomainField) AsCaseInsensitive() DomainField {
+	d.CaseInsensitive = true
+	return d
+}
+
 // AsSortable marks the field as sortable
 func (d DomainField) AsSortable() DomainField {
 	d.Sortable = true
@@ -298,6 +512,29 @@ func (d DomainField) AsRangeLookup() DomainField {
 	return d
 }
 
+// AsDefaultSort makes this field the default List ordering ("asc" or "desc")
+// when no SortBy is supplied. It also marks the field as sortable.
+func (d DomainField) AsDefaultSort(order string) DomainField {
+	d.DefaultSort = order
+	d.Sortable = true
+	return d
+}
+
+// AsShardKey marks this field as the entity's shard key, enabling the
+// generated Sharded{Entity}Service router
+func (d DomainField) AsShardKey() DomainField {
+	d.ShardKey = true
+	return d
+}
+
+// AsAudited records this field's old and new values in the AuditEntry of every
+// Create, Update and Delete of the generated service. Sensitive fields cannot
+// be audited.
+func (d DomainField) AsAudited() DomainField {
+	d.Audited = true
+	return d
+}
+
 // Metadata related methods
 
 // ensureMetadata initializes the Metadata field if nil, returning
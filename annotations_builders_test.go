@@ -706,6 +706,20 @@ func TestDomainConfigDefaults(t *testing.T) {
 	}
 }
 
+func TestDomainConfigDeprecated(t *testing.T) {
+	config := DomainConfig{EntityName: "account"}.Deprecated("use AccountV2").WithSunset("2027-06-30")
+
+	if config.Deprecation != "use AccountV2" {
+		t.Errorf("Deprecation = %q, want %q", config.Deprecation, "use AccountV2")
+	}
+	if config.Sunset != "2027-06-30" {
+		t.Errorf("Sunset = %q, want %q", config.Sunset, "2027-06-30")
+	}
+	if config.EntityName != "account" {
+		t.Errorf("EntityName = %q, want it preserved", config.EntityName)
+	}
+}
+
 // --- Complex builder chaining ---
 
 func TestComplexBuilderChaining(t *testing.T) {
@@ -939,6 +953,45 @@ func TestWithExample(t *testing.T) {
 	}
 }
 
+// --- ShardKey builder ---
+
+func TestAsShardKey(t *testing.T) {
+	field := CreateOnlyField().AsShardKey()
+	if !field.ShardKey {
+		t.Error("AsShardKey() should set ShardKey to true")
+	}
+	if len(field.Scopes) != 3 {
+		t.Errorf("AsShardKey() should retain scopes from CreateOnlyField, got %v", field.Scopes)
+	}
+	if NewDomainField().ShardKey {
+		t.Error("NewDomainField() should not be a shard key")
+	}
+}
+
+func TestAsAudited(t *testing.T) {
+	if !DefaultField().AsAudited().Audited {
+		t.Error("AsAudited() should set Audited to true")
+	}
+	if NewDomainField().Audited {
+		t.Error("NewDomainField() should not be audited")
+	}
+}
+
+func TestAsDefaultSort(t *testing.T) {
+	field := OutputOnlyField().AsDefaultSort("desc")
+	if field.DefaultSort != "desc" {
+		t.Errorf("AsDefaultSort() DefaultSort = %q, want %q", field.DefaultSort, "desc")
+	}
+	if !field.Sortable {
+		t.Error("AsDefaultSort() should mark the field sortable")
+	}
+
+	custom := NewDomainField().AsDefaultSort("asc")
+	if !custom.Sortable || custom.DefaultSort != "asc" {
+		t.Errorf("AsDefaultSort() on empty field = %+v", custom)
+	}
+}
+
 // --- Helper functions for pointer creation in tests ---
 
 func floatPtr(v float64) *float64 { return &v }
@@ -166,6 +166,30 @@ func TestDomainFieldFluentAPI(t *testing.T) {
 		}
 	})
 
+	t.Run("AsFullTextSearchable", func(t *testing.T) {
+		field := NewDomainField().AsFullTextSearchable()
+
+		if !field.FullTextSearchable || !field.Searchable {
+			t.Error("Field should be full-text searchable and searchable")
+		}
+	})
+
+	t.Run("WithSearchAlias", func(t *testing.T) {
+		field := NewDomainField().WithSearchAlias("name")
+
+		if field.SearchAlias != "name" {
+			t.Errorf("SearchAlias = %q, want %q", field.SearchAlias, "name")
+		}
+	})
+
+	t.Run("AsCaseInsensitive", func(t *testing.T) {
+		field := NewDomainField().AsCaseInsensitive()
+
+		if !field.CaseInsensitive {
+			t.Error("Field should be case-insensitive")
+		}
+	})
+
 	t.Run("AsFilterable", func(t *testing.T) {
 		field := NewDomainField().AsFilterable()
 
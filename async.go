@@ -0,0 +1,147 @@
+package entdomain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoJobQueue is returned by the generated CreateAsync and DeleteAsync
+// methods of services without a JobQueue.
+var ErrNoJobQueue = errors.New("no job queue configured")
+
+// Operation is a write enqueued by a generated CreateAsync or DeleteAsync
+// method, for a worker to run later through an OperationHandler.
+type Operation struct {
+	// ID identifies the operation to the caller that enqueued it.
+	ID string `json:"id"`
+	// Resource is the resource name of the entity written, e.g. "user".
+	Resource string `json:"resource"`
+	// Action is ActionCreate or ActionDelete.
+	Action Action `json:"action"`
+	// Payload is the JSON of the create request, or of the ID to delete.
+	Payload json.RawMessage `json:"payload"`
+	// Actor, Tenant and Locale are those of the enqueuing context.
+	Actor  string `json:"actor,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+	Locale string `json:"locale,omitempty"`
+	// EnqueuedAt is when the operation was created.
+	EnqueuedAt time.Time `json:"enqueued_at"`
+}
+
+// NewOperation returns an Operation of action on resource, with a ULID as ID,
+// payload encoded as JSON and the actor, tenant and locale of ctx.
+func NewOperation(ctx context.Context, resource string, action Action, payload any) (*Operation, error) {
+	id, err := NewULID()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s %s operation: %w", resource, action, err)
+	}
+	return &Operation{
+		ID:         id.String(),
+		Resource:   resource,
+		Action:     action,
+		Payload:    data,
+		Actor:      ActorFrom(ctx),
+		Tenant:     TenantFrom(ctx),
+		Locale:     LocaleFrom(ctx),
+		EnqueuedAt: id.Time(),
+	}, nil
+}
+
+// Context returns ctx carrying the actor, tenant and locale of op, and op.ID
+// as idempotency key, so that a create delivered twice to a service with an
+// IdempotencyStore runs once.
+func (op *Operation) Context(ctx context.Context) context.Context {
+	if op.Actor != "" {
+		ctx = WithActor(ctx, op.Actor)
+	}
+	if op.Tenant != "" {
+		ctx = WithTenant(ctx, op.Tenant)
+	}
+	if op.Locale != "" {
+		ctx = WithLocale(ctx, op.Locale)
+	}
+	return WithIdempotencyKey(ctx, op.ID)
+}
+
+// JobQueue carries Operations from generated services to workers, e.g. over
+// a message broker or a database table. Deliveries may repeat: the handlers
+// of generated services tolerate running an operation twice.
+type JobQueue interface {
+	// Enqueue stores op for a worker. Once it returns nil, op must
+	// eventually be delivered.
+	Enqueue(ctx context.Context, op *Operation) error
+}
+
+// Enqueue creates an Operation of action on resource with NewOperation,
+// enqueues it on q and returns its ID. It fails with ErrNoJobQueue when q is
+// nil. Generated CreateAsync and DeleteAsync methods call it.
+func Enqueue(ctx context.Context, q JobQueue, resource string, action Action, payload any) (string, error) {
+	if q == nil {
+		return "", ErrNoJobQueue
+	}
+	op, err := NewOperation(ctx, resource, action, payload)
+	if err != nil {
+		return "", err
+	}
+	if err := q.Enqueue(ctx, op); err != nil {
+		return "", fmt.Errorf("enqueue %s %s operation: %w", resource, action, err)
+	}
+	return op.ID, nil
+}
+
+// OperationHandler runs an Operation taken off a JobQueue. The generated
+// New{Entity}OperationHandler functions return one per entity.
+type OperationHandler func(ctx context.Context, op *Operation) error
+
+// OperationRouter hands each Operation to the OperationHandler of its
+// Resource, so that one worker can serve several entities.
+type OperationRouter map[string]OperationHandler
+
+// Handle runs op with the handler of its resource.
+func (r OperationRouter) Handle(ctx context.Context, op *Operation) error {
+	h, ok := r[op.Resource]
+	if !ok {
+		return fmt.Errorf("no handler for %s operations", op.Resource)
+	}
+	return h(ctx, op)
+}
+
+// MemoryJobQueue is a JobQueue over a channel, for tests and single-process
+// deployments. Operations still in the channel are lost when the process
+// exits.
+type MemoryJobQueue chan *Operation
+
+// Enqueue sends op on q, waiting for room until ctx is done.
+func (q MemoryJobQueue) Enqueue(ctx context.Context, op *Operation) error {
+	select {
+	case q <- op:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Work runs h on each Operation received from q until q is closed or ctx is
+// done, passing the errors of h to onError when it is set.
+func (q MemoryJobQueue) Work(ctx context.Context, h OperationHandler, onError func(op *Operation, err error)) error {
+	for {
+		select {
+		case op, ok := <-q:
+			if !ok {
+				return nil
+			}
+			if err := h(ctx, op); err != nil && onError != nil {
+				onError(op, err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package entdomain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEnqueue(t *testing.T) {
+	ctx := WithLocale(WithTenant(WithActor(context.Background(), "u1"), "t1"), "de")
+	if _, err := Enqueue(ctx, nil, "user", ActionCreate, nil); !errors.Is(err, ErrNoJobQueue) {
+		t.Errorf("nil queue: err = %v, want ErrNoJobQueue", err)
+	}
+
+	q := make(MemoryJobQueue, 1)
+	id, err := Enqueue(ctx, q, "user", ActionCreate, map[string]string{"name": "ann"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := <-q
+	if op.ID != id || op.Resource != "user" || op.Action != ActionCreate || string(op.Payload) != `{"name":"ann"}` {
+		t.Errorf("op = %+v, want create of user %s", op, id)
+	}
+	if _, err := ParseULID(id); err != nil {
+		t.Errorf("operation ID %q is not a ULID: %v", id, err)
+	}
+
+	// Operations survive a trip through JSON, as over a message broker.
+	data, err := json.Marshal(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Operation
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	opCtx := got.Context(context.Background())
+	if ActorFrom(opCtx) != "u1" || TenantFrom(opCtx) != "t1" || LocaleFrom(opCtx) != "de" || IdempotencyKeyFrom(opCtx) != id {
+		t.Errorf("restored context lost the caller: actor %q tenant %q locale %q key %q",
+			ActorFrom(opCtx), TenantFrom(opCtx), LocaleFrom(opCtx), IdempotencyKeyFrom(opCtx))
+	}
+
+	full, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := Enqueue(full, make(MemoryJobQueue), "user", ActionDelete, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("blocked enqueue: err = %v, want context.Canceled", err)
+	}
+}
+
+func TestOperationRouter(t *testing.T) {
+	var ran []string
+	router := OperationRouter{"user": func(_ context.Context, op *Operation) error {
+		ran = append(ran, op.ID)
+		return nil
+	}}
+	q := make(MemoryJobQueue, 2)
+	q <- &Operation{ID: "1", Resource: "user"}
+	q <- &Operation{ID: "2", Resource: "post"}
+	close(q)
+
+	var failed []string
+	if err := q.Work(context.Background(), router.Handle, func(op *Operation, _ error) { failed = append(failed, op.ID) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 1 || ran[0] != "1" || len(failed) != 1 || failed[0] != "2" {
+		t.Errorf("ran %v, failed %v", ran, failed)
+	}
+}
@@ -0,0 +1,71 @@
+package entdomain
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// FieldChange is the change of an audited field by a write. Old is nil for a
+// created entity and New for a deleted one.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// AuditEntry records who wrote an entity and how its audited fields changed.
+type AuditEntry struct {
+	// Resource is the resource name of the entity, e.g. "user".
+	Resource string `json:"resource"`
+	ID       any    `json:"id"`
+	Action   Action `json:"action"`
+	// Actor is the ID given to WithActor, empty when ctx carried none.
+	Actor   string        `json:"actor,omitempty"`
+	Changes []FieldChange `json:"changes,omitempty"`
+	At      time.Time     `json:"at"`
+}
+
+// NewAuditEntry returns the entry of an action on the entity with the given
+// ID, by the actor of ctx, at the current time.
+func NewAuditEntry(ctx context.Context, resource string, action Action, id any) AuditEntry {
+	return AuditEntry{Resource: resource, ID: id, Action: action, Actor: ActorFrom(ctx), At: time.Now()}
+}
+
+// AuditSink stores audit entries. Generated services with AsAudited fields
+// record an entry with their AuditSink for every Create, Update and Delete,
+// after the write and inside its transaction, if any: a sink writing to the
+// same database is rolled back with the write.
+type AuditSink interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
+
+// AuditSinkFunc adapts an ordinary function to the AuditSink interface.
+type AuditSinkFunc func(ctx context.Context, entry AuditEntry) error
+
+// Record calls f(ctx, entry).
+func (f AuditSinkFunc) Record(ctx context.Context, entry AuditEntry) error {
+	return f(ctx, entry)
+}
+
+// DiffFields returns the changes between old and new, the values of fields
+// before and after a write, in the order of fields. A nil old or new stands
+// for an entity that did not exist, and every field then counts as changed.
+// Values are compared with reflect.DeepEqual.
+func DiffFields(fields []string, old, new []any) []FieldChange {
+	var changes []FieldChange
+	for i, field := range fields {
+		var o, n any
+		if old != nil {
+			o = old[i]
+		}
+		if new != nil {
+			n = new[i]
+		}
+		if old != nil && new != nil && reflect.DeepEqual(o, n) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, Old: o, New: n})
+	}
+	return changes
+}
@@ -0,0 +1,28 @@
+package entdomain
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	ctx := WithActor(context.Background(), "u1")
+	entry := NewAuditEntry(ctx, "user", ActionUpdate, 7)
+	if entry.Actor != "u1" || entry.Resource != "user" || entry.ID != 7 || entry.At.IsZero() {
+		t.Errorf("NewAuditEntry() = %+v", entry)
+	}
+}
+
+func TestDiffFields(t *testing.T) {
+	fields := []string{"name", "tags", "age"}
+	got := DiffFields(fields, []any{"a", []string{"x"}, 1}, []any{"b", []string{"x"}, 1})
+	if len(got) != 1 || got[0] != (FieldChange{Field: "name", Old: "a", New: "b"}) {
+		t.Errorf("update diff = %+v", got)
+	}
+	if got := DiffFields(fields, nil, []any{"a", nil, 1}); len(got) != 3 || got[2].Old != nil || got[2].New != 1 {
+		t.Errorf("create diff = %+v", got)
+	}
+	if got := DiffFields(fields, []any{"a", nil, 1}, nil); len(got) != 3 || got[0].Old != "a" || got[0].New != nil {
+		t.Errorf("delete diff = %+v", got)
+	}
+}
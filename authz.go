@@ -0,0 +1,62 @@
+package entdomain
+
+import (
+	"context"
+	"fmt"
+)
+
+// Resource identifies the target of an authorization check.
+type Resource struct {
+	// Type is the resource name, e.g. "user". Generated services use the
+	// same snake_case name as the generated permission constants.
+	Type string
+
+	// ID is the target entity's identifier, or nil for collection-level
+	// actions such as create and list.
+	ID any
+}
+
+// Permission returns the Permission required to perform action on the resource.
+func (r Resource) Permission(action Action) Permission {
+	return NewPermission(r.Type, action)
+}
+
+// Authorizer decides whether the caller in ctx may perform action on resource.
+// Implementations return nil to allow the operation, or an error (preferably
+// wrapping ErrForbidden) to deny it. Generated services call Authorize before
+// running hooks or touching the database.
+type Authorizer interface {
+	Authorize(ctx context.Context, action Action, resource Resource) error
+}
+
+// AuthorizerFunc adapts an ordinary function to the Authorizer interface.
+type AuthorizerFunc func(ctx context.Context, action Action, resource Resource) error
+
+// Authorize calls f(ctx, action, resource).
+func (f AuthorizerFunc) Authorize(ctx context.Context, action Action, resource Resource) error {
+	return f(ctx, action, resource)
+}
+
+// AuthorizationMode controls what happens when no Authorizer is configured.
+type AuthorizationMode int
+
+const (
+	// AuthorizationPermissive allows every operation when no Authorizer is configured.
+	AuthorizationPermissive AuthorizationMode = iota
+
+	// AuthorizationStrict denies every operation with ErrForbidden when no
+	// Authorizer is configured, so a missing wiring fails closed.
+	AuthorizationStrict
+)
+
+// Authorize checks action on resource against authz. A nil authz is treated
+// according to mode: permissive allows, strict denies with ErrForbidden.
+func Authorize(ctx context.Context, authz Authorizer, mode AuthorizationMode, action Action, resource Resource) error {
+	if authz == nil {
+		if mode == AuthorizationStrict {
+			return fmt.Errorf("%w: no authorizer configured for %s", ErrForbidden, resource.Permission(action))
+		}
+		return nil
+	}
+	return authz.Authorize(ctx, action, resource)
+}
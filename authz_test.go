@@ -0,0 +1,59 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestResource_Permission(t *testing.T) {
+	r := Resource{Type: "user", ID: 42}
+	if got := r.Permission(ActionDelete); got != "user.delete" {
+		t.Errorf("Permission() = %q, want %q", got, "user.delete")
+	}
+}
+
+func TestAuthorize_NilAuthorizer(t *testing.T) {
+	ctx := context.Background()
+	res := Resource{Type: "user"}
+
+	if err := Authorize(ctx, nil, AuthorizationPermissive, ActionCreate, res); err != nil {
+		t.Errorf("permissive mode with nil authorizer: got %v, want nil", err)
+	}
+
+	err := Authorize(ctx, nil, AuthorizationStrict, ActionCreate, res)
+	if !IsForbidden(err) {
+		t.Fatalf("strict mode with nil authorizer: got %v, want ErrForbidden", err)
+	}
+	assertContains(t, err.Error(), "user.create")
+}
+
+func TestAuthorize_DelegatesToAuthorizer(t *testing.T) {
+	ctx := context.Background()
+
+	var gotAction Action
+	var gotResource Resource
+	authz := AuthorizerFunc(func(_ context.Context, action Action, resource Resource) error {
+		gotAction, gotResource = action, resource
+		if action == ActionDelete {
+			return fmt.Errorf("%w: admins only", ErrForbidden)
+		}
+		return nil
+	})
+
+	// Both modes delegate when an authorizer is configured
+	for _, mode := range []AuthorizationMode{AuthorizationPermissive, AuthorizationStrict} {
+		if err := Authorize(ctx, authz, mode, ActionRead, Resource{Type: "user", ID: 7}); err != nil {
+			t.Errorf("mode %d: ActionRead got %v, want nil", mode, err)
+		}
+		if gotAction != ActionRead || gotResource.Type != "user" || gotResource.ID != 7 {
+			t.Errorf("mode %d: authorizer received (%q, %+v)", mode, gotAction, gotResource)
+		}
+
+		err := Authorize(ctx, authz, mode, ActionDelete, Resource{Type: "user", ID: 7})
+		if !errors.Is(err, ErrForbidden) {
+			t.Errorf("mode %d: ActionDelete got %v, want ErrForbidden", mode, err)
+		}
+	}
+}
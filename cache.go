@@ -0,0 +1,209 @@
+package entdomain
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cache stores entities read by generated cached services under string keys.
+// Values are the entity pointers themselves, so implementations keep them in
+// process, typically in an LRU or TTL map. Callers must not modify entities
+// they read from a Cache.
+type Cache interface {
+	// Get returns the value stored under key and whether there was one.
+	Get(ctx context.Context, key string) (any, bool, error)
+	// Set stores value under key for ttl; zero keeps it until deleted.
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	// Delete removes keys, ignoring those not stored.
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// CacheLoad returns the value of type T stored under key in cache, or calls
+// load and stores its result for ttl. Errors of load are returned and not
+// cached. A stored value of another type counts as missing. A DecodingCache
+// is read with Load into a T. Hits and misses are recorded as MetadataCache
+// of a CollectResult running with ctx.
+func CacheLoad[T any](ctx context.Context, cache Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	if dc, ok := cache.(DecodingCache); ok {
+		var t T
+		found, err := dc.Load(ctx, key, &t)
+		if err != nil {
+			return t, err
+		}
+		if found {
+			SetMetadata(ctx, MetadataCache, "hit")
+			return t, nil
+		}
+	} else {
+		v, ok, err := cache.Get(ctx, key)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		if t, isT := v.(T); ok && isT {
+			SetMetadata(ctx, MetadataCache, "hit")
+			return t, nil
+		}
+	}
+	SetMetadata(ctx, MetadataCache, "miss")
+	t, err := load(ctx)
+	if err != nil {
+		return t, err
+	}
+	if err := cache.Set(ctx, key, t, ttl); err != nil {
+		var zero T
+		return zero, err
+	}
+	return t, nil
+}
+
+// MemoryCache is a Cache held in a map, for single-process deployments and
+// tests. Expired entries are dropped when read. With MaxEntries set, the
+// least recently used entries are evicted beyond it. The zero value is ready
+// to use.
+type MemoryCache struct {
+	// MaxEntries bounds the number of entries; zero means no bound.
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     list.List // of *memoryEntry, most recently used first
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	key     string
+	value   any
+	expires time.Time
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(_ context.Context, key string) (any, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := el.Value.(*memoryEntry)
+	if !e.expires.IsZero() && !c.clock().Before(e.expires) {
+		c.remove(el)
+		return nil, false, nil
+	}
+	c.lru.MoveToFront(el)
+	return e.value, true, nil
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(_ context.Context, key string, value any, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+	}
+	e := &memoryEntry{key: key, value: value}
+	if ttl > 0 {
+		e.expires = c.clock().Add(ttl)
+	}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+	} else {
+		c.entries[key] = c.lru.PushFront(e)
+	}
+	for c.MaxEntries > 0 && c.lru.Len() > c.MaxEntries {
+		c.remove(c.lru.Back())
+	}
+	return nil
+}
+
+// Delete implements Cache.
+func (c *MemoryCache) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		if el, ok := c.entries[k]; ok {
+			c.remove(el)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*memoryEntry).key)
+}
+
+func (c *MemoryCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// DecodingCache is a Cache storing encoded values, such as one kept in Redis.
+// Its Get cannot know the Go type of a value, so CacheLoad reads it with
+// Load, which decodes into the type asked for.
+type DecodingCache interface {
+	Cache
+	// Load decodes the value stored under key into dst, a pointer, and
+	// reports whether there was one. A value that does not decode into dst
+	// counts as missing.
+	Load(ctx context.Context, key string, dst any) (bool, error)
+}
+
+// ByteStore is the byte-level store a remote cache is built on. A Redis
+// client adapts to it with GET, SET with an expiry, and DEL.
+type ByteStore interface {
+	// Get returns the bytes stored under key and whether there were any.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl; zero keeps it until deleted.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys, ignoring those not stored.
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// StoreCache is a DecodingCache keeping JSON-encoded values in Store, so
+// processes sharing it share their cached entities. Fields excluded from
+// JSON, like ent's Sensitive fields, are not cached: entities read back lack
+// them, and lack the ent client too, which generated cached services attach
+// again before returning them. Get returns the encoded json.RawMessage.
+type StoreCache struct {
+	Store ByteStore
+}
+
+// Get implements Cache.
+func (c StoreCache) Get(ctx context.Context, key string) (any, bool, error) {
+	data, ok, err := c.Store.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return json.RawMessage(data), true, nil
+}
+
+// Load implements DecodingCache.
+func (c StoreCache) Load(ctx context.Context, key string, dst any) (bool, error) {
+	data, ok, err := c.Store.Get(ctx, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	return json.Unmarshal(data, dst) == nil, nil
+}
+
+// Set implements Cache.
+func (c StoreCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cache %s: %w", key, err)
+	}
+	return c.Store.Set(ctx, key, data, ttl)
+}
+
+// Delete implements Cache.
+func (c StoreCache) Delete(ctx context.Context, keys ...string) error {
+	return c.Store.Delete(ctx, keys...)
+}
@@ -0,0 +1,148 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	c := &MemoryCache{now: func() time.Time { return now }}
+
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Fatal("empty cache should miss")
+	}
+	_ = c.Set(ctx, "a", 1, time.Minute)
+	_ = c.Set(ctx, "b", 2, 0)
+	if v, ok, _ := c.Get(ctx, "a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %v, %v", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("a should have expired")
+	}
+	if _, ok, _ := c.Get(ctx, "b"); !ok {
+		t.Error("b has no ttl and should not expire")
+	}
+	_ = c.Delete(ctx, "b", "missing")
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("b should be deleted")
+	}
+}
+
+func TestCacheLoad(t *testing.T) {
+	ctx := context.Background()
+	c := &MemoryCache{}
+	loads := 0
+	load := func(context.Context) (*int, error) {
+		loads++
+		v := 7
+		return &v, nil
+	}
+
+	for range 2 {
+		v, err := CacheLoad(ctx, c, "k", time.Minute, load)
+		if err != nil || *v != 7 {
+			t.Fatalf("CacheLoad() = %v, %v", v, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loaded %d times, want 1", loads)
+	}
+	res, _ := CollectResult(ctx, func(ctx context.Context) (*int, error) {
+		return CacheLoad(ctx, c, "k", time.Minute, load)
+	})
+	if res.Metadata[MetadataCache] != "hit" {
+		t.Errorf("cache metadata = %q, want hit", res.Metadata[MetadataCache])
+	}
+
+	_ = c.Set(ctx, "k", "other type", 0)
+	if _, _ = CacheLoad(ctx, c, "k", time.Minute, load); loads != 2 {
+		t.Error("a value of another type should be reloaded")
+	}
+
+	fail := errors.New("fail")
+	_, err := CacheLoad(ctx, c, "f", time.Minute, func(context.Context) (*int, error) { return nil, fail })
+	if !errors.Is(err, fail) {
+		t.Errorf("err = %v, want the load error", err)
+	}
+	if _, ok, _ := c.Get(ctx, "f"); ok {
+		t.Error("failed loads should not be cached")
+	}
+}
+
+func TestMemoryCacheMaxEntries(t *testing.T) {
+	ctx := context.Background()
+	c := &MemoryCache{MaxEntries: 2}
+	_ = c.Set(ctx, "a", 1, 0)
+	_ = c.Set(ctx, "b", 2, 0)
+	_, _, _ = c.Get(ctx, "a")
+	_ = c.Set(ctx, "c", 3, 0)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("b was least recently used and should be evicted")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok, _ := c.Get(ctx, k); !ok {
+			t.Errorf("%s should be kept", k)
+		}
+	}
+	_ = c.Set(ctx, "a", 4, 0)
+	if v, _, _ := c.Get(ctx, "a"); v != 4 || len(c.entries) != 2 {
+		t.Errorf("overwriting a: got %v with %d entries", v, len(c.entries))
+	}
+}
+
+type memoryStore map[string][]byte
+
+func (s memoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	b, ok := s[key]
+	return b, ok, nil
+}
+
+func (s memoryStore) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	s[key] = value
+	return nil
+}
+
+func (s memoryStore) Delete(_ context.Context, keys ...string) error {
+	for _, k := range keys {
+		delete(s, k)
+	}
+	return nil
+}
+
+func TestStoreCache(t *testing.T) {
+	ctx := context.Background()
+	type entity struct{ Name string }
+	store := memoryStore{}
+	c := StoreCache{Store: store}
+	loads := 0
+	load := func(context.Context) (*entity, error) {
+		loads++
+		return &entity{Name: "a"}, nil
+	}
+
+	for range 2 {
+		v, err := CacheLoad(ctx, c, "k", time.Minute, load)
+		if err != nil || v.Name != "a" {
+			t.Fatalf("CacheLoad() = %v, %v", v, err)
+		}
+	}
+	if loads != 1 || string(store["k"]) != `{"Name":"a"}` {
+		t.Errorf("loaded %d times, stored %s", loads, store["k"])
+	}
+
+	store["k"] = []byte("[1]")
+	if _, _ = CacheLoad(ctx, c, "k", time.Minute, load); loads != 2 {
+		t.Error("a value that does not decode should be reloaded")
+	}
+	_ = c.Delete(ctx, "k")
+	if _, ok, _ := c.Get(ctx, "k"); ok {
+		t.Error("k should be deleted")
+	}
+}
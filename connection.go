@@ -0,0 +1,50 @@
+package entdomain
+
+import "fmt"
+
+// ConnectionArgs are the pagination arguments of a Relay connection field:
+// First/After page forward, Last/Before backward. Generated Connection methods
+// read them into the cursor fields of a SearchRequest.
+type ConnectionArgs struct {
+	First  *int    `json:"first,omitempty"`
+	After  *string `json:"after,omitempty"`
+	Last   *int    `json:"last,omitempty"`
+	Before *string `json:"before,omitempty"`
+}
+
+// Apply sets the Size, Cursor and Direction of req from the arguments and
+// clears its Page. Combining forward and backward arguments is rejected, as
+// the Relay spec discourages it; so are non-positive counts. Like
+// ListRequest.Validate, it returns plain errors.
+func (a ConnectionArgs) Apply(req *ListRequest) error {
+	forward := a.First != nil || a.After != nil
+	backward := a.Last != nil || a.Before != nil
+	if forward && backward {
+		return fmt.Errorf("first/after and last/before cannot be combined")
+	}
+	for _, n := range []*int{a.First, a.Last} {
+		if n != nil && *n <= 0 {
+			return fmt.Errorf("first and last must be positive")
+		}
+	}
+
+	req.Page = 0
+	req.Cursor = ""
+	req.Direction = DirectionAfter
+	if backward {
+		req.Direction = DirectionBefore
+	}
+	switch {
+	case a.First != nil:
+		req.Size = *a.First
+	case a.Last != nil:
+		req.Size = *a.Last
+	}
+	switch {
+	case a.After != nil:
+		req.Cursor = *a.After
+	case a.Before != nil:
+		req.Cursor = *a.Before
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package entdomain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConnectionArgsApply(t *testing.T) {
+	n, zero, cursor := 5, 0, "c"
+	tests := []struct {
+		name    string
+		args    ConnectionArgs
+		want    ListRequest
+		wantErr bool
+	}{
+		{"empty", ConnectionArgs{}, ListRequest{Size: 20, Direction: DirectionAfter}, false},
+		{"first", ConnectionArgs{First: &n}, ListRequest{Size: 5, Direction: DirectionAfter}, false},
+		{"first after", ConnectionArgs{First: &n, After: &cursor}, ListRequest{Size: 5, Cursor: "c", Direction: DirectionAfter}, false},
+		{"last", ConnectionArgs{Last: &n}, ListRequest{Size: 5, Direction: DirectionBefore}, false},
+		{"before", ConnectionArgs{Before: &cursor}, ListRequest{Size: 20, Cursor: "c", Direction: DirectionBefore}, false},
+		{"first and last", ConnectionArgs{First: &n, Last: &n}, ListRequest{}, true},
+		{"after and before", ConnectionArgs{After: &cursor, Before: &cursor}, ListRequest{}, true},
+		{"zero first", ConnectionArgs{First: &zero}, ListRequest{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ListRequest{Page: 3, Size: 20, Cursor: "old"}
+			err := tt.args.Apply(&req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(req, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", req, tt.want)
+			}
+		})
+	}
+}
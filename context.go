@@ -0,0 +1,89 @@
+package entdomain
+
+import (
+	"context"
+	"fmt"
+)
+
+// Request context helpers. Middleware stores who is calling, for which
+// tenant and in which language; generated services read them back to record
+// the actor of audit entries, to scope rows and caches by tenant (see
+// TenantAccess and TenantFrom) and to localize validation messages (see
+// ValidationMessages).
+
+type (
+	actorKey  struct{}
+	tenantKey struct{}
+	localeKey struct{}
+)
+
+// WithActor returns a copy of ctx carrying the ID of the user or system
+// performing its writes, which their audit entries record.
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+// ActorFromContext returns the actor ID stored by WithActor.
+// The boolean is false when no (or an empty) actor is present.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actorID, ok := ctx.Value(actorKey{}).(string)
+	return actorID, ok && actorID != ""
+}
+
+// ActorFrom returns the actor ID stored by WithActor, or "" when none is.
+func ActorFrom(ctx context.Context) string {
+	actorID, _ := ActorFromContext(ctx)
+	return actorID
+}
+
+// WithTenant returns a copy of ctx carrying the given tenant ID.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ID stored by WithTenant.
+// The boolean is false when no (or an empty) tenant is present.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// TenantFrom returns the tenant ID stored by WithTenant, or "" when none is.
+// As the Scope of a generated cached service, it keeps the entries of tenants
+// sharing a Cache apart.
+func TenantFrom(ctx context.Context) string {
+	tenantID, _ := TenantFromContext(ctx)
+	return tenantID
+}
+
+// WithLocale returns a copy of ctx carrying the caller's locale, a BCP 47
+// language tag such as "de" or "pt-BR", e.g. from the Accept-Language
+// header.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale stored by WithLocale.
+// The boolean is false when no (or an empty) locale is present.
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	locale, ok := ctx.Value(localeKey{}).(string)
+	return locale, ok && locale != ""
+}
+
+// LocaleFrom returns the locale stored by WithLocale, or "" when none is.
+func LocaleFrom(ctx context.Context) string {
+	locale, _ := LocaleFromContext(ctx)
+	return locale
+}
+
+// TenantAccess is an AccessPolicy scoping entities declared with
+// DomainConfig.WithOwnerField on a string tenant column, e.g.
+// WithOwnerField("tenant_id"), to the tenant of ctx. Calls without a tenant
+// are denied with an error matching both ErrForbidden and ErrNoTenant.
+var TenantAccess AccessPolicy = AccessPolicyFunc(func(ctx context.Context, resource string) (Access, error) {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return Access{}, fmt.Errorf("%w: %s: %w", ErrForbidden, resource, ErrNoTenant)
+	}
+	return Access{Owner: tenantID}, nil
+})
@@ -0,0 +1,70 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestActorFromContext(t *testing.T) {
+	if _, ok := ActorFromContext(context.Background()); ok {
+		t.Error("empty context should carry no actor")
+	}
+	if _, ok := ActorFromContext(WithActor(context.Background(), "")); ok {
+		t.Error("empty actor should be treated as absent")
+	}
+	ctx := WithActor(context.Background(), "u1")
+	if got, ok := ActorFromContext(ctx); !ok || got != "u1" {
+		t.Errorf("ActorFromContext() = %q, %v", got, ok)
+	}
+	if got := ActorFrom(ctx); got != "u1" {
+		t.Errorf("ActorFrom() = %q, want u1", got)
+	}
+	if got := ActorFrom(context.Background()); got != "" {
+		t.Errorf("ActorFrom() of an empty context = %q", got)
+	}
+}
+
+func TestTenantFromContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := TenantFromContext(ctx); ok {
+		t.Error("empty context should carry no tenant")
+	}
+
+	ctx = WithTenant(ctx, "acme")
+	got, ok := TenantFromContext(ctx)
+	if !ok || got != "acme" {
+		t.Errorf("TenantFromContext() = (%q, %v), want (acme, true)", got, ok)
+	}
+	if got := TenantFrom(ctx); got != "acme" {
+		t.Errorf("TenantFrom() = %q, want acme", got)
+	}
+
+	if _, ok := TenantFromContext(WithTenant(ctx, "")); ok {
+		t.Error("empty tenant ID should be reported as absent")
+	}
+}
+
+func TestLocaleFromContext(t *testing.T) {
+	if _, ok := LocaleFromContext(context.Background()); ok {
+		t.Error("empty context should carry no locale")
+	}
+	ctx := WithLocale(context.Background(), "de-CH")
+	if got, ok := LocaleFromContext(ctx); !ok || got != "de-CH" {
+		t.Errorf("LocaleFromContext() = %q, %v", got, ok)
+	}
+	if got := LocaleFrom(WithLocale(ctx, "")); got != "" {
+		t.Errorf("LocaleFrom() of an empty locale = %q", got)
+	}
+}
+
+func TestTenantAccess(t *testing.T) {
+	access, err := TenantAccess.Access(WithTenant(context.Background(), "acme"), "post")
+	if err != nil || access.Owner != "acme" || access.All {
+		t.Errorf("Access() = %+v, %v", access, err)
+	}
+	_, err = ResolveAccess(context.Background(), TenantAccess, AuthorizationPermissive, "post")
+	if !IsForbidden(err) || !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Access() without a tenant err = %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package entdomain
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// CountMode selects how List and Search compute the Total of a page.
+type CountMode string
+
+const (
+	// CountExact counts the matching rows with COUNT(*). It is the default.
+	CountExact CountMode = "exact"
+
+	// CountEstimated reads the row estimate of the table from the database
+	// statistics when no predicate narrows the query, not even row ownership
+	// or soft delete, and the service has a RowEstimator. It counts exactly
+	// otherwise.
+	CountEstimated CountMode = "estimated"
+
+	// CountNone skips counting; Total is TotalUnknown.
+	CountNone CountMode = "none"
+)
+
+// TotalUnknown is the Total of pages read with CountNone.
+const TotalUnknown = -1
+
+// RowEstimator estimates the number of rows of a table from database
+// statistics, which is far cheaper than COUNT(*) on large tables. Estimates
+// cover the whole table, including rows that interceptors such as soft delete
+// or tenant scoping hide, and lag behind recent writes.
+type RowEstimator interface {
+	// EstimateRows returns the estimated row count of table. ok is false
+	// when the database has no statistics for it yet.
+	EstimateRows(ctx context.Context, table string) (rows int, ok bool, err error)
+}
+
+// RowEstimatorFunc adapts an ordinary function to RowEstimator.
+type RowEstimatorFunc func(ctx context.Context, table string) (int, bool, error)
+
+// EstimateRows implements RowEstimator.
+func (f RowEstimatorFunc) EstimateRows(ctx context.Context, table string) (int, bool, error) {
+	return f(ctx, table)
+}
+
+// PostgresRowEstimator implements RowEstimator with pg_class.reltuples, which
+// VACUUM and ANALYZE keep up to date.
+type PostgresRowEstimator struct {
+	DB *sql.DB
+}
+
+// EstimateRows implements RowEstimator.
+func (e PostgresRowEstimator) EstimateRows(ctx context.Context, table string) (int, bool, error) {
+	var rows sql.NullInt64
+	err := e.DB.QueryRowContext(ctx, "SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass($1)", table).Scan(&rows)
+	return estimate(table, rows, err)
+}
+
+// MySQLRowEstimator implements RowEstimator with the TABLE_ROWS statistic of
+// information_schema, for tables in the current database.
+type MySQLRowEstimator struct {
+	DB *sql.DB
+}
+
+// EstimateRows implements RowEstimator.
+func (e MySQLRowEstimator) EstimateRows(ctx context.Context, table string) (int, bool, error) {
+	var rows sql.NullInt64
+	err := e.DB.QueryRowContext(ctx, "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", table).Scan(&rows)
+	return estimate(table, rows, err)
+}
+
+// estimate converts the result of a statistics query. Postgres reports -1
+// for tables that were never analyzed.
+func estimate(table string, rows sql.NullInt64, err error) (int, bool, error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("estimate rows of %s: %w", table, err)
+	case !rows.Valid || rows.Int64 < 0:
+		return 0, false, nil
+	}
+	return int(rows.Int64), true, nil
+}
@@ -0,0 +1,53 @@
+package entdomain
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// Compile-time checks that the estimators satisfy RowEstimator.
+var (
+	_ RowEstimator = PostgresRowEstimator{}
+	_ RowEstimator = MySQLRowEstimator{}
+	_ RowEstimator = RowEstimatorFunc(nil)
+)
+
+func TestEstimate(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name    string
+		rows    sql.NullInt64
+		err     error
+		want    int
+		wantOK  bool
+		wantErr error
+	}{
+		{"estimate", sql.NullInt64{Int64: 1200, Valid: true}, nil, 1200, true, nil},
+		{"empty table", sql.NullInt64{Int64: 0, Valid: true}, nil, 0, true, nil},
+		{"never analyzed", sql.NullInt64{Int64: -1, Valid: true}, nil, 0, false, nil},
+		{"null statistic", sql.NullInt64{}, nil, 0, false, nil},
+		{"unknown table", sql.NullInt64{}, sql.ErrNoRows, 0, false, nil},
+		{"query error", sql.NullInt64{}, boom, 0, false, boom},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := estimate("users", tt.rows, tt.err)
+			if got != tt.want || ok != tt.wantOK || !errors.Is(err, tt.wantErr) {
+				t.Errorf("estimate() = %d, %v, %v, want %d, %v, %v", got, ok, err, tt.want, tt.wantOK, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRowEstimatorFunc(t *testing.T) {
+	var table string
+	e := RowEstimatorFunc(func(_ context.Context, tbl string) (int, bool, error) {
+		table = tbl
+		return 42, true, nil
+	})
+	if rows, ok, err := e.EstimateRows(context.Background(), "users"); rows != 42 || !ok || err != nil || table != "users" {
+		t.Errorf("EstimateRows = %d, %v, %v (table %q)", rows, ok, err, table)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+
+	"entgo.io/ent/dialect/sql"
 )
 
 // Cursor holds the keyset pagination position. It encodes the sort field
@@ -17,6 +19,23 @@ type Cursor struct {
 	// Value is the sort field value of the last row. Nil when sorting
 	// by ID only (no secondary sort field).
 	Value any `json:"value,omitempty"`
+
+	// Values holds the sort field values of the last row, in sort order,
+	// when sorting by more than one field before the ID. Value is unset
+	// then.
+	Values []any `json:"values,omitempty"`
+}
+
+// SortValues returns the sort field values of the cursor position: Values,
+// or Value alone, or nil when sorting by ID only.
+func (c *Cursor) SortValues() []any {
+	if len(c.Values) > 0 {
+		return c.Values
+	}
+	if c.Value != nil {
+		return []any{c.Value}
+	}
+	return nil
 }
 
 // PageInfo holds cursor-based pagination metadata returned alongside
@@ -25,9 +44,17 @@ type PageInfo struct {
 	// HasNextPage indicates whether more results exist beyond this page.
 	HasNextPage bool `json:"hasNextPage"`
 
+	// HasPreviousPage indicates whether results exist before this page.
+	HasPreviousPage bool `json:"hasPreviousPage"`
+
+	// StartCursor is the opaque cursor string pointing to the first item
+	// in the current page. Pass it as Cursor with Direction "before" to
+	// fetch the previous page.
+	StartCursor string `json:"startCursor,omitempty"`
+
 	// EndCursor is the opaque cursor string pointing to the last item
-	// in the current page. Pass this as ListRequest.Cursor to fetch
-	// the next page.
+	// in the current page. Pass this as ListRequest.Cursor (or
+	// SearchRequest.Cursor) to fetch the next page.
 	EndCursor string `json:"endCursor,omitempty"`
 }
 
@@ -65,6 +92,9 @@ func DecodeCursor(s string) (*Cursor, error) {
 	// Normalize float64 → int64 for JSON-unmarshaled numbers
 	c.ID = normalizeJSONNumber(c.ID)
 	c.Value = normalizeJSONNumber(c.Value)
+	for i, v := range c.Values {
+		c.Values[i] = normalizeJSONNumber(v)
+	}
 	return &c, nil
 }
 
@@ -76,3 +106,68 @@ func normalizeJSONNumber(v any) any {
 	}
 	return v
 }
+
+// CursorValue converts a value decoded by DecodeCursor back to T, the Go type
+// of the column it came from. The JSON round trip of the cursor turns times
+// and UUIDs into strings and integers into int64, which would otherwise
+// compare wrongly against the column.
+func CursorValue[T any](v any) (T, error) {
+	var out T
+	data, err := json.Marshal(v)
+	if err != nil {
+		return out, fmt.Errorf("%w: invalid cursor value: %v", ErrValidation, err)
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("%w: invalid cursor value: %v", ErrValidation, err)
+	}
+	return out, nil
+}
+
+// KeysetPredicate returns the selector predicate for the rows after the
+// cursor position (value, id) in ORDER BY column, idColumn, both ascending or
+// both descending: column > value OR (column = value AND idColumn > id).
+// When column is idColumn, only the ID is compared.
+func KeysetPredicate(column string, value any, idColumn string, id any, desc bool) func(*sql.Selector) {
+	if column == idColumn {
+		return MultiKeysetPredicate(KeysetColumn{Column: idColumn, Value: id, Desc: desc})
+	}
+	return MultiKeysetPredicate(
+		KeysetColumn{Column: column, Value: value, Desc: desc},
+		KeysetColumn{Column: idColumn, Value: id, Desc: desc},
+	)
+}
+
+// KeysetColumn is one column of a keyset position: its name, the value of the
+// cursor row and the sort direction.
+type KeysetColumn struct {
+	Column string
+	Value  any
+	Desc   bool
+}
+
+// MultiKeysetPredicate returns the selector predicate for the rows after the
+// cursor position in ORDER BY keys, each column in its own direction:
+// k1 > v1 OR (k1 = v1 AND (k2 > v2 OR (k2 = v2 AND ...))), with < for
+// descending columns. The last key must be unique, normally the ID.
+func MultiKeysetPredicate(keys ...KeysetColumn) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		if len(keys) > 0 {
+			s.Where(keysetAfter(s, keys))
+		}
+	}
+}
+
+func keysetAfter(s *sql.Selector, keys []KeysetColumn) *sql.Predicate {
+	k := keys[0]
+	after := sql.GT
+	if k.Desc {
+		after = sql.LT
+	}
+	if len(keys) == 1 {
+		return after(s.C(k.Column), k.Value)
+	}
+	return sql.Or(
+		after(s.C(k.Column), k.Value),
+		sql.And(sql.EQ(s.C(k.Column), k.Value), keysetAfter(s, keys[1:])),
+	)
+}
@@ -1,7 +1,14 @@
 package entdomain
 
 import (
+	"encoding/base64"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
 )
 
 func TestEncodeDecode_IDOnly(t *testing.T) {
@@ -133,3 +140,150 @@ func TestPageInfo_Defaults(t *testing.T) {
 		t.Error("default EndCursor should be empty")
 	}
 }
+
+// FuzzDecodeCursor checks that DecodeCursor never panics on arbitrary input
+// and that every cursor it accepts survives an encode/decode round trip.
+func FuzzDecodeCursor(f *testing.F) {
+	for _, c := range []*Cursor{
+		{ID: int64(42)},
+		{ID: "abc", Value: "2024-01-01T00:00:00Z"},
+		{ID: "550e8400-e29b-41d4-a716-446655440000", Value: 3.5},
+	} {
+		s, err := EncodeCursor(c)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(s)
+	}
+	f.Add("")
+	f.Add("!!!")
+	f.Add(base64.RawURLEncoding.EncodeToString([]byte(`{"id":null}`)))
+	f.Add(base64.RawURLEncoding.EncodeToString([]byte(`{"id":1e400}`)))
+	f.Add(base64.RawURLEncoding.EncodeToString([]byte(`{"id":{"nested":[1,2]},"value":-0}`)))
+
+	f.Fuzz(func(t *testing.T, s string) {
+		c, err := DecodeCursor(s)
+		if err != nil {
+			return
+		}
+		if c.ID == nil {
+			t.Fatalf("DecodeCursor(%q) accepted a cursor without ID", s)
+		}
+
+		encoded, err := EncodeCursor(c)
+		if err != nil {
+			t.Fatalf("EncodeCursor(%#v) error = %v", c, err)
+		}
+		again, err := DecodeCursor(encoded)
+		if err != nil {
+			t.Fatalf("DecodeCursor(EncodeCursor(%#v)) error = %v", c, err)
+		}
+		if !reflect.DeepEqual(c, again) {
+			t.Errorf("round trip changed cursor: %#v -> %#v", c, again)
+		}
+	})
+}
+
+func TestCursorValue(t *testing.T) {
+	at := time.Date(2024, 1, 10, 9, 30, 0, 0, time.UTC)
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	encoded, err := EncodeCursor(&Cursor{ID: u, Value: at})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := DecodeCursor(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := CursorValue[uuid.UUID](c.ID); err != nil || id != u {
+		t.Errorf("CursorValue[uuid.UUID]() = %v, %v, want %v", id, err, u)
+	}
+	if v, err := CursorValue[time.Time](c.Value); err != nil || !v.Equal(at) {
+		t.Errorf("CursorValue[time.Time]() = %v, %v, want %v", v, err, at)
+	}
+	if v, err := CursorValue[int](int64(7)); err != nil || v != 7 {
+		t.Errorf("CursorValue[int]() = %v, %v, want 7", v, err)
+	}
+	if _, err := CursorValue[int]("seven"); !errors.Is(err, ErrValidation) {
+		t.Errorf("CursorValue[int](string) err = %v, want ErrValidation", err)
+	}
+}
+
+func TestKeysetPredicate(t *testing.T) {
+	tests := []struct {
+		name      string
+		column    string
+		desc      bool
+		wantQuery string
+		wantArgs  []any
+	}{
+		{"id ascending", "id", false, "SELECT * FROM `users` WHERE `users`.`id` > ?", []any{int64(9)}},
+		{"id descending", "id", true, "SELECT * FROM `users` WHERE `users`.`id` < ?", []any{int64(9)}},
+		{
+			"column ascending", "name", false,
+			"SELECT * FROM `users` WHERE `users`.`name` > ? OR (`users`.`name` = ? AND `users`.`id` > ?)",
+			[]any{"bob", "bob", int64(9)},
+		},
+		{
+			"column descending", "name", true,
+			"SELECT * FROM `users` WHERE `users`.`name` < ? OR (`users`.`name` = ? AND `users`.`id` < ?)",
+			[]any{"bob", "bob", int64(9)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sql.Select("*").From(sql.Table("users"))
+			KeysetPredicate(tt.column, "bob", "id", int64(9), tt.desc)(s)
+			query, args := s.Query()
+			if query != tt.wantQuery {
+				t.Errorf("query = %s, want %s", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestMultiKeysetPredicate(t *testing.T) {
+	s := sql.Select("*").From(sql.Table("users"))
+	MultiKeysetPredicate(
+		KeysetColumn{Column: "status", Value: "active"},
+		KeysetColumn{Column: "age", Value: 30, Desc: true},
+		KeysetColumn{Column: "id", Value: int64(9)},
+	)(s)
+	query, args := s.Query()
+	want := "SELECT * FROM `users` WHERE `users`.`status` > ? OR (`users`.`status` = ? AND (`users`.`age` < ? OR (`users`.`age` = ? AND `users`.`id` > ?)))"
+	if query != want {
+		t.Errorf("query = %s, want %s", query, want)
+	}
+	if wantArgs := []any{"active", "active", 30, 30, int64(9)}; !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+
+	s = sql.Select("*").From(sql.Table("users"))
+	MultiKeysetPredicate()(s)
+	if query, _ := s.Query(); query != "SELECT * FROM `users`" {
+		t.Errorf("no keys query = %s", query)
+	}
+}
+
+func TestCursorSortValues(t *testing.T) {
+	encoded, err := EncodeCursor(&Cursor{ID: 7, Values: []any{"active", 30}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := DecodeCursor(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.SortValues(), []any{"active", int64(30)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortValues() = %#v, want %#v", got, want)
+	}
+	if got := (&Cursor{ID: 1, Value: "bob"}).SortValues(); !reflect.DeepEqual(got, []any{"bob"}) {
+		t.Errorf("single value SortValues() = %#v", got)
+	}
+	if got := (&Cursor{ID: 1}).SortValues(); got != nil {
+		t.Errorf("ID-only SortValues() = %#v, want nil", got)
+	}
+}
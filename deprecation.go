@@ -0,0 +1,18 @@
+package entdomain
+
+import (
+	"net/http"
+	"time"
+)
+
+// SetDeprecationHeaders marks an HTTP response as served by a deprecated API.
+// It sets "Deprecation: true" and, when sunset is non-zero, the RFC 8594
+// Sunset header with the date after which the API stops responding.
+// Generated handlers of entities annotated with DomainConfig.Deprecated wrap
+// this with their own sunset date.
+func SetDeprecationHeaders(h http.Header, sunset time.Time) {
+	h.Set("Deprecation", "true")
+	if !sunset.IsZero() {
+		h.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+	}
+}
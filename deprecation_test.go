@@ -0,0 +1,30 @@
+package entdomain
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSetDeprecationHeaders(t *testing.T) {
+	t.Run("without sunset", func(t *testing.T) {
+		h := http.Header{}
+		SetDeprecationHeaders(h, time.Time{})
+
+		if got := h.Get("Deprecation"); got != "true" {
+			t.Errorf("Deprecation = %q, want %q", got, "true")
+		}
+		if _, ok := h["Sunset"]; ok {
+			t.Errorf("Sunset should not be set, got %q", h.Get("Sunset"))
+		}
+	})
+
+	t.Run("with sunset", func(t *testing.T) {
+		h := http.Header{}
+		SetDeprecationHeaders(h, time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC))
+
+		if got, want := h.Get("Sunset"), "Wed, 30 Jun 2027 00:00:00 GMT"; got != want {
+			t.Errorf("Sunset = %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package entdomain
+
+import (
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// CheckDistinctOn checks that the distinct columns lead order, in any order
+// and direction, as PostgreSQL requires of DISTINCT ON. Like
+// ListRequest.Validate, it returns plain errors.
+func CheckDistinctOn(columns []string, order []SortField) error {
+	if len(order) < len(columns) {
+		return fmt.Errorf("distinct_on fields must lead the sort order")
+	}
+	leading := make(map[string]bool, len(columns))
+	for _, o := range order[:len(columns)] {
+		leading[o.Field] = true
+	}
+	for _, c := range columns {
+		if !leading[c] {
+			return fmt.Errorf("distinct_on field %q must lead the sort order", c)
+		}
+	}
+	return nil
+}
+
+// DistinctOn returns the query modifier that selects DISTINCT ON (columns),
+// keeping the first row of each combination of columns. It needs PostgreSQL
+// and an ent build with the sql/modifier feature, and must run after the
+// columns are selected, as ent modifiers do.
+func DistinctOn(columns ...string) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		on := s.Columns(columns...)
+		selected := s.SelectedColumns()
+		s.SetDistinct(false).SelectExpr(sql.ExprFunc(func(b *sql.Builder) {
+			b.WriteString("DISTINCT ON (").IdentComma(on...).WriteString(") ").IdentComma(selected...)
+		}))
+	}
+}
+
+// CountDistinctOn returns the ent aggregate function counting the distinct
+// combinations of columns, the Total of a DistinctOn query. It wraps the
+// query in a subquery, so it runs as an aggregate, after the predicates are
+// applied. Unlike DistinctOn, it works on every dialect.
+func CountDistinctOn(columns ...string) func(*sql.Selector) string {
+	return func(s *sql.Selector) string {
+		inner := s.Clone().ClearOrder().Distinct()
+		inner.Select(inner.Columns(columns...)...)
+		s.SetP(nil).ClearOrder().SetDistinct(false).From(inner.As("distinct_on"))
+		return sql.Count("*")
+	}
+}
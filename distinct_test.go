@@ -0,0 +1,55 @@
+package entdomain
+
+import (
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+)
+
+func TestCheckDistinctOn(t *testing.T) {
+	order := []SortField{SortDesc("status"), SortAsc("name"), SortAsc("id")}
+	tests := []struct {
+		name    string
+		columns []string
+		wantErr bool
+	}{
+		{"leading", []string{"status"}, false},
+		{"leading in any order", []string{"name", "status"}, false},
+		{"not leading", []string{"name"}, true},
+		{"longer than order", []string{"status", "name", "id", "email"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckDistinctOn(tt.columns, order); (err != nil) != tt.wantErr {
+				t.Errorf("CheckDistinctOn(%v) err = %v, wantErr %v", tt.columns, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func usersSelector() *sql.Selector {
+	t := sql.Table("users")
+	return sql.Dialect(dialect.Postgres).Select(t.C("id"), t.C("name")).From(t).
+		Where(sql.EQ(t.C("status"), "active")).OrderBy(t.C("status"), t.C("name"))
+}
+
+func TestDistinctOn(t *testing.T) {
+	s := usersSelector()
+	DistinctOn("status")(s)
+	query, args := s.Query()
+	want := `SELECT DISTINCT ON ("users"."status") "users"."id", "users"."name" FROM "users" WHERE "users"."status" = $1 ORDER BY "users"."status", "users"."name"`
+	if query != want || len(args) != 1 {
+		t.Errorf("query = %s %v\nwant    %s", query, args, want)
+	}
+}
+
+func TestCountDistinctOn(t *testing.T) {
+	s := usersSelector()
+	s.Select(CountDistinctOn("status", "name")(s))
+	query, args := s.Query()
+	want := `SELECT COUNT(*) FROM (SELECT DISTINCT "users"."status", "users"."name" FROM "users" WHERE "users"."status" = $1) AS "distinct_on"`
+	if query != want || len(args) != 1 {
+		t.Errorf("query = %s %v\nwant    %s", query, args, want)
+	}
+}
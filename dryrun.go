@@ -0,0 +1,40 @@
+package entdomain
+
+import "context"
+
+type dryRunKey struct{}
+
+// dryRunState is the ctx value of a dry run: pending until a generated
+// service has started the transaction it runs in.
+type dryRunState int
+
+const (
+	dryRunPending dryRunState = iota + 1
+	dryRunStarted
+)
+
+// WithDryRun returns a ctx under which the generated Create, Update and Delete
+// run as usual, hooks and validation included, in a transaction they roll
+// back instead of committing. They return the would-be result, e.g. for
+// "preview changes" endpoints; events and outbox messages are not kept.
+// Hooks with side effects outside the database should check IsDryRun. A dry
+// run cannot join a transaction already carried by ctx.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, dryRunPending)
+}
+
+// IsDryRun reports whether ctx was returned by WithDryRun.
+func IsDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(dryRunKey{}).(dryRunState)
+	return ok
+}
+
+// BeginDryRun reports whether ctx is a dry run whose transaction has yet to be
+// started, and returns the ctx to run it with, on which it reports false.
+// Generated services call it on entry to their writes.
+func BeginDryRun(ctx context.Context) (context.Context, bool) {
+	if state, _ := ctx.Value(dryRunKey{}).(dryRunState); state == dryRunPending {
+		return context.WithValue(ctx, dryRunKey{}, dryRunStarted), true
+	}
+	return ctx, false
+}
@@ -0,0 +1,28 @@
+package entdomain
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	if IsDryRun(ctx) {
+		t.Error("IsDryRun() without WithDryRun = true")
+	}
+	if _, ok := BeginDryRun(ctx); ok {
+		t.Error("BeginDryRun() without WithDryRun = true")
+	}
+
+	ctx = WithDryRun(ctx)
+	started, ok := BeginDryRun(ctx)
+	if !ok || !IsDryRun(ctx) {
+		t.Fatal("BeginDryRun() of a dry run = false")
+	}
+	if _, ok := BeginDryRun(started); ok {
+		t.Error("BeginDryRun() of a started dry run = true")
+	}
+	if !IsDryRun(started) {
+		t.Error("IsDryRun() of a started dry run = false")
+	}
+}
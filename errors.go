@@ -1,6 +1,11 @@
 package entdomain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
 
 // Sentinel errors returned by generated repositories. Use errors.Is() or the
 // provided Is* helpers to check error types without string matching.
@@ -13,6 +18,28 @@ var (
 
 	// ErrValidation indicates the input failed validation.
 	ErrValidation = errors.New("validation failed")
+
+	// ErrForbidden indicates the caller is not allowed to perform the operation.
+	ErrForbidden = errors.New("operation forbidden")
+
+	// ErrTxRequired indicates the operation must run inside a transaction
+	// (e.g. row locking reads). Use the generated WithTx to start one.
+	ErrTxRequired = errors.New("transaction required")
+
+	// ErrConflict indicates the entity was modified since the caller read
+	// it, as detected by optimistic locking. Re-read it and retry. It is also
+	// returned for retries of a request still running under its idempotency
+	// key, see Idempotent.
+	ErrConflict = errors.New("entity was modified concurrently")
+
+	// ErrPreconditionFailed indicates the entity no longer has the entity
+	// tag a conditional update was made against, e.g. the If-Match header
+	// of the request.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrRateLimited indicates the caller exceeded the rate a RateLimiter
+	// allows for the operation. Retry later.
+	ErrRateLimited = errors.New("rate limit exceeded")
 )
 
 // IsNotFound reports whether err (or any error in its chain) is ErrNotFound.
@@ -23,3 +50,89 @@ func IsAlreadyExists(err error) bool { return errors.Is(err, ErrAlreadyExists) }
 
 // IsValidation reports whether err (or any error in its chain) is ErrValidation.
 func IsValidation(err error) bool { return errors.Is(err, ErrValidation) }
+
+// IsForbidden reports whether err (or any error in its chain) is ErrForbidden.
+func IsForbidden(err error) bool { return errors.Is(err, ErrForbidden) }
+
+// IsTxRequired reports whether err (or any error in its chain) is ErrTxRequired.
+func IsTxRequired(err error) bool { return errors.Is(err, ErrTxRequired) }
+
+// IsConflict reports whether err (or any error in its chain) is ErrConflict.
+func IsConflict(err error) bool { return errors.Is(err, ErrConflict) }
+
+// IsPreconditionFailed reports whether err (or any error in its chain) is ErrPreconditionFailed.
+func IsPreconditionFailed(err error) bool { return errors.Is(err, ErrPreconditionFailed) }
+
+// IsRateLimited reports whether err (or any error in its chain) is ErrRateLimited.
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// MissingIDsError is returned by generated GetByIDs methods when some of the
+// requested IDs match no entity. It matches ErrNotFound.
+type MissingIDsError struct {
+	// Resource is the lowercase entity name, e.g. "user".
+	Resource string
+
+	// IDs holds the String forms of the missing IDs, in request order.
+	IDs []string
+}
+
+// Error implements error.
+func (e *MissingIDsError) Error() string {
+	return fmt.Sprintf("%v: %s %s", ErrNotFound, e.Resource, strings.Join(e.IDs, ", "))
+}
+
+// Unwrap returns ErrNotFound.
+func (e *MissingIDsError) Unwrap() error { return ErrNotFound }
+
+// BatchError is returned by generated batch methods, which write their items
+// in chunks of ExtensionConfig.BatchChunkSize, when a chunk fails. Outside a
+// transaction, the Offset items before the chunk have been written. It
+// matches the error of the failing chunk.
+type BatchError struct {
+	// Offset is the index of the first item of the failing chunk.
+	Offset int
+
+	// Err is the error the chunk failed with.
+	Err error
+}
+
+// Error implements error.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch failed at item %d: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns Err.
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// ItemErrors is returned by the generated CreateBatch and UpdateBatch when
+// some items fail, keyed by the index of each failed item; the other items
+// were written. errors.Is and errors.As match the error of any item.
+type ItemErrors map[int]error
+
+// Error implements error.
+func (e ItemErrors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, i := range e.indexes() {
+		parts = append(parts, fmt.Sprintf("item %d: %v", i, e[i]))
+	}
+	return fmt.Sprintf("%d batch items failed: %s", len(e), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the errors of the failed items, in the order of their
+// indexes.
+func (e ItemErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, i := range e.indexes() {
+		errs = append(errs, e[i])
+	}
+	return errs
+}
+
+func (e ItemErrors) indexes() []int {
+	indexes := make([]int, 0, len(e))
+	for i := range e {
+		indexes = append(indexes, i)
+	}
+	slices.Sort(indexes)
+	return indexes
+}
@@ -68,3 +68,106 @@ func TestIsValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestIsForbidden(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"direct", ErrForbidden, true},
+		{"wrapped", fmt.Errorf("user.delete: %w", ErrForbidden), true},
+		{"nil", nil, false},
+		{"unrelated", errors.New("something else"), false},
+		{"ErrValidation", ErrValidation, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsForbidden(tt.err); got != tt.want {
+				t.Errorf("IsForbidden() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTxRequired(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"direct", ErrTxRequired, true},
+		{"wrapped", fmt.Errorf("user GetByIDForUpdate: %w", ErrTxRequired), true},
+		{"nil", nil, false},
+		{"unrelated", errors.New("something else"), false},
+		{"ErrNotFound", ErrNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTxRequired(tt.err); got != tt.want {
+				t.Errorf("IsTxRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingIDsError(t *testing.T) {
+	var err error = fmt.Errorf("load: %w", &MissingIDsError{Resource: "user", IDs: []string{"2", "5"}})
+	if !IsNotFound(err) {
+		t.Error("MissingIDsError should match ErrNotFound")
+	}
+	var missing *MissingIDsError
+	if !errors.As(err, &missing) || len(missing.IDs) != 2 {
+		t.Fatalf("errors.As() = %v", missing)
+	}
+	if want := "load: entity not found: user 2, 5"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestBatchError(t *testing.T) {
+	var err error = &BatchError{Offset: 500, Err: fmt.Errorf("%w: dup", ErrAlreadyExists)}
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Error("BatchError should match the error of its chunk")
+	}
+	if want := "batch failed at item 500: entity already exists: dup"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestItemErrors(t *testing.T) {
+	var err error = ItemErrors{3: ErrNotFound, 0: fmt.Errorf("%w: name is required", ErrValidation)}
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, ErrValidation) {
+		t.Error("ItemErrors should match the error of every item")
+	}
+	if want := "2 batch items failed: item 0: validation failed: name is required; item 3: entity not found"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestIsConflict(t *testing.T) {
+	if !IsConflict(fmt.Errorf("doc 1: %w", ErrConflict)) {
+		t.Error("wrapped ErrConflict should match")
+	}
+	if IsConflict(ErrNotFound) || IsConflict(nil) {
+		t.Error("IsConflict should only match ErrConflict")
+	}
+}
+
+func TestIsPreconditionFailed(t *testing.T) {
+	if !IsPreconditionFailed(fmt.Errorf("doc 1: %w", ErrPreconditionFailed)) {
+		t.Error("wrapped ErrPreconditionFailed should match")
+	}
+	if IsPreconditionFailed(ErrConflict) || IsPreconditionFailed(nil) {
+		t.Error("IsPreconditionFailed should only match ErrPreconditionFailed")
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	if !IsRateLimited(fmt.Errorf("user:create: %w", ErrRateLimited)) {
+		t.Error("wrapped ErrRateLimited should match")
+	}
+	if IsRateLimited(ErrForbidden) || IsRateLimited(nil) {
+		t.Error("IsRateLimited should only match ErrRateLimited")
+	}
+}
@@ -0,0 +1,53 @@
+package entdomain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ETag returns the strong entity tag of the version of an entity: a quoted
+// hash of its ID and of the value versioning it, such as an optimistic lock
+// counter or updated_at. Generated {Entity}Response.ETag methods call it.
+func ETag(id, version any) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v\x00%s", id, etagValue(version))))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagValue formats version for ETag. Times are compared in UTC to the
+// nanosecond, so that a time read back from another location tags alike.
+func etagValue(version any) string {
+	rv := reflect.ValueOf(version)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+	if t, ok := rv.Interface().(time.Time); ok {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// ETagMatches reports whether the value of an If-Match header, a list of
+// entity tags or "*", matches etag. As RFC 9110 requires for If-Match, weak
+// tags (W/"...") never match.
+func ETagMatches(ifMatch, etag string) bool {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && tag == etag && !strings.HasPrefix(tag, "W/") {
+			return true
+		}
+	}
+	return false
+}
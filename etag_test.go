@@ -0,0 +1,43 @@
+package entdomain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestETag(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tag := ETag(1, at)
+	if len(tag) != 34 || tag[0] != '"' || tag[33] != '"' {
+		t.Fatalf("ETag() = %s, want a quoted 32-digit hash", tag)
+	}
+	if ETag(1, at.In(time.FixedZone("CEST", 2*3600))) != tag || ETag(1, &at) != tag {
+		t.Error("the same time should tag alike in any location or through a pointer")
+	}
+	if ETag(1, at.Add(time.Nanosecond)) == tag || ETag(2, at) == tag {
+		t.Error("another version or ID should tag differently")
+	}
+	if ETag(1, 3) == ETag(1, 4) || ETag(1, (*int)(nil)) == ETag(1, 0) {
+		t.Error("version counters should tag differently")
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	tag := ETag("u1", 3)
+	tests := []struct {
+		ifMatch string
+		want    bool
+	}{
+		{tag, true},
+		{" * ", true},
+		{`"other", ` + tag, true},
+		{`"other"`, false},
+		{"W/" + tag, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ETagMatches(tt.ifMatch, tag); got != tt.want {
+			t.Errorf("ETagMatches(%q) = %v, want %v", tt.ifMatch, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package entdomain
+
+import "context"
+
+// DomainEvent is a change to a domain entity. Generated services of entities
+// declared with DomainConfig.WithEvents publish an {Entity}Created,
+// {Entity}Updated or {Entity}Deleted event to their EventBus after each
+// successful Create, Update and Delete.
+type DomainEvent interface {
+	// EventName returns the name of the event: the snake_case resource name
+	// Authorizer sees and the change, e.g. "user.created".
+	EventName() string
+}
+
+// EventBus delivers DomainEvents to their subscribers, e.g. in process or
+// through a message broker. Writes inside a transaction publish their events
+// once it commits, so subscribers never see rolled back changes.
+type EventBus interface {
+	Publish(ctx context.Context, event DomainEvent) error
+}
+
+// EventBusFunc adapts an ordinary function to the EventBus interface.
+type EventBusFunc func(ctx context.Context, event DomainEvent) error
+
+// Publish calls f(ctx, event).
+func (f EventBusFunc) Publish(ctx context.Context, event DomainEvent) error {
+	return f(ctx, event)
+}
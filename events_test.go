@@ -0,0 +1,24 @@
+package entdomain
+
+import (
+	"context"
+	"testing"
+)
+
+type testEvent struct{}
+
+func (testEvent) EventName() string { return "post.created" }
+
+func TestEventBusFunc(t *testing.T) {
+	var got []string
+	bus := EventBusFunc(func(_ context.Context, event DomainEvent) error {
+		got = append(got, event.EventName())
+		return nil
+	})
+	if err := bus.Publish(context.Background(), testEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != "post.created" {
+		t.Errorf("published %v, want [post.created]", got)
+	}
+}
@@ -0,0 +1,174 @@
+package entdomain
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+)
+
+// ExportFormat is the encoding of the output of generated Export methods.
+type ExportFormat string
+
+const (
+	// ExportCSV is comma-separated values: a header row of column names,
+	// then a row per entity.
+	ExportCSV ExportFormat = "csv"
+	// ExportJSON is a JSON array with an object per entity.
+	ExportJSON ExportFormat = "json"
+	// ExportJSONL is JSON Lines: a JSON object per entity and line.
+	ExportJSONL ExportFormat = "jsonl"
+)
+
+// DefaultExportBatchSize is the number of rows generated Export methods read
+// per query.
+const DefaultExportBatchSize = 500
+
+// ExportWriter writes the rows of an export with a fixed set of columns, as
+// generated Export methods do. It buffers its output; Close flushes it.
+type ExportWriter struct {
+	w       *bufio.Writer
+	csv     *csv.Writer
+	format  ExportFormat
+	columns []string
+	keys    [][]byte
+	cells   []string
+	rows    int
+}
+
+// NewExportWriter returns an ExportWriter of format writing to w. For
+// ExportCSV, the header row naming columns is written first. An unknown format
+// is an ErrValidation error.
+func NewExportWriter(w io.Writer, format ExportFormat, columns []string) (*ExportWriter, error) {
+	e := &ExportWriter{w: bufio.NewWriter(w), format: format, columns: columns}
+	switch format {
+	case ExportCSV:
+		e.csv = csv.NewWriter(e.w)
+		e.cells = make([]string, len(columns))
+		if err := e.csv.Write(columns); err != nil {
+			return nil, err
+		}
+	case ExportJSON, ExportJSONL:
+		e.keys = make([][]byte, len(columns))
+		for i, c := range columns {
+			key, err := json.Marshal(c)
+			if err != nil {
+				return nil, err
+			}
+			e.keys[i] = append(key, ':')
+		}
+		if format == ExportJSON {
+			if err := e.w.WriteByte('['); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown export format %q", ErrValidation, format)
+	}
+	return e, nil
+}
+
+// Write writes a row of values, one per column. Nil pointers are written as
+// empty cells or JSON nulls. In CSV cells, times are written in RFC 3339,
+// bytes in base64, and slices, maps and structs as JSON.
+func (e *ExportWriter) Write(values ...any) error {
+	if len(values) != len(e.columns) {
+		return fmt.Errorf("export row has %d values for %d columns", len(values), len(e.columns))
+	}
+	e.rows++
+	if e.csv != nil {
+		for i, v := range values {
+			cell, err := exportCell(v)
+			if err != nil {
+				return fmt.Errorf("exporting %s: %w", e.columns[i], err)
+			}
+			e.cells[i] = cell
+		}
+		return e.csv.Write(e.cells)
+	}
+
+	if e.format == ExportJSON {
+		if e.rows > 1 {
+			e.w.WriteByte(',')
+		}
+		e.w.WriteByte('\n')
+	}
+	e.w.WriteByte('{')
+	for i, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("exporting %s: %w", e.columns[i], err)
+		}
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		e.w.Write(e.keys[i])
+		e.w.Write(data)
+	}
+	e.w.WriteByte('}')
+	if e.format == ExportJSONL {
+		e.w.WriteByte('\n')
+	}
+	// Errors of the writes above are kept by e.w and returned by Flush.
+	return nil
+}
+
+// Close ends the export, closing the JSON array of ExportJSON, and flushes
+// it. It does not close the underlying writer.
+func (e *ExportWriter) Close() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if e.format == ExportJSON {
+		if e.rows > 0 {
+			e.w.WriteByte('\n')
+		}
+		e.w.WriteString("]\n")
+	}
+	return e.w.Flush()
+}
+
+// exportCell formats v as a CSV cell.
+func exportCell(v any) (string, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "", nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return "", nil
+	}
+	switch v := rv.Interface().(type) {
+	case string:
+		return v, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		if rv.IsNil() {
+			return "", nil
+		}
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(rv.Interface()), nil
+	}
+	data, err := json.Marshal(rv.Interface())
+	return string(data), err
+}
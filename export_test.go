@@ -0,0 +1,71 @@
+package entdomain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestExportWriter(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	nick := "a, \"b\""
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	rows := [][]any{
+		{id, &nick, 30, at, []string{"x"}},
+		{id, (*string)(nil), 0, at, []string(nil)},
+	}
+	columns := []string{"id", "nickname", "age", "created_at", "tags"}
+
+	tests := []struct {
+		format ExportFormat
+		want   string
+	}{
+		{ExportCSV, "id,nickname,age,created_at,tags\n" +
+			"6ba7b810-9dad-11d1-80b4-00c04fd430c8,\"a, \"\"b\"\"\",30,2024-05-01T12:00:00Z,\"[\"\"x\"\"]\"\n" +
+			"6ba7b810-9dad-11d1-80b4-00c04fd430c8,,0,2024-05-01T12:00:00Z,\n"},
+		{ExportJSONL, `{"id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","nickname":"a, \"b\"","age":30,"created_at":"2024-05-01T12:00:00Z","tags":["x"]}` + "\n" +
+			`{"id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","nickname":null,"age":0,"created_at":"2024-05-01T12:00:00Z","tags":null}` + "\n"},
+		{ExportJSON, "[\n" + `{"id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","nickname":"a, \"b\"","age":30,"created_at":"2024-05-01T12:00:00Z","tags":["x"]}` + ",\n" +
+			`{"id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","nickname":null,"age":0,"created_at":"2024-05-01T12:00:00Z","tags":null}` + "\n]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var b strings.Builder
+			w, err := NewExportWriter(&b, tt.format, columns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range rows {
+				if err := w.Write(row...); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestExportWriterEdgeCases(t *testing.T) {
+	var b strings.Builder
+	w, err := NewExportWriter(&b, ExportJSON, []string{"id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil || b.String() != "[]\n" {
+		t.Errorf("empty JSON export = %q, %v", b.String(), err)
+	}
+	if err := w.Write(1, 2); err == nil {
+		t.Error("a row with more values than columns should fail")
+	}
+	if _, err := NewExportWriter(&b, "xml", nil); !errors.Is(err, ErrValidation) {
+		t.Errorf("unknown format: err = %v, want ErrValidation", err)
+	}
+}
@@ -1,16 +1,20 @@
 package entdomain
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
 	"entgo.io/ent/entc"
 	"entgo.io/ent/entc/gen"
+	"entgo.io/ent/schema/field"
 	"golang.org/x/tools/imports"
 )
 
@@ -18,6 +22,17 @@ import (
 type Extension struct {
 	// Config holds the extension configuration.
 	Config *ExtensionConfig
+
+	// report collects render statistics when Config.Report is set.
+	report *generationReport
+
+	// templates caches parsed templates by name, so each template is parsed
+	// once per extension rather than once per entity.
+	templates map[string]*template.Template
+
+	// aggregated collects rendered files by kind when Config.AggregatedOutput
+	// is set, until they are written at the end of generation.
+	aggregated map[string]*aggregatedFile
 }
 
 // ExtensionConfig holds configuration for the extension
@@ -28,6 +43,113 @@ type ExtensionConfig struct {
 	// GenerateBaseHandler controls whether BaseHandler structs are generated
 	GenerateBaseHandler bool
 
+	// GenerateServiceExtensions controls whether a {entity}_domain_service_ext.go
+	// scaffold embedding the base service is created. The file is written only
+	// when missing, so custom code in it survives regeneration. Requires
+	// GenerateBaseService.
+	GenerateServiceExtensions bool
+
+	// GenerateExampleTests controls whether {entity}_example_test.go files with
+	// compiled usage examples are generated. Requires GenerateBaseService.
+	GenerateExampleTests bool
+
+	// GenerateBenchmarks controls whether {entity}_bench_test.go files with
+	// GetByID and list benchmarks against in-memory SQLite are generated.
+	// The target module needs github.com/mattn/go-sqlite3. Requires
+	// GenerateBaseService.
+	GenerateBenchmarks bool
+
+	// GenerateMocks controls whether {entity}_domain_mocks.go files with a
+	// Mock{Entity}Repository implementing the repository interfaces are
+	// generated. Requires GenerateBaseService.
+	GenerateMocks bool
+
+	// GenerateTracing controls whether {entity}_domain_tracing.go files with
+	// an {Entity}TracedRepository, starting a span per operation of a wrapped
+	// service or repository, are generated. Requires GenerateBaseService.
+	GenerateTracing bool
+
+	// GenerateImporter controls whether {entity}_importer.go files with an
+	// {Entity}Importer, creating entities in batches from CSV or JSON Lines
+	// input, are generated. Requires GenerateBaseService.
+	GenerateImporter bool
+
+	// GenerateSchemaSnapshot controls whether entdomain_schema_snapshot.go,
+	// recording the table shape of annotated entities for runtime drift
+	// checks (entdomain.CheckSchemaDrift), is generated
+	GenerateSchemaSnapshot bool
+
+	// GeneratePermissions controls whether RBAC permission constants are generated
+	GeneratePermissions bool
+
+	// StrictAuthorization makes generated services deny every operation
+	// when no Authorizer is configured instead of allowing it
+	StrictAuthorization bool
+
+	// GenerateSearchIndexing controls whether Reindex methods feeding an
+	// entdomain.SearchIndexer are generated on base services
+	GenerateSearchIndexing bool
+
+	// GenerateUpsert controls whether Upsert and UpsertBy methods, which
+	// insert or update on a conflict of a Unique field, are generated on
+	// base services. Requires ent's sql/upsert feature
+	GenerateUpsert bool
+
+	// BatchChunkSize is the number of rows DeleteBatch and UpsertBatch write
+	// per statement, keeping large batches within the bind parameter limits
+	// of the database. Zero means entdomain.DefaultBatchChunkSize
+	BatchChunkSize int
+
+	// GenSuffix names generated files {entity}_{kind}.gen.go instead of
+	// {entity}_{kind}.go, separating immutable output from hand-written
+	// skeletons (which keep the plain .go suffix and are only created when absent)
+	GenSuffix bool
+
+	// AggregatedOutput writes the DTOs, base services, base handlers and
+	// permissions of all entities into one file per kind
+	// (entdomain_dto.go, entdomain_base_service.go, ...) instead of one file
+	// per entity and kind. Example tests, benchmarks and service extension
+	// scaffolds stay per entity
+	AggregatedOutput bool
+
+	// APIVersion prefixes generated route paths with a version segment
+	// (e.g. "v1" → "/v1/users"). Entities can override it with
+	// DomainConfig.APIVersions. Empty means unversioned routes.
+	APIVersion string
+
+	// IntegerIDsAsStrings encodes integer IDs in generated Response DTOs as
+	// JSON strings (`json:",string"`), since 64-bit IDs lose precision in
+	// JavaScript numbers. Entities with an ID prefix are strings already.
+	IntegerIDsAsStrings bool
+
+	// TypedIDs generates a distinct ID type per entity ({Entity}ID wrapping
+	// entdomain.TypedID) and uses it in base service and handler signatures,
+	// so IDs of different entities cannot be mixed up
+	TypedIDs bool
+
+	// IDValidation adds an IDValidator field to generated base services.
+	// Every ID-based operation validates its ID with it before querying;
+	// zero IDs are always rejected
+	IDValidation bool
+
+	// DefaultFieldAnnotation, when set, is applied to every field without a
+	// DomainField annotation (sensitive fields excepted), so existing schemas
+	// can adopt entdomain without annotating each field
+	DefaultFieldAnnotation *DomainField
+
+	// DefaultFieldAnnotations overrides DefaultFieldAnnotation for unannotated
+	// fields of specific types, e.g. field.TypeTime → OutputOnlyField()
+	DefaultFieldAnnotations map[field.Type]DomainField
+
+	// Strict fails generation on schemas without annotated fields and on
+	// sort, lookup, or shard annotations that would otherwise be ignored
+	Strict bool
+
+	// Report, when set, receives a table of per-entity template render
+	// times, file sizes, and generated function counts at the end of
+	// generation, to find the schemas that dominate generation time
+	Report io.Writer
+
 	// EntDomainPackage is the import path for the entdomain package
 	// Default: "github.com/githonllc/entdomain"
 	EntDomainPackage string
@@ -69,6 +191,29 @@ func (e *Extension) generatePerTypeFiles(next gen.Generator) gen.Generator {
 			return err
 		}
 
+		if e.Config.BatchChunkSize < 0 {
+			return fmt.Errorf("invalid BatchChunkSize %d: cannot be negative", e.Config.BatchChunkSize)
+		}
+
+		e.applyDefaultFieldAnnotations(g.Nodes)
+		if upsert, _ := g.Config.FeatureEnabled("sql/upsert"); e.Config.GenerateUpsert && !upsert {
+			log.Printf("WARNING: skipping Upsert methods: the sql/upsert feature is not enabled")
+		}
+
+		if e.Config.Strict {
+			if err := validateStrict(g.Nodes); err != nil {
+				return err
+			}
+		}
+
+		e.report = nil
+		if e.Config.Report != nil {
+			e.report = newGenerationReport()
+		}
+		if err := e.removeOtherOutputMode(g); err != nil {
+			return err
+		}
+
 		// Generate separate files for each Type that has entdomain annotations.
 		// Entities without annotations are skipped to avoid empty generated files.
 		for _, node := range g.Nodes {
@@ -88,12 +233,81 @@ func (e *Extension) generatePerTypeFiles(next gen.Generator) gen.Generator {
 				}
 			}
 
+			// Scaffold custom service file → ent/{entity}_domain_service_ext.go (only if absent)
+			if e.Config.GenerateBaseService && e.Config.GenerateServiceExtensions {
+				if err := e.generateDomainServiceExtFile(g, node); err != nil {
+					return fmt.Errorf("failed to generate %s domain service extension file: %w", node.Name, err)
+				}
+			}
+
+			// Generate example tests → ent/{entity}_example_test.go
+			if e.Config.GenerateBaseService && e.Config.GenerateExampleTests {
+				if err := e.generateExampleTestFile(g, node); err != nil {
+					return fmt.Errorf("failed to generate %s example tests: %w", node.Name, err)
+				}
+			}
+
+			// Generate benchmarks → ent/{entity}_bench_test.go
+			if e.Config.GenerateBaseService && e.Config.GenerateBenchmarks {
+				if err := e.generateBenchTestFile(g, node); err != nil {
+					return fmt.Errorf("failed to generate %s benchmarks: %w", node.Name, err)
+				}
+			}
+
+			// Generate repository mocks → ent/{entity}_domain_mocks.go
+			if e.Config.GenerateBaseService && e.Config.GenerateMocks {
+				if err := e.generateDomainMocksFile(g, node); err != nil {
+					return fmt.Errorf("failed to generate %s mocks: %w", node.Name, err)
+				}
+			}
+
+			// Generate tracing decorators → ent/{entity}_domain_tracing.go
+			if e.Config.GenerateBaseService && e.Config.GenerateTracing {
+				if err := e.generateDomainTracingFile(g, node); err != nil {
+					return fmt.Errorf("failed to generate %s tracing: %w", node.Name, err)
+				}
+			}
+
+			// Generate bulk importers → ent/{entity}_importer.go
+			if e.Config.GenerateBaseService && e.Config.GenerateImporter {
+				if err := e.generateImporterFile(g, node); err != nil {
+					return fmt.Errorf("failed to generate %s importer: %w", node.Name, err)
+				}
+			}
+
 			// Generate base handler file → ent/{entity}_base_handler.go
 			if e.Config.GenerateBaseHandler {
 				if err := e.generateBaseHandlerFile(g, node); err != nil {
 					return fmt.Errorf("failed to generate %s base handler file: %w", node.Name, err)
 				}
 			}
+
+			// Generate permission constants file → ent/{entity}_permissions.go
+			if e.Config.GeneratePermissions {
+				if err := e.generatePermissionsFile(g, node); err != nil {
+					return fmt.Errorf("failed to generate %s permissions file: %w", node.Name, err)
+				}
+			}
+		}
+
+		// Write the files collected in aggregated mode → ent/entdomain_{kind}.go
+		if e.Config.AggregatedOutput {
+			if err := e.writeAggregatedFiles(g); err != nil {
+				return fmt.Errorf("failed to write aggregated files: %w", err)
+			}
+		}
+
+		// Generate graph-level schema snapshot → ent/entdomain_schema_snapshot.go
+		if e.Config.GenerateSchemaSnapshot {
+			if err := e.generateSchemaSnapshotFile(g); err != nil {
+				return fmt.Errorf("failed to generate schema snapshot: %w", err)
+			}
+		}
+
+		if e.report != nil {
+			if err := e.report.write(e.Config.Report); err != nil {
+				return fmt.Errorf("failed to write generation report: %w", err)
+			}
 		}
 
 		return nil
@@ -103,69 +317,381 @@ func (e *Extension) generatePerTypeFiles(next gen.Generator) gen.Generator {
 // generateDTOFile generates a DTO file for a single Type.
 // Output: ent/{entity}_dto.go
 func (e *Extension) generateDTOFile(g *gen.Graph, node *gen.Type) error {
-	tmpl, err := template.New("dto").
-		Funcs(e.templateFuncMap()).
-		Parse(dtoTemplate)
+	start := time.Now()
+	tmpl, err := e.template("dto", dtoTemplate)
 	if err != nil {
 		return fmt.Errorf("failed to parse DTO template: %w", err)
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, node); err != nil {
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
 		return fmt.Errorf("failed to render DTO template: %w", err)
 	}
+	e.report.record(node.Name, "dto", time.Since(start), content)
 
-	filename := fmt.Sprintf("%s_dto.go", strings.ToLower(node.Name))
-	outputPath := filepath.Join(g.Config.Target, filename)
-
-	return writeFile(outputPath, buf.Bytes())
+	return e.writeGeneratedFile(g, node, "dto", content)
 }
 
 // generateBaseServiceFile generates a base service file for a single Type.
 // Output: ent/{entity}_base_service.go
 func (e *Extension) generateBaseServiceFile(g *gen.Graph, node *gen.Type) error {
-	tmpl, err := template.New("base_service").
-		Funcs(e.templateFuncMap()).
-		Parse(baseServiceTemplate)
+	start := time.Now()
+	tmpl, err := e.template("base_service", baseServiceTemplate)
 	if err != nil {
 		return fmt.Errorf("failed to parse base service template: %w", err)
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, node); err != nil {
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
 		return fmt.Errorf("failed to render base service template: %w", err)
 	}
+	e.report.record(node.Name, "base_service", time.Since(start), content)
+
+	return e.writeGeneratedFile(g, node, "base_service", content)
+}
+
+// generateDomainServiceExtFile scaffolds the hand-editable service for a single Type.
+// Output: ent/{entity}_domain_service_ext.go, skipped if the file already exists.
+func (e *Extension) generateDomainServiceExtFile(g *gen.Graph, node *gen.Type) error {
+	filename := fmt.Sprintf("%s_domain_service_ext.go", strings.ToLower(node.Name))
+	outputPath := filepath.Join(g.Config.Target, filename)
+	if _, err := os.Stat(outputPath); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to stat %s: %w", outputPath, err)
+	}
+
+	start := time.Now()
+	tmpl, err := e.template("domain_service_ext", domainServiceExtTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse domain service extension template: %w", err)
+	}
+
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
+		return fmt.Errorf("failed to render domain service extension template: %w", err)
+	}
+	e.report.record(node.Name, "domain_service_ext", time.Since(start), content)
+
+	return writeFile(outputPath, content)
+}
+
+// generateExampleTestFile generates usage examples for a single Type.
+// Output: ent/{entity}_example_test.go (never suffixed with .gen, so go test picks it up).
+// Types with a composite primary key are skipped with a warning.
+func (e *Extension) generateExampleTestFile(g *gen.Graph, node *gen.Type) error {
+	if node.HasCompositeID() {
+		log.Printf("WARNING: skipping %s example tests: composite primary keys are not supported", node.Name)
+		return nil
+	}
+
+	start := time.Now()
+	tmpl, err := e.template("example_test", exampleTestTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse example test template: %w", err)
+	}
+
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
+		return fmt.Errorf("failed to render example test template: %w", err)
+	}
+	e.report.record(node.Name, "example_test", time.Since(start), content)
+
+	filename := fmt.Sprintf("%s_example_test.go", strings.ToLower(node.Name))
+	outputPath := filepath.Join(g.Config.Target, filename)
+
+	return writeFile(outputPath, content)
+}
+
+// generateBenchTestFile generates benchmarks for a single Type.
+// Output: ent/{entity}_bench_test.go. Types with a composite primary key or
+// whose rows cannot be synthesized (see benchSeedable) are skipped with a warning.
+func (e *Extension) generateBenchTestFile(g *gen.Graph, node *gen.Type) error {
+	if node.HasCompositeID() {
+		log.Printf("WARNING: skipping %s benchmarks: composite primary keys are not supported", node.Name)
+		return nil
+	}
+	if !benchSeedable(node) {
+		log.Printf("WARNING: skipping %s benchmarks: required fields or edges cannot be seeded automatically", node.Name)
+		return nil
+	}
+
+	start := time.Now()
+	tmpl, err := e.template("bench_test", benchTestTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse benchmark template: %w", err)
+	}
+
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
+		return fmt.Errorf("failed to render benchmark template: %w", err)
+	}
+	e.report.record(node.Name, "bench_test", time.Since(start), content)
 
-	filename := fmt.Sprintf("%s_base_service.go", strings.ToLower(node.Name))
+	filename := fmt.Sprintf("%s_bench_test.go", strings.ToLower(node.Name))
 	outputPath := filepath.Join(g.Config.Target, filename)
 
-	return writeFile(outputPath, buf.Bytes())
+	return writeFile(outputPath, content)
+}
+
+// generateDomainMocksFile generates the repository mocks for a single Type.
+// Output: ent/{entity}_domain_mocks.go. Types with a composite primary key,
+// which have no repository interfaces, are skipped with a warning.
+func (e *Extension) generateDomainMocksFile(g *gen.Graph, node *gen.Type) error {
+	if node.HasCompositeID() {
+		log.Printf("WARNING: skipping %s mocks: composite primary keys are not supported", node.Name)
+		return nil
+	}
+
+	start := time.Now()
+	tmpl, err := e.template("domain_mocks", domainMocksTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse mocks template: %w", err)
+	}
+
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
+		return fmt.Errorf("failed to render mocks template: %w", err)
+	}
+	e.report.record(node.Name, "domain_mocks", time.Since(start), content)
+
+	return e.writeGeneratedFile(g, node, "domain_mocks", content)
+}
+
+// generateDomainTracingFile generates the tracing decorator for a single Type.
+// Output: ent/{entity}_domain_tracing.go. Types with a composite primary key,
+// which have no repository interfaces, are skipped with a warning.
+func (e *Extension) generateDomainTracingFile(g *gen.Graph, node *gen.Type) error {
+	if node.HasCompositeID() {
+		log.Printf("WARNING: skipping %s tracing: composite primary keys are not supported", node.Name)
+		return nil
+	}
+
+	start := time.Now()
+	tmpl, err := e.template("domain_tracing", domainTracingTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse tracing template: %w", err)
+	}
+
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
+		return fmt.Errorf("failed to render tracing template: %w", err)
+	}
+	e.report.record(node.Name, "domain_tracing", time.Since(start), content)
+
+	return e.writeGeneratedFile(g, node, "domain_tracing", content)
+}
+
+// generateImporterFile generates the bulk importer for a single Type.
+// Output: ent/{entity}_importer.go. Types without create fields get none, and
+// types with a composite primary key are skipped with a warning.
+func (e *Extension) generateImporterFile(g *gen.Graph, node *gen.Type) error {
+	if len(createFields(node)) == 0 {
+		return nil
+	}
+	if node.HasCompositeID() {
+		log.Printf("WARNING: skipping %s importer: composite primary keys are not supported", node.Name)
+		return nil
+	}
+
+	start := time.Now()
+	tmpl, err := e.template("importer", importerTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse importer template: %w", err)
+	}
+
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
+		return fmt.Errorf("failed to render importer template: %w", err)
+	}
+	e.report.record(node.Name, "importer", time.Since(start), content)
+
+	return e.writeGeneratedFile(g, node, "importer", content)
+}
+
+// generateSchemaSnapshotFile generates the table shape snapshot for the whole graph.
+// Output: ent/entdomain_schema_snapshot.go
+func (e *Extension) generateSchemaSnapshotFile(g *gen.Graph) error {
+	start := time.Now()
+	tmpl, err := e.template("schema_snapshot", schemaSnapshotTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse schema snapshot template: %w", err)
+	}
+
+	content, err := renderStreamed(g.Config.Target, tmpl, g)
+	if err != nil {
+		return fmt.Errorf("failed to render schema snapshot template: %w", err)
+	}
+	e.report.record(graphEntity, "schema_snapshot", time.Since(start), content)
+
+	filename := "entdomain_schema_snapshot.go"
+	if e.Config.GenSuffix {
+		filename = "entdomain_schema_snapshot.gen.go"
+	}
+
+	return e.writeGeneratedPath(g, filename, content)
 }
 
 // generateBaseHandlerFile generates a base handler file for a single Type.
 // Output: ent/{entity}_base_handler.go
 func (e *Extension) generateBaseHandlerFile(g *gen.Graph, node *gen.Type) error {
-	tmpl, err := template.New("base_handler").
-		Funcs(e.templateFuncMap()).
-		Parse(baseHandlerTemplate)
+	start := time.Now()
+	tmpl, err := e.template("base_handler", baseHandlerTemplate)
 	if err != nil {
 		return fmt.Errorf("failed to parse base handler template: %w", err)
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, node); err != nil {
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
 		return fmt.Errorf("failed to render base handler template: %w", err)
 	}
+	e.report.record(node.Name, "base_handler", time.Since(start), content)
+
+	return e.writeGeneratedFile(g, node, "base_handler", content)
+}
 
-	filename := fmt.Sprintf("%s_base_handler.go", strings.ToLower(node.Name))
+// generatePermissionsFile generates the RBAC permission constants for a single Type.
+// Output: ent/{entity}_permissions.go
+func (e *Extension) generatePermissionsFile(g *gen.Graph, node *gen.Type) error {
+	start := time.Now()
+	tmpl, err := e.template("permissions", permissionsTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse permissions template: %w", err)
+	}
+
+	content, err := renderStreamed(g.Config.Target, tmpl, node)
+	if err != nil {
+		return fmt.Errorf("failed to render permissions template: %w", err)
+	}
+	e.report.record(node.Name, "permissions", time.Since(start), content)
+
+	return e.writeGeneratedFile(g, node, "permissions", content)
+}
+
+// generatedHeader prefixes every file entdomain owns and may overwrite.
+const generatedHeader = "// Code generated by entdomain extension"
+
+// generatedFilename returns the file name for a generated file kind, e.g.
+// "user_dto.go", or "user_dto.gen.go" when GenSuffix is set.
+func (e *Extension) generatedFilename(node *gen.Type, kind string) string {
+	name := fmt.Sprintf("%s_%s", strings.ToLower(node.Name), kind)
+	if e.Config.GenSuffix {
+		return name + ".gen.go"
+	}
+	return name + ".go"
+}
+
+// writeGeneratedFile writes a generated file kind for node, or, with
+// AggregatedOutput, adds it to the aggregated file of its kind.
+func (e *Extension) writeGeneratedFile(g *gen.Graph, node *gen.Type, kind string, content []byte) error {
+	if e.Config.AggregatedOutput {
+		if e.aggregated == nil {
+			e.aggregated = make(map[string]*aggregatedFile)
+		}
+		file, ok := e.aggregated[kind]
+		if !ok {
+			file = &aggregatedFile{}
+			e.aggregated[kind] = file
+		}
+		if err := file.add(node.Name, content); err != nil {
+			return fmt.Errorf("failed to aggregate %s %s: %w", node.Name, kind, err)
+		}
+		return nil
+	}
+	return e.writeGeneratedPath(g, e.generatedFilename(node, kind), content)
+}
+
+// writeGeneratedPath writes a generated file under filename. When the file
+// still exists under the other naming convention (GenSuffix was toggled),
+// it is renamed first so its keep regions carry over and no duplicate
+// declarations are left behind.
+func (e *Extension) writeGeneratedPath(g *gen.Graph, filename string, content []byte) error {
 	outputPath := filepath.Join(g.Config.Target, filename)
 
-	return writeFile(outputPath, buf.Bytes())
+	stale := strings.TrimSuffix(filename, ".gen.go") + ".go"
+	if !e.Config.GenSuffix {
+		stale = strings.TrimSuffix(filename, ".go") + ".gen.go"
+	}
+	stalePath := filepath.Join(g.Config.Target, stale)
+	if _, err := os.Stat(outputPath); os.IsNotExist(err) && isGeneratedFile(stalePath) {
+		if err := os.Rename(stalePath, outputPath); err != nil {
+			return fmt.Errorf("failed to rename %s: %w", stalePath, err)
+		}
+	}
+
+	return writeFile(outputPath, content)
+}
+
+// isGeneratedFile reports whether the file at path exists and was written by entdomain.
+func isGeneratedFile(path string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return bytes.HasPrefix(content, []byte(generatedHeader))
+}
+
+// template returns the parsed template for name, parsing text with the
+// extension's function map on first use.
+func (e *Extension) template(name, text string) (*template.Template, error) {
+	if tmpl, ok := e.templates[name]; ok {
+		return tmpl, nil
+	}
+	tmpl, err := template.New(name).Funcs(e.templateFuncMap()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if e.templates == nil {
+		e.templates = make(map[string]*template.Template)
+	}
+	e.templates[name] = tmpl
+	return tmpl, nil
+}
+
+// renderStreamed executes tmpl over data through a buffered writer into a
+// temporary file in dir and reads the result back in a single allocation of
+// the exact size. For large outputs this avoids the repeated doubling (and
+// the garbage left behind) of rendering into a growing bytes.Buffer, which
+// dominated peak memory on graphs with hundreds of entities.
+func renderStreamed(dir string, tmpl *template.Template, data any) ([]byte, error) {
+	f, err := os.CreateTemp(dir, ".entdomain-*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	err = tmpl.Execute(w, data)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(f.Name())
 }
 
-// writeFile formats the generated Go source with goimports and writes it to disk
+// importsOptions is shared by every goimports run instead of being
+// allocated per file. The values match goimports' defaults.
+var importsOptions = &imports.Options{Comments: true, TabIndent: true, TabWidth: 8}
+
+// writeFile formats the generated Go source with goimports and writes it to disk.
+// Keep regions of an existing file at path are merged into content first.
 func writeFile(path string, content []byte) error {
-	formatted, err := imports.Process(path, content, nil)
+	if existing, err := os.ReadFile(path); err == nil {
+		merged, orphans, err := mergeKeepRegions(content, existing)
+		if err != nil {
+			return fmt.Errorf("failed to merge keep regions into %s: %w", path, err)
+		}
+		if len(orphans) > 0 {
+			log.Printf("WARNING: keep regions %v in %s no longer exist in the template (appended at end of file)", orphans, path)
+		}
+		content = merged
+	}
+	formatted, err := imports.Process(path, content, importsOptions)
 	if err != nil {
 		log.Printf("WARNING: goimports formatting failed for %s: %v (writing unformatted)", path, err)
 		formatted = content
@@ -190,6 +716,9 @@ func (e *Extension) templateFuncMap() template.FuncMap {
 	pkg := e.Config.EntDomainPackage
 	funcs["entdomainPkg"] = func() string { return pkg }
 
+	cfg := e.Config
+	funcs["extensionConfig"] = func() *ExtensionConfig { return cfg }
+
 	return funcs
 }
 
@@ -210,6 +739,166 @@ func WithBaseHandler(generate bool) Option {
 	}
 }
 
+// WithServiceExtensions controls whether once-only {entity}_domain_service_ext.go scaffolds are generated
+func WithServiceExtensions(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenerateServiceExtensions = generate
+	}
+}
+
+// WithExampleTests controls whether compiled {entity}_example_test.go usage examples are generated
+func WithExampleTests(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenerateExampleTests = generate
+	}
+}
+
+// WithBenchmarks controls whether SQLite-backed {entity}_bench_test.go benchmarks are generated
+func WithBenchmarks(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenerateBenchmarks = generate
+	}
+}
+
+// WithMocks controls whether {entity}_domain_mocks.go repository mocks are generated
+func WithMocks(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenerateMocks = generate
+	}
+}
+
+// WithTracing controls whether {entity}_domain_tracing.go tracing decorators are generated
+func WithTracing(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenerateTracing = generate
+	}
+}
+
+// WithImporter controls whether {entity}_importer.go bulk importers are generated
+func WithImporter(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenerateImporter = generate
+	}
+}
+
+// WithSchemaSnapshot controls whether a schema snapshot for runtime drift detection is generated
+func WithSchemaSnapshot(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenerateSchemaSnapshot = generate
+	}
+}
+
+// WithPermissions controls whether RBAC permission constants are generated
+func WithPermissions(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GeneratePermissions = generate
+	}
+}
+
+// WithStrictAuthorization makes generated services fail closed when no Authorizer is configured
+func WithStrictAuthorization(strict bool) Option {
+	return func(c *ExtensionConfig) {
+		c.StrictAuthorization = strict
+	}
+}
+
+// WithSearchIndexing controls whether Reindex methods for search backends are generated
+func WithSearchIndexing(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenerateSearchIndexing = generate
+	}
+}
+
+// WithUpsert controls whether Upsert methods keyed on Unique fields are generated
+func WithUpsert(generate bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenerateUpsert = generate
+	}
+}
+
+// WithBatchChunkSize sets the number of rows batch methods write per statement
+func WithBatchChunkSize(size int) Option {
+	return func(c *ExtensionConfig) {
+		c.BatchChunkSize = size
+	}
+}
+
+// WithGenSuffix controls whether generated files use the .gen.go suffix
+func WithGenSuffix(enabled bool) Option {
+	return func(c *ExtensionConfig) {
+		c.GenSuffix = enabled
+	}
+}
+
+// WithAggregatedOutput controls whether each generated kind is written as one
+// file for all entities
+func WithAggregatedOutput(enabled bool) Option {
+	return func(c *ExtensionConfig) {
+		c.AggregatedOutput = enabled
+	}
+}
+
+// WithAPIVersion sets the default API version used as a prefix for generated routes
+func WithAPIVersion(version string) Option {
+	return func(c *ExtensionConfig) {
+		c.APIVersion = version
+	}
+}
+
+// WithDefaultFieldAnnotation treats fields without a DomainField annotation as
+// if they carried annotation, e.g. DefaultField(), instead of skipping them
+func WithDefaultFieldAnnotation(annotation DomainField) Option {
+	return func(c *ExtensionConfig) {
+		c.DefaultFieldAnnotation = &annotation
+	}
+}
+
+// WithDefaultFieldAnnotationFor overrides the default annotation for unannotated
+// fields of one type, e.g. OutputOnlyField() for field.TypeTime
+func WithDefaultFieldAnnotationFor(typ field.Type, annotation DomainField) Option {
+	return func(c *ExtensionConfig) {
+		if c.DefaultFieldAnnotations == nil {
+			c.DefaultFieldAnnotations = make(map[field.Type]DomainField)
+		}
+		c.DefaultFieldAnnotations[typ] = annotation
+	}
+}
+
+// WithIntegerIDsAsStrings controls whether integer IDs in Response DTOs are encoded as JSON strings
+func WithIntegerIDsAsStrings(enabled bool) Option {
+	return func(c *ExtensionConfig) {
+		c.IntegerIDsAsStrings = enabled
+	}
+}
+
+// WithTypedIDs controls whether per-entity typed IDs are generated and used in service signatures
+func WithTypedIDs(enabled bool) Option {
+	return func(c *ExtensionConfig) {
+		c.TypedIDs = enabled
+	}
+}
+
+// WithIDValidation controls whether generated services validate IDs with an IDValidator before querying
+func WithIDValidation(enabled bool) Option {
+	return func(c *ExtensionConfig) {
+		c.IDValidation = enabled
+	}
+}
+
+// WithStrict turns silently skipped schemas and ignored annotations into generation errors
+func WithStrict(strict bool) Option {
+	return func(c *ExtensionConfig) {
+		c.Strict = strict
+	}
+}
+
+// WithReport writes a generation timing and statistics report to w
+func WithReport(w io.Writer) Option {
+	return func(c *ExtensionConfig) {
+		c.Report = w
+	}
+}
+
 // WithEntDomainPackage sets the import path for the entdomain package
 func WithEntDomainPackage(pkg string) Option {
 	return func(c *ExtensionConfig) {
@@ -1,9 +1,18 @@
 package entdomain
 
 import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"text/template"
 
 	"entgo.io/ent/entc/gen"
+	"entgo.io/ent/schema/field"
 )
 
 func TestExtension_NewExtension(t *testing.T) {
@@ -103,6 +112,220 @@ func TestExtensionOptions(t *testing.T) {
 			t.Error("GenerateBaseHandler should be true")
 		}
 	})
+
+	t.Run("WithStrictAuthorization", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithStrictAuthorization(true)
+		opt(config)
+
+		if !config.StrictAuthorization {
+			t.Error("StrictAuthorization should be true")
+		}
+	})
+
+	t.Run("WithPermissions", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithPermissions(true)
+		opt(config)
+
+		if !config.GeneratePermissions {
+			t.Error("GeneratePermissions should be true")
+		}
+	})
+
+	t.Run("WithServiceExtensions", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithServiceExtensions(true)
+		opt(config)
+
+		if !config.GenerateServiceExtensions {
+			t.Error("GenerateServiceExtensions should be true")
+		}
+	})
+
+	t.Run("WithExampleTests", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithExampleTests(true)
+		opt(config)
+
+		if !config.GenerateExampleTests {
+			t.Error("GenerateExampleTests should be true")
+		}
+	})
+
+	t.Run("WithBenchmarks", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithBenchmarks(true)
+		opt(config)
+
+		if !config.GenerateBenchmarks {
+			t.Error("GenerateBenchmarks should be true")
+		}
+	})
+
+	t.Run("WithMocks", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithMocks(true)
+		opt(config)
+
+		if !config.GenerateMocks {
+			t.Error("GenerateMocks should be true")
+		}
+	})
+
+	t.Run("WithTracing", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithTracing(true)
+		opt(config)
+
+		if !config.GenerateTracing {
+			t.Error("GenerateTracing should be true")
+		}
+	})
+
+	t.Run("WithImporter", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithImporter(true)
+		opt(config)
+
+		if !config.GenerateImporter {
+			t.Error("GenerateImporter should be true")
+		}
+	})
+
+	t.Run("WithSchemaSnapshot", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithSchemaSnapshot(true)
+		opt(config)
+
+		if !config.GenerateSchemaSnapshot {
+			t.Error("GenerateSchemaSnapshot should be true")
+		}
+	})
+
+	t.Run("WithGenSuffix", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithGenSuffix(true)
+		opt(config)
+
+		if !config.GenSuffix {
+			t.Error("GenSuffix should be true")
+		}
+	})
+
+	t.Run("WithAPIVersion", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithAPIVersion("v1")
+		opt(config)
+
+		if config.APIVersion != "v1" {
+			t.Errorf("APIVersion = %q, want %q", config.APIVersion, "v1")
+		}
+	})
+
+	t.Run("WithDefaultFieldAnnotation", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		WithDefaultFieldAnnotation(DefaultField())(config)
+		WithDefaultFieldAnnotationFor(field.TypeTime, OutputOnlyField())(config)
+
+		if config.DefaultFieldAnnotation == nil || len(config.DefaultFieldAnnotation.Scopes) != len(AllFieldScopes) {
+			t.Errorf("DefaultFieldAnnotation = %+v, want DefaultField()", config.DefaultFieldAnnotation)
+		}
+		if _, ok := config.DefaultFieldAnnotations[field.TypeTime]; !ok {
+			t.Error("DefaultFieldAnnotations should have a field.TypeTime override")
+		}
+	})
+
+	t.Run("WithIntegerIDsAsStrings", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithIntegerIDsAsStrings(true)
+		opt(config)
+
+		if !config.IntegerIDsAsStrings {
+			t.Error("IntegerIDsAsStrings should be true")
+		}
+	})
+
+	t.Run("WithTypedIDs", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithTypedIDs(true)
+		opt(config)
+
+		if !config.TypedIDs {
+			t.Error("TypedIDs should be true")
+		}
+	})
+
+	t.Run("WithIDValidation", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithIDValidation(true)
+		opt(config)
+
+		if !config.IDValidation {
+			t.Error("IDValidation should be true")
+		}
+	})
+
+	t.Run("WithStrict", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithStrict(true)
+		opt(config)
+
+		if !config.Strict {
+			t.Error("Strict should be true")
+		}
+	})
+
+	t.Run("WithReport", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		var buf bytes.Buffer
+		opt := WithReport(&buf)
+		opt(config)
+
+		if config.Report != &buf {
+			t.Error("Report should be the given writer")
+		}
+	})
+
+	t.Run("WithSearchIndexing", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithSearchIndexing(true)
+		opt(config)
+
+		if !config.GenerateSearchIndexing {
+			t.Error("GenerateSearchIndexing should be true")
+		}
+	})
+
+	t.Run("WithUpsert", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithUpsert(true)
+		opt(config)
+
+		if !config.GenerateUpsert {
+			t.Error("GenerateUpsert should be true")
+		}
+	})
+
+	t.Run("WithAggregatedOutput", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithAggregatedOutput(true)
+		opt(config)
+
+		if !config.AggregatedOutput {
+			t.Error("AggregatedOutput should be true")
+		}
+	})
+
+	t.Run("WithBatchChunkSize", func(t *testing.T) {
+		config := &ExtensionConfig{}
+		opt := WithBatchChunkSize(100)
+		opt(config)
+
+		if config.BatchChunkSize != 100 {
+			t.Errorf("BatchChunkSize = %d, want 100", config.BatchChunkSize)
+		}
+	})
 }
 
 func TestWithEntDomainPackage(t *testing.T) {
@@ -180,6 +403,15 @@ func TestExtension_TemplateFuncMap(t *testing.T) {
 		t.Errorf("entdomainPkg() = %q, want %q", got, customPkg)
 	}
 
+	// Verify "extensionConfig" exposes the extension's configuration
+	cfgFn, ok := funcMap["extensionConfig"].(func() *ExtensionConfig)
+	if !ok {
+		t.Fatalf("extensionConfig has unexpected type %T, want func() *ExtensionConfig", funcMap["extensionConfig"])
+	}
+	if cfgFn() != ext.Config {
+		t.Error("extensionConfig() should return the extension's Config")
+	}
+
 	// Verify it does not mutate the global gen.Funcs map
 	genFuncsBefore := make(map[string]bool, len(gen.Funcs))
 	for k := range gen.Funcs {
@@ -219,3 +451,404 @@ func TestNewExtensionWithOptions_EntDomainPackage(t *testing.T) {
 		t.Errorf("EntDomainPackage = %q, want %q", ext.Config.EntDomainPackage, customPkg)
 	}
 }
+
+func TestExtension_GenerateDomainServiceExtFile(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &gen.Config{Target: dir, Package: "example.com/app/ent"}
+	g := &gen.Graph{Config: cfg}
+	node := newUUIDTestType("User", newStringField("name", ptr(DefaultField())))
+	node.Config = cfg
+	ext := NewExtension(&ExtensionConfig{GenerateBaseService: true, GenerateServiceExtensions: true})
+	path := filepath.Join(dir, "user_domain_service_ext.go")
+
+	if err := ext.generateDomainServiceExtFile(g, node); err != nil {
+		t.Fatalf("generateDomainServiceExtFile() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("scaffold not written: %v", err)
+	}
+	for _, want := range []string{"type UserDomainService struct", "BaseUserService", "func NewUserDomainService(db *Client)"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("scaffold missing %q", want)
+		}
+	}
+
+	// An existing file, including any custom code in it, must be left alone.
+	custom := []byte("package ent\n\n// custom code\n")
+	if err := os.WriteFile(path, custom, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ext.generateDomainServiceExtFile(g, node); err != nil {
+		t.Fatalf("generateDomainServiceExtFile() second run error = %v", err)
+	}
+	content, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != string(custom) {
+		t.Errorf("existing scaffold was overwritten:\n%s", content)
+	}
+}
+
+func TestExtension_GeneratedFilename(t *testing.T) {
+	node := newTestType("User")
+
+	plain := NewExtension(&ExtensionConfig{})
+	if got := plain.generatedFilename(node, "dto"); got != "user_dto.go" {
+		t.Errorf("generatedFilename() = %q, want %q", got, "user_dto.go")
+	}
+
+	suffixed := NewExtension(&ExtensionConfig{GenSuffix: true})
+	if got := suffixed.generatedFilename(node, "base_service"); got != "user_base_service.gen.go" {
+		t.Errorf("generatedFilename() = %q, want %q", got, "user_base_service.gen.go")
+	}
+}
+
+func TestExtension_WriteGeneratedFile_RenamesStale(t *testing.T) {
+	dir := t.TempDir()
+	g := &gen.Graph{Config: &gen.Config{Target: dir}}
+	node := newTestType("User")
+
+	stale := filepath.Join(dir, "user_dto.go")
+	old := generatedHeader + ". DO NOT EDIT.\n\npackage ent\n\n// entdomain:begin keep custom\nconst Custom = 1\n// entdomain:end keep\n"
+	if err := os.WriteFile(stale, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ext := NewExtension(&ExtensionConfig{GenSuffix: true})
+	content := generatedHeader + ". DO NOT EDIT.\n\npackage ent\n\n// entdomain:begin keep custom\n// entdomain:end keep\n"
+	if err := ext.writeGeneratedFile(g, node, "dto", []byte(content)); err != nil {
+		t.Fatalf("writeGeneratedFile() error = %v", err)
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale %s should have been removed, stat error = %v", stale, err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "user_dto.gen.go"))
+	if err != nil {
+		t.Fatalf("user_dto.gen.go not written: %v", err)
+	}
+	if !strings.Contains(string(got), "const Custom = 1") {
+		t.Errorf("keep region lost while renaming:\n%s", got)
+	}
+}
+
+func TestExtension_WriteGeneratedFile_LeavesHandWrittenFiles(t *testing.T) {
+	dir := t.TempDir()
+	g := &gen.Graph{Config: &gen.Config{Target: dir}}
+	node := newTestType("User")
+
+	handWritten := filepath.Join(dir, "user_dto.go")
+	if err := os.WriteFile(handWritten, []byte("package ent\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ext := NewExtension(&ExtensionConfig{GenSuffix: true})
+	if err := ext.writeGeneratedFile(g, node, "dto", []byte("package ent\n")); err != nil {
+		t.Fatalf("writeGeneratedFile() error = %v", err)
+	}
+	if _, err := os.Stat(handWritten); err != nil {
+		t.Errorf("hand-written %s should be untouched: %v", handWritten, err)
+	}
+}
+
+func TestExtension_TemplateIsCached(t *testing.T) {
+	ext := NewExtension(nil)
+	first, err := ext.template("permissions", permissionsTemplate)
+	if err != nil {
+		t.Fatalf("template() error = %v", err)
+	}
+	second, err := ext.template("permissions", permissionsTemplate)
+	if err != nil {
+		t.Fatalf("template() error = %v", err)
+	}
+	if first != second {
+		t.Error("template() should return the cached template on second use")
+	}
+}
+
+func TestRenderStreamed(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := template.Must(template.New("t").Parse("package {{ . }}\n"))
+
+	got, err := renderStreamed(dir, tmpl, "ent")
+	if err != nil {
+		t.Fatalf("renderStreamed() error = %v", err)
+	}
+	if string(got) != "package ent\n" {
+		t.Errorf("renderStreamed() = %q, want %q", got, "package ent\n")
+	}
+
+	_, err = renderStreamed(dir, template.Must(template.New("t").Parse("{{ .Missing }}")), "ent")
+	if err == nil {
+		t.Error("renderStreamed() should return execution errors")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestExtension_BaseServiceSkipsSoftDeleted(t *testing.T) {
+	deleted := newTimeField("deleted_at", nil)
+	deleted.Optional, deleted.Nillable = true, true
+	node := newUUIDTestType("User", newStringField("name", ptr(DefaultField())), deleted)
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	for _, signature := range []string{
+		"func (s *BaseUserService) GetByID(",
+		"func (s *BaseUserService) GetByIDs(",
+		"func (s *BaseUserService) Iterate(",
+		"func userSearchQuery(",
+	} {
+		assertContains(t, generatedFunc(t, src, signature), "user.DeletedAtIsNil()")
+	}
+	// List and Search read through userSearchQuery, unless IncludeDeleted.
+	assertContains(t, generatedFunc(t, src, "func userSearchQuery("), "if !req.IncludeDeleted {")
+	assertContains(t, generatedFunc(t, src, "func (s *BaseUserService) ListDeleted("), "IncludeDeleted: true")
+
+	plain := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), newUUIDTestType("Tag", newStringField("name", ptr(DefaultField()))))
+	assertNotContains(t, plain, "DeletedAtIsNil")
+
+	// Owned rows: owned... cannot follow another argument of Where.
+	node.Fields = append(node.Fields, newUUIDField("user_id", ptr(DefaultField())))
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOwnerField("user_id")}
+	owned := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+	for _, signature := range []string{"func (s *BaseUserService) Restore(", "func (s *BaseUserService) Purge("} {
+		restore := generatedFunc(t, owned, signature)
+		assertContains(t, restore, "Where(owned...)")
+		assertNotContains(t, restore, ", owned...)")
+	}
+}
+
+func TestExtension_BaseServiceWritesSkipSoftDeleted(t *testing.T) {
+	deleted := newTimeField("deleted_at", nil)
+	deleted.Optional, deleted.Nillable = true, true
+	node := newUUIDTestType("User", newStringField("name", ptr(DefaultField())), newTimeField("updated_at", nil), deleted)
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	// A second Delete would stamp deleted_at again and publish another event.
+	for _, signature := range []string{
+		"func (s *BaseUserService) Update(",
+		"func (s *BaseUserService) Delete(",
+		"func (s *BaseUserService) Touch(",
+	} {
+		assertContains(t, generatedFunc(t, src, signature), ".Where(user.DeletedAtIsNil())")
+	}
+}
+
+func TestExtension_BaseServiceFindOrCreateSkipsOthers(t *testing.T) {
+	deleted := newTimeField("deleted_at", nil)
+	deleted.Optional, deleted.Nillable = true, true
+	node := newUUIDTestType("User", newStringField("email", ptr(DefaultField().AsUniqueLookup())), newUUIDField("org_id", ptr(DefaultField())), deleted)
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOwnerField("org_id")}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	find := generatedFunc(t, src, "func (s *BaseUserService) FindOrCreateByEmail(")
+	assertContains(t, find, "user.EmailEQ(value), user.DeletedAtIsNil()")
+	assertContains(t, find, "Where(owned...)")
+}
+
+func TestExtension_BaseServiceUpdateRequiresVersionFirst(t *testing.T) {
+	node := newUUIDTestType("Post", newStringField("title", ptr(DefaultField())), newIntField("version", nil))
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOptimisticLock("version")}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	// A request without a version fails before the Authorizer and the hooks.
+	update := generatedFunc(t, src, "func (s *BasePostService) Update(")
+	required := strings.Index(update, "version is required")
+	if required < 0 || required > strings.Index(update, "s.authorize(") || required > strings.Index(update, "s.beforeUpdate(") {
+		t.Errorf("Update checks the version after authorize or the hooks:\n%s", update)
+	}
+}
+
+func TestExtension_BaseServiceEstimatesUnnarrowedCounts(t *testing.T) {
+	deleted := newTimeField("deleted_at", nil)
+	deleted.Optional, deleted.Nillable = true, true
+	node := newUUIDTestType("Post", newStringField("title", ptr(DefaultField())), newUUIDField("user_id", ptr(DefaultField())), deleted)
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOwnerField("user_id")}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	search := generatedFunc(t, src, "func (s *BasePostService) SearchEntitiesWithPredicates(")
+	assertContains(t, search, "filtered := req.HasFilters() || len(ps) > 0 || len(owned) > 0 || !req.IncludeDeleted")
+	assertContains(t, search, "s.countTotal(ctx, query, req.EffectiveCountMode(), filtered, distinctOn)")
+}
+
+func TestExtension_BaseServicePublishesThroughOutboxOnly(t *testing.T) {
+	node := newUUIDTestType("User", newStringField("name", ptr(DefaultField())))
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithEvents()}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	// Publishing to EventBus too would deliver the event again with the relay.
+	publish := generatedFunc(t, src, "func (s *BaseUserService) publish(")
+	assertContains(t, publish, "return s.Outbox.Append(ctx, db.driver, msg)")
+}
+
+func TestExtension_BaseServiceScopesToOwner(t *testing.T) {
+	node := newUUIDTestType("Post", newStringField("title", ptr(DefaultField())), newUUIDField("user_id", ptr(DefaultField())))
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOwnerField("user_id")}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	assertContains(t, generatedFunc(t, src, "func (s *BasePostService) Iterate("), "Where(owned...)")
+	assertContains(t, generatedFunc(t, src, "func (s *BasePostService) create("), "s.ownCreate(ctx, builder.Mutation())")
+	own := generatedFunc(t, src, "func (s *BasePostService) ownCreate(")
+	assertContains(t, own, "m.SetUserID(owner)")
+	assertContains(t, own, "entdomain.ErrForbidden")
+	// Requests leaving a required owner field out carry its zero value.
+	assertContains(t, own, "!ok || v == zero")
+}
+
+func TestExtension_BaseServiceFieldMutationsScopedToOwner(t *testing.T) {
+	status := newStringField("status", ptr(DefaultField()))
+	node := newUUIDTestType("Post", status, newUUIDField("user_id", ptr(DefaultField())))
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOwnerField("user_id").WithFieldMutations().WithCache()}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	for _, signature := range []string{
+		"func (s *BasePostService) DeleteByStatus(",
+		"func (s *BasePostService) CountByStatus(",
+		"func (s *PostCachedService) DeleteByStatus(",
+	} {
+		assertContains(t, generatedFunc(t, src, signature), "Where(owned...)")
+	}
+	assertNotContains(t, src, "DeleteByUserID")
+}
+
+func TestExtension_BaseServiceFieldMutationsMatchExactly(t *testing.T) {
+	email := newStringField("email", ptr(DefaultField().AsCaseInsensitive()))
+	node := newUUIDTestType("User", email)
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithFieldMutations().WithCache()}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	for _, signature := range []string{
+		"func (s *BaseUserService) DeleteByEmail(",
+		"func (s *BaseUserService) CountByEmail(",
+		"func (s *UserCachedService) DeleteByEmail(",
+	} {
+		fn := generatedFunc(t, src, signature)
+		assertContains(t, fn, "user.EmailEQ(value)")
+		assertNotContains(t, fn, "EmailEqualFold")
+	}
+}
+
+func TestExtension_BaseServiceUpsertKeepsOwners(t *testing.T) {
+	slug := newStringField("slug", ptr(DefaultField()))
+	slug.Unique = true
+	node := newUUIDTestType("Post", slug, newUUIDField("user_id", ptr(DefaultField())))
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOwnerField("user_id")}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true, GenerateUpsert: true}), node, gen.FeatureUpsert)
+
+	// Conflicting rows of other owners are not overwritten, nor read back.
+	upsert := generatedFunc(t, src, "func (s *BasePostService) UpsertBy(")
+	assertContains(t, upsert, "s.upsertsOwned(ctx, db, owned, match)")
+	assertContains(t, upsert, "Where(match).Where(owned...).Only(ctx)")
+	assertContains(t, generatedFunc(t, src, "func (s *BasePostService) UpsertBatch("), "s.upsertsOwned(ctx, db, owned, post.SlugIn(values...))")
+	assertContains(t, generatedFunc(t, src, "func (s *BasePostService) upsertsOwned("), "entdomain.ErrForbidden")
+}
+
+func TestExtension_BaseServiceIdempotentCreate(t *testing.T) {
+	node := newUUIDTestType("User", newStringField("name", ptr(DefaultField())))
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	create := generatedFunc(t, src, "func (s *BaseUserService) Create(")
+	authorized := strings.Index(create, "s.authorize(ctx, entdomain.ActionCreate, nil)")
+	if replayed := strings.Index(create, "entdomain.Idempotent("); authorized < 0 || replayed < authorized {
+		t.Errorf("Create should authorize before replaying:\n%s", create)
+	}
+	// Only the ID is recorded; replays read the entity again.
+	assertContains(t, create, "func(ctx context.Context) (id uuid.UUID, err error)")
+	assertContains(t, create, "return s.GetByID(ctx, id)")
+	assertNotContains(t, generatedFunc(t, src, "func (s *BaseUserService) create("), "s.authorize(")
+}
+
+func TestExtension_BaseServiceCacheInvalidation(t *testing.T) {
+	node := newUUIDTestType("User", newStringField("name", ptr(DefaultField())))
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithCache().WithFieldMutations()}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	// Entries are dropped once the transaction commits, not before.
+	invalidate := generatedFunc(t, src, "func (s *UserCachedService) invalidate(")
+	assertContains(t, invalidate, "tx.OnCommit(")
+	assertContains(t, invalidate, "next.Commit(ctx, tx)")
+	deleteBy := generatedFunc(t, src, "func (s *UserCachedService) DeleteByName(")
+	assertContains(t, deleteBy, "s.invalidate(ctx, keys...)")
+}
+
+func TestExtension_BaseServiceCacheAttachesClient(t *testing.T) {
+	node := newUUIDTestType("User", newStringField("name", ptr(DefaultField())))
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithCache()}
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	// Copies decoded by a DecodingCache need a client for edge queries.
+	get := generatedFunc(t, src, "func (s *UserCachedService) GetByID(")
+	assertContains(t, get, "if entity.driver == nil {")
+	assertContains(t, get, "entity.config = db.config")
+}
+
+func TestExtension_BaseServiceUpdateIfMatchClaimsVersion(t *testing.T) {
+	node := newUUIDTestType("User",
+		newStringField("name", ptr(DefaultField())),
+		newTimeField("updated_at", ptr(OutputOnlyField())),
+	)
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	// Writers holding the same etag race for the row: only one may match.
+	update := generatedFunc(t, src, "func (s *BaseUserService) UpdateIfMatch(")
+	assertContains(t, update, "user.UpdatedAtEQ(current.UpdatedAt)")
+	assertContains(t, update, "if n == 0 {")
+	if claimed, updated := strings.Index(update, "claim.SetUpdatedAt("), strings.Index(update, "s.Update(ctx, id, req)"); claimed < 0 || updated < claimed {
+		t.Errorf("UpdateIfMatch should claim updated_at before updating:\n%s", update)
+	}
+}
+
+func TestExtension_BaseServiceEntityNamedOrder(t *testing.T) {
+	node := newUUIDTestType("Order", newStringField("number", ptr(DefaultField())), newIntField("total", ptr(DefaultField())))
+	src := renderBaseService(t, NewExtension(&ExtensionConfig{GenerateBaseService: true}), node)
+
+	// The generated code refers to the order package, so no local name may
+	// shadow it.
+	file, err := parser.ParseFile(token.NewFileSet(), "order_base_service.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packageRefs := make(map[*ast.Ident]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				packageRefs[x] = true
+			}
+		}
+		return true
+	})
+	ast.Inspect(file, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == "order" && !packageRefs[id] {
+			t.Errorf("identifier order shadows the order package at offset %d", id.Pos())
+		}
+		return true
+	})
+}
+
+func TestExtension_GenerateSchemaSnapshotFile_RenamesStale(t *testing.T) {
+	dir := t.TempDir()
+	g := &gen.Graph{Config: &gen.Config{Target: dir, Package: "example.com/app/ent"}}
+	g.Nodes = []*gen.Type{newTestType("User", newStringField("name", nil))}
+	g.Nodes[0].Config = g.Config
+
+	if err := NewExtension(&ExtensionConfig{}).generateSchemaSnapshotFile(g); err != nil {
+		t.Fatalf("generateSchemaSnapshotFile() error = %v", err)
+	}
+	if err := NewExtension(&ExtensionConfig{GenSuffix: true}).generateSchemaSnapshotFile(g); err != nil {
+		t.Fatalf("generateSchemaSnapshotFile() with GenSuffix error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "entdomain_schema_snapshot.go")); !os.IsNotExist(err) {
+		t.Errorf("stale snapshot should have been removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "entdomain_schema_snapshot.gen.go")); err != nil {
+		t.Errorf("entdomain_schema_snapshot.gen.go not written: %v", err)
+	}
+}
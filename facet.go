@@ -0,0 +1,100 @@
+package entdomain
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultFacetLimit is the number of values per facet when
+	// FacetRequest.FacetLimit is not specified.
+	DefaultFacetLimit = 10
+
+	// MaxFacetLimit is the maximum allowed number of values per facet.
+	MaxFacetLimit = 100
+)
+
+// FacetRequest is a SearchRequest that also asks for facets: for some
+// filterable fields, the number of matching entities per value, as shown in
+// the filter sidebar of a search page.
+type FacetRequest struct {
+	SearchRequest
+
+	// Facets lists the column names of the filterable fields to count. Empty
+	// counts every filterable field.
+	Facets []string `json:"facets,omitempty"`
+
+	// FacetLimit caps the values returned per facet, keeping the most
+	// frequent ones.
+	FacetLimit int `json:"facet_limit,omitempty"`
+}
+
+// SetDefaults fills in the defaults of the SearchRequest and FacetLimit.
+func (r *FacetRequest) SetDefaults() {
+	r.SearchRequest.SetDefaults()
+	if r.FacetLimit == 0 {
+		r.FacetLimit = DefaultFacetLimit
+	}
+}
+
+// Validate checks the embedded SearchRequest and the facet fields and limit.
+// Like ListRequest.Validate, it returns plain errors.
+func (r *FacetRequest) Validate() error {
+	if r == nil {
+		return fmt.Errorf("facet request cannot be nil")
+	}
+	if err := r.SearchRequest.Validate(); err != nil {
+		return err
+	}
+	if r.FacetLimit < 1 || r.FacetLimit > MaxFacetLimit {
+		return fmt.Errorf("facet_limit must be between 1 and %d", MaxFacetLimit)
+	}
+	for i, f := range r.Facets {
+		if slices.Contains(r.Facets[:i], f) {
+			return fmt.Errorf("facet %q is listed twice", f)
+		}
+	}
+	return nil
+}
+
+// FacetResult maps each facet field to the number of matching entities per
+// value. Values are keyed by FacetKey.
+type FacetResult map[string]map[string]int
+
+// FacetKey returns the key of a field value in a FacetResult: times in
+// RFC 3339 form, which Filters accept back, and other values in their fmt
+// form.
+func FacetKey(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+// LimitFacet returns counts reduced to its limit most frequent values, ties
+// going to the smaller key. A non-positive limit keeps every value.
+func LimitFacet(counts map[string]int, limit int) map[string]int {
+	if limit <= 0 || len(counts) <= limit {
+		return counts
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	top := make(map[string]int, limit)
+	for _, k := range keys[:limit] {
+		top[k] = counts[k]
+	}
+	return top
+}
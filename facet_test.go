@@ -0,0 +1,59 @@
+package entdomain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFacetRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     FacetRequest
+		wantErr bool
+	}{
+		{"defaults", FacetRequest{}, false},
+		{"fields", FacetRequest{Facets: []string{"status", "age"}}, false},
+		{"duplicate field", FacetRequest{Facets: []string{"status", "status"}}, true},
+		{"negative limit", FacetRequest{FacetLimit: -1}, true},
+		{"excessive limit", FacetRequest{FacetLimit: MaxFacetLimit + 1}, true},
+		{"invalid search", FacetRequest{SearchRequest: SearchRequest{ListRequest: ListRequest{Page: -1}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.SetDefaults()
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	req := FacetRequest{}
+	req.SetDefaults()
+	if req.FacetLimit != DefaultFacetLimit || req.Size != DefaultPageSize {
+		t.Errorf("SetDefaults() = %+v", req)
+	}
+}
+
+func TestFacetKey(t *testing.T) {
+	type status string
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	for v, want := range map[any]string{"a": "a", status("active"): "active", 42: "42", true: "true", at: "2024-01-02T15:04:05Z"} {
+		if got := FacetKey(v); got != want {
+			t.Errorf("FacetKey(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestLimitFacet(t *testing.T) {
+	counts := map[string]int{"a": 1, "b": 3, "c": 2, "d": 2}
+	if got, want := LimitFacet(counts, 2), map[string]int{"b": 3, "c": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LimitFacet(2) = %v, want %v", got, want)
+	}
+	if got := LimitFacet(counts, 10); !reflect.DeepEqual(got, counts) {
+		t.Errorf("LimitFacet(10) = %v", got)
+	}
+	if got := LimitFacet(counts, 0); !reflect.DeepEqual(got, counts) {
+		t.Errorf("LimitFacet(0) = %v", got)
+	}
+}
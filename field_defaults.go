@@ -0,0 +1,45 @@
+package entdomain
+
+import (
+	"entgo.io/ent/entc/gen"
+)
+
+// applyDefaultFieldAnnotations gives every field without a DomainField
+// annotation the configured default: the per-type override for its field
+// type if there is one, otherwise DefaultFieldAnnotation. Sensitive fields
+// are left alone so a blanket default can never expose them in responses;
+// annotate them explicitly.
+func (e *Extension) applyDefaultFieldAnnotations(nodes []*gen.Type) {
+	c := e.Config
+	if c.DefaultFieldAnnotation == nil && len(c.DefaultFieldAnnotations) == 0 {
+		return
+	}
+	for _, node := range nodes {
+		for _, f := range node.Fields {
+			if f.Sensitive() || getDomainFieldAnnotation(f) != nil {
+				continue
+			}
+			annotation, ok := defaultFieldAnnotation(c, f)
+			if !ok {
+				continue
+			}
+			if f.Annotations == nil {
+				f.Annotations = gen.Annotations{}
+			}
+			f.Annotations[annotation.Name()] = &annotation
+		}
+	}
+}
+
+// defaultFieldAnnotation returns the configured default annotation for an unannotated field.
+func defaultFieldAnnotation(c *ExtensionConfig, f *gen.Field) (DomainField, bool) {
+	if f.Type != nil {
+		if annotation, ok := c.DefaultFieldAnnotations[f.Type.Type]; ok {
+			return annotation, true
+		}
+	}
+	if c.DefaultFieldAnnotation != nil {
+		return *c.DefaultFieldAnnotation, true
+	}
+	return DomainField{}, false
+}
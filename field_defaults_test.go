@@ -0,0 +1,60 @@
+package entdomain
+
+import (
+	"testing"
+
+	"entgo.io/ent/entc/gen"
+	"entgo.io/ent/schema/field"
+)
+
+func TestApplyDefaultFieldAnnotations(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		node := newTestType("Tag", newStringField("label", nil))
+		NewExtension(nil).applyDefaultFieldAnnotations([]*gen.Type{node})
+
+		if got := domainFields(node); len(got) != 0 {
+			t.Errorf("domainFields() = %d fields, want 0", len(got))
+		}
+	})
+
+	t.Run("default and per-type override", func(t *testing.T) {
+		explicit := ptr(InputOnlyField())
+		node := newTestType("Post",
+			newStringField("title", nil),
+			newTimeField("published_at", nil),
+			newStringField("secret", explicit),
+		)
+		ext := NewExtensionWithOptions(
+			WithDefaultFieldAnnotation(DefaultField()),
+			WithDefaultFieldAnnotationFor(field.TypeTime, OutputOnlyField()),
+		)
+		ext.applyDefaultFieldAnnotations([]*gen.Type{node})
+
+		if got := len(domainFields(node)); got != 3 {
+			t.Fatalf("domainFields() = %d fields, want 3", got)
+		}
+		title, publishedAt, secret := node.Fields[0], node.Fields[1], node.Fields[2]
+		if !hasDomainScope(title, ScopeCreate) || !hasDomainScope(title, ScopeResponse) {
+			t.Error("title should get DefaultField scopes")
+		}
+		if hasDomainScope(publishedAt, ScopeCreate) || !hasDomainScope(publishedAt, ScopeResponse) {
+			t.Error("published_at should get the OutputOnlyField override for time fields")
+		}
+		if getDomainFieldAnnotation(secret) != explicit {
+			t.Error("explicit annotations must not be replaced")
+		}
+	})
+
+	t.Run("per-type override only", func(t *testing.T) {
+		node := newTestType("Post", newStringField("title", nil), newTimeField("published_at", nil))
+		ext := NewExtensionWithOptions(WithDefaultFieldAnnotationFor(field.TypeTime, OutputOnlyField()))
+		ext.applyDefaultFieldAnnotations([]*gen.Type{node})
+
+		if getDomainFieldAnnotation(node.Fields[0]) != nil {
+			t.Error("title has no default and should stay unannotated")
+		}
+		if getDomainFieldAnnotation(node.Fields[1]) == nil {
+			t.Error("published_at should get the time override")
+		}
+	})
+}
@@ -0,0 +1,259 @@
+package entdomain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// FilterOp is the comparison a Filter applies to its field.
+type FilterOp string
+
+// Filter operators. Generated services accept an operator on a field only when
+// ent generates the matching predicate for its type: ordering operators need
+// an ordered type, like and prefix a string, isnull an optional field.
+const (
+	OpEq     FilterOp = "eq"
+	OpNeq    FilterOp = "neq"
+	OpGt     FilterOp = "gt"
+	OpGte    FilterOp = "gte"
+	OpLt     FilterOp = "lt"
+	OpLte    FilterOp = "lte"
+	OpIn     FilterOp = "in"     // Value is a list
+	OpLike   FilterOp = "like"   // Value is an SQL LIKE pattern
+	OpPrefix FilterOp = "prefix" // Value is the prefix string
+	OpIsNull FilterOp = "isnull" // Value is a bool, nil meaning true
+)
+
+// filterOps lists the known operators.
+var filterOps = map[FilterOp]bool{
+	OpEq: true, OpNeq: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true,
+	OpIn: true, OpLike: true, OpPrefix: true, OpIsNull: true,
+}
+
+// Filter is one typed condition of a SearchRequest: Field compared to Value
+// with Op. Field is the column name of a filterable field. JSON-decoded
+// values are converted to the field's Go type by the generated service.
+type Filter struct {
+	Field string   `json:"field"`
+	Op    FilterOp `json:"op"`
+	Value any      `json:"value,omitempty"`
+}
+
+// Validate checks that the filter names a field and a known operator, and that
+// in filters carry a list. Like ListRequest.Validate, it returns plain errors.
+func (f Filter) Validate() error {
+	if f.Field == "" {
+		return fmt.Errorf("filter field is required")
+	}
+	if !filterOps[f.Op] {
+		return fmt.Errorf("unknown filter operator %q for %s", f.Op, f.Field)
+	}
+	if f.Op == OpIn {
+		if v := reflect.ValueOf(f.Value); v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return fmt.Errorf("%s filter of %s needs a list value", f.Op, f.Field)
+		}
+	}
+	return nil
+}
+
+// Null returns whether an isnull filter selects NULL (true) or non-NULL
+// (false) values.
+func (f Filter) Null() (bool, error) {
+	if f.Value == nil {
+		return true, nil
+	}
+	null, ok := f.Value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: %s filter of %s needs a bool value", ErrValidation, f.Op, f.Field)
+	}
+	return null, nil
+}
+
+// NullCheck is a SearchRequest.Filters value selecting NULL or non-NULL values
+// of an optional field instead of comparing it. A nil value, such as a JSON
+// null, checks for NULL as well.
+type NullCheck bool
+
+// Null checks, as Filters values.
+const (
+	Null    NullCheck = true
+	NotNull NullCheck = false
+)
+
+// IsNullCheck reports whether value, taken from SearchRequest.Filters, is a
+// null check, and if so whether it selects NULL.
+func IsNullCheck(value any) (null, ok bool) {
+	switch v := value.(type) {
+	case nil:
+		return true, true
+	case NullCheck:
+		return bool(v), true
+	}
+	return false, false
+}
+
+// FilterValue converts the value of f to T, the Go type of its field. Values
+// decoded from JSON arrive as strings, float64s and bools; they are converted
+// through a JSON round trip, so "2024-01-02T15:04:05Z" becomes a time.Time
+// and 18.0 an int.
+func FilterValue[T any](f Filter) (T, error) {
+	if v, ok := f.Value.(T); ok {
+		return v, nil
+	}
+	var out T
+	if err := convertFilterValue(f.Value, &out); err != nil {
+		return out, fmt.Errorf("%w: invalid %s filter value for %s: %v", ErrValidation, f.Op, f.Field, err)
+	}
+	return out, nil
+}
+
+// FilterValues converts the list value of an in filter to []T.
+func FilterValues[T any](f Filter) ([]T, error) {
+	if v, ok := f.Value.([]T); ok {
+		return v, nil
+	}
+	var out []T
+	if err := convertFilterValue(f.Value, &out); err != nil {
+		return nil, fmt.Errorf("%w: invalid %s filter value for %s: %v", ErrValidation, f.Op, f.Field, err)
+	}
+	return out, nil
+}
+
+func convertFilterValue(v, out any) error {
+	if v == nil {
+		return fmt.Errorf("value is required")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// maxFilterGroupDepth bounds the nesting of FilterGroups, which usually come
+// from request bodies.
+const maxFilterGroupDepth = 8
+
+// FilterGroup is a boolean tree of filters. A group matches when its Filter,
+// all And groups and at least one Or group match; parts left empty are
+// skipped, but a group needs at least one. Build groups with AllOf and AnyOf:
+//
+//	// status = active AND (type = a OR type = b)
+//	entdomain.AllOf(
+//		entdomain.FilterOn("status").Eq("active"),
+//		entdomain.AnyOf(entdomain.FilterOn("type").Eq("a"), entdomain.FilterOn("type").Eq("b")),
+//	)
+type FilterGroup struct {
+	Filter *Filter       `json:"filter,omitempty"`
+	And    []FilterGroup `json:"and,omitempty"`
+	Or     []FilterGroup `json:"or,omitempty"`
+}
+
+// Validate checks every filter of the tree, and rejects empty groups and
+// trees nested deeper than 8 levels. Like ListRequest.Validate, it returns
+// plain errors.
+func (g *FilterGroup) Validate() error { return g.validate(1) }
+
+func (g *FilterGroup) validate(depth int) error {
+	if depth > maxFilterGroupDepth {
+		return fmt.Errorf("filter groups cannot be nested more than %d deep", maxFilterGroupDepth)
+	}
+	if g.Filter == nil && len(g.And) == 0 && len(g.Or) == 0 {
+		return fmt.Errorf("filter group is empty")
+	}
+	if g.Filter != nil {
+		if err := g.Filter.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, sub := range [][]FilterGroup{g.And, g.Or} {
+		for i := range sub {
+			if err := sub[i].validate(depth + 1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FilterExpr is a Filter or a FilterGroup, as accepted by AllOf and AnyOf.
+type FilterExpr interface {
+	filterGroup() FilterGroup
+}
+
+func (f Filter) filterGroup() FilterGroup      { return FilterGroup{Filter: &f} }
+func (g FilterGroup) filterGroup() FilterGroup { return g }
+
+// AllOf returns the group matching when all exprs match.
+func AllOf(exprs ...FilterExpr) FilterGroup { return FilterGroup{And: filterGroups(exprs)} }
+
+// AnyOf returns the group matching when at least one of exprs matches.
+func AnyOf(exprs ...FilterExpr) FilterGroup { return FilterGroup{Or: filterGroups(exprs)} }
+
+func filterGroups(exprs []FilterExpr) []FilterGroup {
+	groups := make([]FilterGroup, len(exprs))
+	for i, e := range exprs {
+		groups[i] = e.filterGroup()
+	}
+	return groups
+}
+
+// LikePredicate returns the selector predicate column LIKE pattern. ent has
+// no per-field LIKE predicate, so generated services use it for OpLike.
+func LikePredicate(column, pattern string) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		s.Where(sql.Like(s.C(column), pattern))
+	}
+}
+
+// FilterField starts a Filter on a field; its methods complete it with an
+// operator and value:
+//
+//	req.Where = append(req.Where,
+//		entdomain.FilterOn("age").Gte(18),
+//		entdomain.FilterOn("status").In("active", "invited"),
+//	)
+type FilterField string
+
+// FilterOn returns the FilterField for the column name field.
+func FilterOn(field string) FilterField { return FilterField(field) }
+
+func (f FilterField) filter(op FilterOp, v any) Filter {
+	return Filter{Field: string(f), Op: op, Value: v}
+}
+
+// Eq returns the filter field = v.
+func (f FilterField) Eq(v any) Filter { return f.filter(OpEq, v) }
+
+// Neq returns the filter field <> v.
+func (f FilterField) Neq(v any) Filter { return f.filter(OpNeq, v) }
+
+// Gt returns the filter field > v.
+func (f FilterField) Gt(v any) Filter { return f.filter(OpGt, v) }
+
+// Gte returns the filter field >= v.
+func (f FilterField) Gte(v any) Filter { return f.filter(OpGte, v) }
+
+// Lt returns the filter field < v.
+func (f FilterField) Lt(v any) Filter { return f.filter(OpLt, v) }
+
+// Lte returns the filter field <= v.
+func (f FilterField) Lte(v any) Filter { return f.filter(OpLte, v) }
+
+// In returns the filter field IN (values...).
+func (f FilterField) In(values ...any) Filter { return f.filter(OpIn, values) }
+
+// Like returns the filter field LIKE pattern.
+func (f FilterField) Like(pattern string) Filter { return f.filter(OpLike, pattern) }
+
+// Prefix returns the filter selecting values of field starting with prefix.
+func (f FilterField) Prefix(prefix string) Filter { return f.filter(OpPrefix, prefix) }
+
+// IsNull returns the filter field IS NULL.
+func (f FilterField) IsNull() Filter { return f.filter(OpIsNull, true) }
+
+// NotNull returns the filter field IS NOT NULL.
+func (f FilterField) NotNull() Filter { return f.filter(OpIsNull, false) }
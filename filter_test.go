@@ -0,0 +1,160 @@
+package entdomain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+)
+
+func TestFilterValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr bool
+	}{
+		{"eq", FilterOn("name").Eq("ann"), false},
+		{"in", FilterOn("status").In("active", "invited"), false},
+		{"in typed slice", Filter{Field: "age", Op: OpIn, Value: []int{1, 2}}, false},
+		{"isnull", FilterOn("deleted_at").IsNull(), false},
+		{"missing field", Filter{Op: OpEq, Value: 1}, true},
+		{"unknown op", Filter{Field: "age", Op: "between"}, true},
+		{"empty op", Filter{Field: "age", Value: 1}, true},
+		{"in scalar", Filter{Field: "age", Op: OpIn, Value: 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filter.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilterOn(t *testing.T) {
+	if got := FilterOn("age").Gte(18); got != (Filter{Field: "age", Op: OpGte, Value: 18}) {
+		t.Errorf("Gte = %+v", got)
+	}
+	if got := FilterOn("name").Prefix("an"); got.Op != OpPrefix || got.Value != "an" {
+		t.Errorf("Prefix = %+v", got)
+	}
+	if null, err := FilterOn("x").NotNull().Null(); err != nil || null {
+		t.Errorf("NotNull().Null() = %v, %v", null, err)
+	}
+	if null, err := (Filter{Field: "x", Op: OpIsNull}).Null(); err != nil || !null {
+		t.Errorf("Null() without value = %v, %v", null, err)
+	}
+	for value, want := range map[any]bool{nil: true, Null: true, NotNull: false} {
+		if null, ok := IsNullCheck(value); !ok || null != want {
+			t.Errorf("IsNullCheck(%v) = %v, %v, want %v, true", value, null, ok, want)
+		}
+	}
+	if _, ok := IsNullCheck(false); ok {
+		t.Error("IsNullCheck(false) should not be a null check")
+	}
+	if _, err := FilterOn("x").Eq("yes").Null(); !errors.Is(err, ErrValidation) {
+		t.Errorf("Null() of a string err = %v, want ErrValidation", err)
+	}
+}
+
+func TestFilterValue(t *testing.T) {
+	var f Filter
+	if err := json.Unmarshal([]byte(`{"field":"age","op":"gt","value":18}`), &f); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := FilterValue[int](f); err != nil || v != 18 {
+		t.Errorf("FilterValue[int] = %v, %v", v, err)
+	}
+	if _, err := FilterValue[string](f); !errors.Is(err, ErrValidation) {
+		t.Errorf("FilterValue[string] of a number err = %v, want ErrValidation", err)
+	}
+	if _, err := FilterValue[int](FilterOn("age").Eq(1.5)); !errors.Is(err, ErrValidation) {
+		t.Errorf("FilterValue[int](1.5) err = %v, want ErrValidation", err)
+	}
+	if _, err := FilterValue[int](Filter{Field: "age", Op: OpEq}); !errors.Is(err, ErrValidation) {
+		t.Errorf("FilterValue without value err = %v, want ErrValidation", err)
+	}
+
+	ts := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	if v, err := FilterValue[time.Time](FilterOn("created_at").Lt("2024-01-02T15:04:05Z")); err != nil || !v.Equal(ts) {
+		t.Errorf("FilterValue[time.Time] = %v, %v", v, err)
+	}
+	u := uuid.New()
+	if v, err := FilterValue[uuid.UUID](FilterOn("id").Eq(u)); err != nil || v != u {
+		t.Errorf("FilterValue[uuid.UUID] = %v, %v", v, err)
+	}
+
+	ids, err := FilterValues[uuid.UUID](FilterOn("id").In(u.String(), u))
+	if err != nil || len(ids) != 2 || ids[0] != u || ids[1] != u {
+		t.Errorf("FilterValues[uuid.UUID] = %v, %v", ids, err)
+	}
+	if _, err := FilterValues[int](FilterOn("age").In("x")); !errors.Is(err, ErrValidation) {
+		t.Errorf("FilterValues[int] of strings err = %v, want ErrValidation", err)
+	}
+}
+
+func TestLikePredicate(t *testing.T) {
+	s := sql.Select("*").From(sql.Table("users"))
+	LikePredicate("name", "a%")(s)
+	query, args := s.Query()
+	if want := "SELECT * FROM `users` WHERE `users`.`name` LIKE ?"; query != want {
+		t.Errorf("query = %s, want %s", query, want)
+	}
+	if len(args) != 1 || args[0] != "a%" {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestFilterGroup(t *testing.T) {
+	g := AllOf(
+		FilterOn("status").Eq("active"),
+		AnyOf(FilterOn("type").Eq("a"), FilterOn("type").Eq("b")),
+	)
+	if len(g.And) != 2 || g.And[0].Filter == nil || g.And[0].Filter.Field != "status" {
+		t.Fatalf("AllOf = %+v", g)
+	}
+	if or := g.And[1].Or; len(or) != 2 || or[1].Filter.Value != "b" {
+		t.Fatalf("AnyOf = %+v", g.And[1])
+	}
+	if err := g.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"and":[{"filter":{"field":"status","op":"eq","value":"active"}},{"or":[{"filter":{"field":"type","op":"eq","value":"a"}},{"filter":{"field":"type","op":"eq","value":"b"}}]}]}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+}
+
+func TestFilterGroupValidate(t *testing.T) {
+	deep := AnyOf(FilterOn("a").Eq(1))
+	for range maxFilterGroupDepth {
+		deep = AllOf(deep)
+	}
+	tests := []struct {
+		name    string
+		group   FilterGroup
+		wantErr bool
+	}{
+		{"single filter", FilterGroup{Filter: &Filter{Field: "a", Op: OpEq, Value: 1}}, false},
+		{"filter and or", FilterGroup{Filter: &Filter{Field: "a", Op: OpEq, Value: 1}, Or: []FilterGroup{FilterOn("b").Eq(2).filterGroup()}}, false},
+		{"empty", FilterGroup{}, true},
+		{"empty member", AnyOf(FilterOn("a").Eq(1), FilterGroup{}), true},
+		{"invalid nested filter", AllOf(AnyOf(Filter{Field: "a", Op: "between"})), true},
+		{"too deep", deep, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.group.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
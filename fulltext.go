@@ -0,0 +1,55 @@
+package entdomain
+
+import (
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+)
+
+// TextSearchConfig is the PostgreSQL text search configuration FullTextPredicate
+// parses text and queries with, such as "english" to stem words. Set it before
+// serving requests.
+var TextSearchConfig = "simple"
+
+// FullTextPredicate returns the selector predicate matching query against the
+// text of columns. On PostgreSQL it is
+//
+//	to_tsvector(config, coalesce(c1, '') || ' ' || ...) @@ websearch_to_tsquery(config, query)
+//
+// which an expression index on the same to_tsvector call serves. Other
+// dialects fall back to LIKE: every word of query must appear in one of the
+// columns. Generated services use it for fields marked AsFullTextSearchable.
+func FullTextPredicate(query string, columns ...string) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		if s.Dialect() == dialect.Postgres {
+			config := "'" + strings.ReplaceAll(TextSearchConfig, "'", "''") + "'"
+			s.Where(sql.P(func(b *sql.Builder) {
+				b.WriteString("to_tsvector(" + config + ", ")
+				for i, c := range columns {
+					if i > 0 {
+						b.WriteString(" || ' ' || ")
+					}
+					b.WriteString("coalesce(").Ident(s.C(c)).WriteString(", '')")
+				}
+				b.WriteString(") @@ websearch_to_tsquery(" + config + ", ").Arg(query).WriteString(")")
+			}))
+			return
+		}
+
+		words := strings.Fields(query)
+		if len(words) == 0 {
+			s.Where(sql.False())
+			return
+		}
+		matches := make([]*sql.Predicate, len(words))
+		for i, w := range words {
+			contains := make([]*sql.Predicate, len(columns))
+			for j, c := range columns {
+				contains[j] = sql.Contains(s.C(c), w)
+			}
+			matches[i] = sql.Or(contains...)
+		}
+		s.Where(sql.And(matches...))
+	}
+}
@@ -0,0 +1,57 @@
+package entdomain
+
+import (
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+)
+
+func TestFullTextPredicate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		query   string
+		want    string
+		args    []any
+	}{
+		{
+			name:    "postgres",
+			dialect: dialect.Postgres,
+			query:   "quick fox",
+			want:    `SELECT * FROM "posts" WHERE to_tsvector('simple', coalesce("posts"."title", '') || ' ' || coalesce("posts"."body", '')) @@ websearch_to_tsquery('simple', $1)`,
+			args:    []any{"quick fox"},
+		},
+		{
+			name:    "sqlite",
+			dialect: dialect.SQLite,
+			query:   "quick fox",
+			want:    "SELECT * FROM `posts` WHERE (`posts`.`title` LIKE ? OR `posts`.`body` LIKE ?) AND (`posts`.`title` LIKE ? OR `posts`.`body` LIKE ?)",
+			args:    []any{"%quick%", "%quick%", "%fox%", "%fox%"},
+		},
+		{
+			name:    "blank query",
+			dialect: dialect.SQLite,
+			query:   "  ",
+			want:    "SELECT * FROM `posts` WHERE FALSE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sql.Dialect(tt.dialect).Select().From(sql.Table("posts"))
+			FullTextPredicate(tt.query, "title", "body")(s)
+			query, args := s.Query()
+			if query != tt.want {
+				t.Errorf("query = %s\nwant    %s", query, tt.want)
+			}
+			if len(args) != len(tt.args) {
+				t.Fatalf("args = %v, want %v", args, tt.args)
+			}
+			for i := range args {
+				if args[i] != tt.args[i] {
+					t.Errorf("args = %v, want %v", args, tt.args)
+				}
+			}
+		})
+	}
+}
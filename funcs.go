@@ -15,6 +15,7 @@ import (
 //   - funcs_scope.go:      scope and requirement checking
 //   - funcs_typechecks.go: field type checking
 //   - funcs_codegen.go:    code generation helpers
+//   - funcs_config.go:     entity-level DomainConfig lookups
 func templateFuncs() template.FuncMap {
 	return template.FuncMap{
 		// String manipulation
@@ -23,36 +24,96 @@ func templateFuncs() template.FuncMap {
 		"hasPrefix": hasPrefix,
 
 		// Field selection (used in template range loops)
-		"domainFields":      domainFields,
-		"createFields":      createFields,
-		"updateFields":      updateFields,
-		"responseFields":    responseFields,
+		"domainFields":       domainFields,
+		"createFields":       createFields,
+		"updateFields":       updateFields,
+		"responseFields":     responseFields,
+		"clearableFields":    clearableFields,
+		"patchDocFields":     patchDocFields,
 		"uniqueLookupFields": uniqueLookupFields,
+		"upsertFields":       upsertFields,
+		"uniqueIndexLookups": uniqueIndexLookups,
 		"rangeLookupFields":  rangeLookupFields,
 		"responseEdges":      responseEdges,
+		"mutationEdges":      mutationEdges,
+		"shardKeyField":      shardKeyField,
+		"auditedFields":      auditedFields,
+		"sortableFields":     sortableFields,
+		"filterableFields":   filterableFields,
+		"searchableFields":   searchableFields,
+		"fullTextFields":     fullTextFields,
+		"searchAliases":      searchAliases,
+		"defaultSortField":   defaultSortField,
+		"defaultSortOrder":   defaultSortOrder,
+		"queryFields":        queryFields,
 
 		// Scope and requirement checking
+		"hasDomainScope":   hasDomainScope,
 		"isDomainRequired": isDomainRequired,
+		"validateTag":      validateTag,
 
 		// Field type checking
 		"isUniqueField":      isUniqueField,
+		"isSensitive":        isSensitive,
 		"isUUIDType":         isUUIDType,
 		"hasTimeFields":      hasTimeFields,
 		"hasTimeField":       hasTimeField,
 		"isComplexFieldType": isComplexFieldType,
+		"isCaseInsensitive":  isCaseInsensitive,
 		"hasSoftDelete":      hasSoftDelete,
+		"hasExpiry":          hasExpiry,
+		"hasUpdatedAt":       hasUpdatedAt,
 
 		// Code generation helpers
-		"setFieldCallReq": setFieldCallReq,
-		"searchMethod":    searchMethod,
-		"findByMethod":    findByMethod,
-		"last":            last,
+		"setFieldCallReq":  setFieldCallReq,
+		"searchMethod":     searchMethod,
+		"filterPredicate":  filterPredicate,
+		"findByMethod":     findByMethod,
+		"whereMethods":     whereMethods,
+		"last":             last,
+		"benchSeedValue":   benchSeedValue,
+		"benchSeedFields":  benchSeedFields,
+		"queryParamKind":   queryParamKind,
+		"queryParamParser": queryParamParser,
+		"timeLayoutArgs":   timeLayoutArgs,
+		"importCellParser": importCellParser,
+		"fieldTitle":       fieldTitle,
+		"isDeprecated":     isDeprecated,
+		"idString":         idString,
+		"idExample":        idExample,
+		"idValue":          idValue,
+
+		// Composite primary keys (edge schemas keyed by their edge fields)
+		"compositeKeyParams":     compositeKeyParams,
+		"compositeKeyPredicates": compositeKeyPredicates,
+		"compositeKeyID":         compositeKeyID,
+
+		// Entity-level configuration
+		"resourceName":        resourceName,
+		"pluralName":          pluralName,
+		"resourcePath":        resourcePath,
+		"apiVersions":         apiVersions,
+		"versionedPath":       versionedPath,
+		"versionIdent":        versionIdent,
+		"deprecationNotice":   deprecationNotice,
+		"sunsetExpr":          sunsetExpr,
+		"idGeneratorExpr":     idGeneratorExpr,
+		"idPrefix":            idPrefix,
+		"optimisticLockField": optimisticLockField,
+		"etagField":           etagField,
+		"fieldMutationFields": fieldMutationFields,
+		"isCached":            isCached,
+		"ownerField":          ownerField,
+		"archivableField":     archivableField,
+		"hasEvents":           hasEvents,
 
 		// Utility functions
 		"contains": contains,
 
 		// Template code generation helpers
-		"generateIdOperation":    generateIdOperation,
+		"generateIdOperation":     generateIdOperation,
 		"generateSearchCondition": generateSearchCondition,
+		"containsPredicate":       containsPredicate,
+		"equalPredicate":          equalPredicate,
 	}
 }
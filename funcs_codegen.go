@@ -2,6 +2,7 @@ package entdomain
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"entgo.io/ent/entc/gen"
@@ -13,17 +14,40 @@ func getEntityPackageName(node *gen.Type) string {
 	return strings.ToLower(node.Name)
 }
 
-// generateSearchCondition generates search condition for searchable string fields
+// generateSearchCondition generates search condition for searchable string fields.
+// Full-text fields get none; they are matched together by one FullTextPredicate.
 func generateSearchCondition(field *gen.Field, node *gen.Type) string {
-	if field.Type.String() == "string" {
-		packageName := getEntityPackageName(node)
-		return fmt.Sprintf("		predicates = append(predicates, %s.%sContains(req.Query))", packageName, field.StructField())
+	if field.Type.String() == "string" && !isFullTextField(field) {
+		return fmt.Sprintf("		predicates = append(predicates, %s(req.Query))", containsPredicate(field, node))
 	}
 	return ""
 }
 
+// containsPredicate returns the ent substring predicate of a string field,
+// ContainsFold for case-insensitive fields, e.g. "user.NameContains".
+func containsPredicate(field *gen.Field, node *gen.Type) string {
+	contains := "Contains"
+	if isCaseInsensitive(field) {
+		contains = "ContainsFold"
+	}
+	return fmt.Sprintf("%s.%s%s", getEntityPackageName(node), field.StructField(), contains)
+}
+
+// equalPredicate returns the ent equality predicate of a field, EqualFold for
+// case-insensitive fields, e.g. "user.EmailEQ".
+func equalPredicate(field *gen.Field, node *gen.Type) string {
+	pred := gen.EQ.Name()
+	if isCaseInsensitive(field) {
+		pred = gen.EqualFold.Name()
+	}
+	return fmt.Sprintf("%s.%s%s", getEntityPackageName(node), field.StructField(), pred)
+}
+
 // generateIdOperation generates ID-related operations for the given type
 func generateIdOperation(node *gen.Type, operation string, idVar string) string {
+	if node.HasCompositeID() {
+		return generateCompositeKeyOperation(node, operation, idVar)
+	}
 	idType := node.ID.Type.String()
 	entityName := node.Name
 	packageName := getEntityPackageName(node)
@@ -40,7 +64,7 @@ func generateIdOperation(node *gen.Type, operation string, idVar string) string
 		return 0
 	}())`, entityName, idVar)
 		} else if isUUIDType(idType) {
-			return fmt.Sprintf(`uid, parseErr := uuid.Parse(%s.String())
+			return fmt.Sprintf(`uid, parseErr := entdomain.UUIDFromID(%s)
 	if parseErr != nil {
 		return nil, fmt.Errorf("invalid uuid: %%w", parseErr)
 	}
@@ -61,7 +85,7 @@ func generateIdOperation(node *gen.Type, operation string, idVar string) string
 		return 0
 	}()).Exec(ctx)`, entityName, idVar)
 		} else if isUUIDType(idType) {
-			return fmt.Sprintf(`uid, parseErr := uuid.Parse(%s.String())
+			return fmt.Sprintf(`uid, parseErr := entdomain.UUIDFromID(%s)
 	if parseErr != nil {
 		return fmt.Errorf("invalid uuid: %%w", parseErr)
 	}
@@ -71,7 +95,7 @@ func generateIdOperation(node *gen.Type, operation string, idVar string) string
 		}
 	case "softDelete":
 		if isUUIDType(idType) {
-			return fmt.Sprintf(`uid, parseErr := uuid.Parse(%s.String())
+			return fmt.Sprintf(`uid, parseErr := entdomain.UUIDFromID(%s)
 	if parseErr != nil {
 		return fmt.Errorf("invalid uuid: %%w", parseErr)
 	}
@@ -92,7 +116,7 @@ func generateIdOperation(node *gen.Type, operation string, idVar string) string
 			return fmt.Sprintf(`now := time.Now()
 	uids := make([]uuid.UUID, len(%s))
 	for i, id := range %s {
-		uid, parseErr := uuid.Parse(id.String())
+		uid, parseErr := entdomain.UUIDFromID(id)
 		if parseErr != nil {
 			return fmt.Errorf("invalid uuid at index %%d: %%w", i, parseErr)
 		}
@@ -113,7 +137,7 @@ func generateIdOperation(node *gen.Type, operation string, idVar string) string
 		return 0
 	}())).Count(ctx)`, entityName, packageName, idVar)
 		} else if isUUIDType(idType) {
-			return fmt.Sprintf(`uid, parseErr := uuid.Parse(%s.String())
+			return fmt.Sprintf(`uid, parseErr := entdomain.UUIDFromID(%s)
 	if parseErr != nil {
 		return false, fmt.Errorf("invalid uuid: %%w", parseErr)
 	}
@@ -139,7 +163,7 @@ func generateIdOperation(node *gen.Type, operation string, idVar string) string
 		} else if isUUIDType(idType) {
 			return fmt.Sprintf(`uuidIds := make([]uuid.UUID, len(ids))
 	for i, id := range ids {
-		uid, parseErr := uuid.Parse(id.String())
+		uid, parseErr := entdomain.UUIDFromID(id)
 		if parseErr != nil {
 			return fmt.Errorf("invalid uuid at index %%d: %%w", i, parseErr)
 		}
@@ -154,6 +178,143 @@ func generateIdOperation(node *gen.Type, operation string, idVar string) string
 	}
 }
 
+// generateCompositeKeyOperation is generateIdOperation for edge schemas whose
+// primary key spans several edge fields. idVar holds an entdomain.ID that is
+// split with entdomain.CompositeIDFromID; each part is converted to its column
+// type and the entity is matched with one predicate per key column.
+func generateCompositeKeyOperation(node *gen.Type, operation string, idVar string) string {
+	var zero string
+	switch operation {
+	case "get":
+		zero = "nil, "
+	case "exists":
+		zero = "false, "
+	case "delete":
+	default:
+		return fmt.Sprintf("// unsupported operation for composite key: %s", operation)
+	}
+
+	packageName := getEntityPackageName(node)
+	var b strings.Builder
+	fmt.Fprintf(&b, `key, keyErr := entdomain.CompositeIDFromID(%s, %d)
+	if keyErr != nil {
+		return %skeyErr
+	}`, idVar, len(node.EdgeSchema.ID), zero)
+
+	predicates := make([]string, len(node.EdgeSchema.ID))
+	for i, f := range node.EdgeSchema.ID {
+		part := camelCase(f.Name)
+		ft := f.Type.String()
+		switch {
+		case isUUIDType(ft):
+			fmt.Fprintf(&b, `
+	%s, keyErr := entdomain.UUIDFromID(key[%d])`, part, i)
+			predicates[i] = fmt.Sprintf("%s.%s(%s)", packageName, f.StructField(), part)
+		case ft == "string":
+			fmt.Fprintf(&b, `
+	%s := key[%d].String()`, part, i)
+			predicates[i] = fmt.Sprintf("%s.%s(%s)", packageName, f.StructField(), part)
+			continue
+		default:
+			fmt.Fprintf(&b, `
+	%s, keyErr := key[%d].Int64()`, part, i)
+			predicates[i] = fmt.Sprintf("%s.%s(%s(%s))", packageName, f.StructField(), ft, part)
+		}
+		fmt.Fprintf(&b, `
+	if keyErr != nil {
+		return %skeyErr
+	}`, zero)
+	}
+
+	where := strings.Join(predicates, ", ")
+	switch operation {
+	case "get":
+		fmt.Fprintf(&b, `
+	entity, err := r.client.%s.Query().Where(%s).Only(ctx)`, node.Name, where)
+	case "exists":
+		fmt.Fprintf(&b, `
+	count, err := r.client.%s.Query().Where(%s).Count(ctx)`, node.Name, where)
+	case "delete":
+		fmt.Fprintf(&b, `
+	_, err := r.client.%s.Delete().Where(%s).Exec(ctx)
+	return err`, node.Name, where)
+	}
+	return b.String()
+}
+
+// compositeKeyParams returns the parameter list of the key columns of a
+// composite-key edge schema, e.g. "userId int, groupId uuid.UUID".
+func compositeKeyParams(node *gen.Type) string {
+	params := make([]string, len(node.EdgeSchema.ID))
+	for i, f := range node.EdgeSchema.ID {
+		params[i] = camelCase(f.Name) + " " + f.Type.String()
+	}
+	return strings.Join(params, ", ")
+}
+
+// compositeKeyPredicates returns the predicates matching one row by the key
+// parameters of compositeKeyParams, e.g. "membership.UserID(userId), membership.GroupID(groupId)".
+func compositeKeyPredicates(node *gen.Type) string {
+	packageName := getEntityPackageName(node)
+	predicates := make([]string, len(node.EdgeSchema.ID))
+	for i, f := range node.EdgeSchema.ID {
+		predicates[i] = fmt.Sprintf("%s.%s(%s)", packageName, f.StructField(), camelCase(f.Name))
+	}
+	return strings.Join(predicates, ", ")
+}
+
+// compositeKeyID returns the entdomain.CompositeID expression built from the
+// key parameters of compositeKeyParams, used for authorization and errors.
+func compositeKeyID(node *gen.Type) string {
+	parts := make([]string, len(node.EdgeSchema.ID))
+	for i, f := range node.EdgeSchema.ID {
+		parts[i] = idValue(f, camelCase(f.Name))
+	}
+	return fmt.Sprintf("entdomain.NewCompositeID(%s)", strings.Join(parts, ", "))
+}
+
+// idString returns the Go expression formatting the ID value expr as a string:
+// expr.String() for UUIDs, expr itself for strings, Int64ID formatting for
+// integers (no reflection, and no allocation for small IDs), fmt.Sprint(expr)
+// otherwise.
+func idString(id *gen.Field, expr string) string {
+	switch ft := id.Type.String(); {
+	case isUUIDType(ft):
+		return expr + ".String()"
+	case ft == "string":
+		return expr
+	case id.Type.Numeric() && !id.Type.Type.Float():
+		return fmt.Sprintf("entdomain.NewInt64ID(int64(%s)).String()", expr)
+	}
+	return fmt.Sprintf("fmt.Sprint(%s)", expr)
+}
+
+// idValue returns the Go expression wrapping the key value expr in its
+// entdomain.ID type, e.g. "entdomain.NewIDFromUUID(e.ID)".
+func idValue(id *gen.Field, expr string) string {
+	switch ft := id.Type.String(); {
+	case isUUIDType(ft):
+		return fmt.Sprintf("entdomain.NewIDFromUUID(%s)", expr)
+	case ft == "string":
+		return fmt.Sprintf("entdomain.NewStringID(%s)", expr)
+	}
+	return fmt.Sprintf("entdomain.NewInt64ID(int64(%s))", expr)
+}
+
+// idExample returns a placeholder ID value for generated examples.
+func idExample(id *gen.Field) string {
+	ft := id.Type.String()
+	switch {
+	case isUUIDType(ft):
+		return "uuid.New()"
+	case ft == "string":
+		return `"01J00000000000000000000000"`
+	case ft == "int":
+		return "1"
+	}
+	return ft + "(1)"
+}
+
 // setFieldCallReq generates a setter method call for a CreateRequest field (e.g., "SetName(req.Name)").
 // For Nillable fields, uses SetNillable... to accept pointer types.
 func setFieldCallReq(field *gen.Field, _ ...interface{}) string {
@@ -166,23 +327,57 @@ func setFieldCallReq(field *gen.Field, _ ...interface{}) string {
 // fieldPredicate generates a type-assertion + Where predicate for a field.
 // indent controls the indentation level of the generated code block.
 // When skipEmpty is true, string checks include `&& v != ""`.
+// Optional fields first check for entdomain null checks (nil, Null, NotNull)
+// and emit IsNil/NotNil predicates for them. Values of other types, such as
+// the float64s of decoded JSON, are converted with entdomain.FilterValue as
+// Where filters are, and the code returns its entdomain.ErrValidation when
+// they do not convert.
 func fieldPredicate(field *gen.Field, node *gen.Type, indent string, skipEmpty bool) string {
+	eq := fieldEQPredicate(field, node, indent, skipEmpty)
+	if !slices.Contains(field.Ops(), gen.IsNil) || !strings.HasPrefix(eq, indent+"if ") {
+		return eq
+	}
+	pkg := getEntityPackageName(node)
+	name := field.StructField()
+	return fmt.Sprintf(`%[1]sif null, ok := entdomain.IsNullCheck(value); ok {
+%[1]s	if null {
+%[1]s		query = query.Where(%[2]s.%[3]sIsNil())
+%[1]s	} else {
+%[1]s		query = query.Where(%[2]s.%[3]sNotNil())
+%[1]s	}
+%[1]s} else `, indent, pkg, name) + strings.TrimPrefix(eq, indent)
+}
+
+// fieldEQPredicate generates the equality part of fieldPredicate.
+func fieldEQPredicate(field *gen.Field, node *gen.Type, indent string, skipEmpty bool) string {
 	pkg := getEntityPackageName(node)
 	name := field.StructField()
 	ft := field.Type.String()
 
+	// convert ends the type switch of the generated code: when no branch
+	// matched, value is converted to goType, the field type, or rejected.
+	// cond guards the conversion, e.g. to keep skipping empty strings.
+	convert := func(goType, cond, eq string) string {
+		return fmt.Sprintf(` else%[1]s {
+%[2]s	v, err := entdomain.FilterValue[%[3]s](entdomain.Filter{Field: %[4]s.%[5]s, Op: entdomain.OpEq, Value: value})
+%[2]s	if err != nil {
+%[2]s		return nil, err
+%[2]s	}
+%[2]s	query = query.Where(%[4]s.%[6]s%[7]s(v))
+%[2]s}`, cond, indent, goType, pkg, field.Constant(), name, eq)
+	}
 	where := func(cast, goType string) string {
 		return fmt.Sprintf(`%sif v, ok := value.(%s); ok {
 %s	query = query.Where(%s.%sEQ(v))
-%s}`, indent, cast, indent, pkg, name, indent)
+%s}`, indent, cast, indent, pkg, name, indent) + convert(goType, "", "EQ")
 	}
 
 	switch {
 	case field.IsEnum():
 		enumType := fmt.Sprintf("%s.%s", pkg, name)
-		extra := ""
+		extra, cond := "", ""
 		if skipEmpty {
-			extra = ` && v != ""`
+			extra, cond = ` && v != ""`, " if !ok"
 		}
 		// Try concrete enum type first (e.g., person.Gender), then fall back to string.
 		// Go type assertions don't match underlying types, so both branches are needed.
@@ -190,27 +385,31 @@ func fieldPredicate(field *gen.Field, node *gen.Type, indent string, skipEmpty b
 %s	query = query.Where(%s.%sEQ(v))
 %s} else if v, ok := value.(string); ok%s {
 %s	query = query.Where(%s.%sEQ(%s(v)))
-%s}`, indent, enumType, indent, pkg, name, indent, extra, indent, pkg, name, enumType, indent)
+%s}`, indent, enumType, indent, pkg, name, indent, extra, indent, pkg, name, enumType, indent) + convert(enumType, cond, "EQ")
 	case ft == "string":
-		extra := ""
+		extra, cond := "", ""
 		if skipEmpty {
-			extra = ` && v != ""`
+			extra, cond = ` && v != ""`, " if !ok"
+		}
+		eq := "EQ"
+		if isCaseInsensitive(field) {
+			eq = "EqualFold"
 		}
 		return fmt.Sprintf(`%sif v, ok := value.(string); ok%s {
-%s	query = query.Where(%s.%sEQ(v))
-%s}`, indent, extra, indent, pkg, name, indent)
+%s	query = query.Where(%s.%s%s(v))
+%s}`, indent, extra, indent, pkg, name, eq, indent) + convert(ft, cond, eq)
 	case ft == "int":
 		return fmt.Sprintf(`%sif v, ok := value.(int); ok {
 %s	query = query.Where(%s.%sEQ(v))
 %s} else if v, ok := value.(int64); ok {
 %s	query = query.Where(%s.%sEQ(int(v)))
-%s}`, indent, indent, pkg, name, indent, indent, pkg, name, indent)
+%s}`, indent, indent, pkg, name, indent, indent, pkg, name, indent) + convert(ft, "", "EQ")
 	case ft == "int32":
 		return fmt.Sprintf(`%sif v, ok := value.(int32); ok {
 %s	query = query.Where(%s.%sEQ(v))
 %s} else if v, ok := value.(int64); ok {
 %s	query = query.Where(%s.%sEQ(int32(v)))
-%s}`, indent, indent, pkg, name, indent, indent, pkg, name, indent)
+%s}`, indent, indent, pkg, name, indent, indent, pkg, name, indent) + convert(ft, "", "EQ")
 	case ft == "int64":
 		return where("int64", ft)
 	case ft == "bool":
@@ -236,11 +435,215 @@ func searchMethod(field *gen.Field, node *gen.Type) string {
 	return fieldPredicate(field, node, "\t\t\t", true)
 }
 
+// filterOperators maps the value-taking Filter operators to the ent
+// predicate each translates to, in generated case order.
+var filterOperators = []struct {
+	op   string
+	pred gen.Op
+}{
+	{"OpEq", gen.EQ},
+	{"OpNeq", gen.NEQ},
+	{"OpGt", gen.GT},
+	{"OpGte", gen.GTE},
+	{"OpLt", gen.LT},
+	{"OpLte", gen.LTE},
+	{"OpPrefix", gen.HasPrefix},
+}
+
+// filterPredicate generates the body of a `case "<field>":` in the generated
+// {entity}FilterPredicate helper: it translates each Filter operator ent has a
+// predicate for into that predicate, converting the value to the field type.
+// Unsupported operators fall through to the helper's final error.
+func filterPredicate(field *gen.Field, node *gen.Type) string {
+	pkg := getEntityPackageName(node)
+	name := field.StructField()
+	ft := field.Type.String()
+	ops := make(map[gen.Op]bool)
+	for _, op := range field.Ops() {
+		ops[op] = true
+	}
+
+	var b strings.Builder
+	if ops[gen.IsNil] {
+		fmt.Fprintf(&b, `		if f.Op == entdomain.OpIsNull {
+			null, err := f.Null()
+			if err != nil {
+				return nil, err
+			}
+			if null {
+				return %[1]s.%[2]sIsNil(), nil
+			}
+			return %[1]s.%[2]sNotNil(), nil
+		}
+`, pkg, name)
+	}
+	if ops[gen.In] {
+		fmt.Fprintf(&b, `		if f.Op == entdomain.OpIn {
+			values, err := entdomain.FilterValues[%s](f)
+			if err != nil {
+				return nil, err
+			}
+			return %s.%sIn(values...), nil
+		}
+`, ft, pkg, name)
+	}
+	var cases []string
+	for _, o := range filterOperators {
+		if ops[o.pred] {
+			pred := o.pred.Name()
+			if o.pred == gen.EQ && isCaseInsensitive(field) {
+				pred = gen.EqualFold.Name()
+			}
+			cases = append(cases, fmt.Sprintf("\t\tcase entdomain.%s:\n\t\t\treturn %s.%s%s(v), nil", o.op, pkg, name, pred))
+		}
+	}
+	if ft == "string" && ops[gen.Contains] {
+		cases = append(cases, fmt.Sprintf("\t\tcase entdomain.OpLike:\n\t\t\treturn predicate.%s(entdomain.LikePredicate(%s.%s, v)), nil", node.Name, pkg, field.Constant()))
+	}
+	if len(cases) > 0 {
+		// isnull and in take no scalar value; when they were not handled
+		// above, leave the switch for the unsupported-operator error.
+		var skip []string
+		if !ops[gen.IsNil] {
+			skip = append(skip, "f.Op == entdomain.OpIsNull")
+		}
+		if !ops[gen.In] {
+			skip = append(skip, "f.Op == entdomain.OpIn")
+		}
+		if len(skip) > 0 {
+			fmt.Fprintf(&b, "\t\tif %s {\n\t\t\tbreak\n\t\t}\n", strings.Join(skip, " || "))
+		}
+		fmt.Fprintf(&b, `		v, err := entdomain.FilterValue[%s](f)
+		if err != nil {
+			return nil, err
+		}
+		switch f.Op {
+%s
+		}
+`, ft, strings.Join(cases, "\n"))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// whereMethods generates the typed Where methods of the generated
+// {Entity}SearchBuilder for field: one per Filter operator its
+// {entity}FilterPredicate case accepts, named after the ent predicate.
+func whereMethods(field *gen.Field, node *gen.Type) string {
+	builder := node.Name + "SearchBuilder"
+	name := field.StructField()
+	ft := field.Type.String()
+	column := fmt.Sprintf("%s.%s", getEntityPackageName(node), field.Constant())
+	ops := make(map[gen.Op]bool)
+	for _, op := range field.Ops() {
+		ops[op] = true
+	}
+
+	var methods []string
+	add := func(suffix, doc, params, op, value string) {
+		methods = append(methods, fmt.Sprintf(`// Where%[1]s%[2]s keeps the %[3]ss whose %[4]s %[5]s.
+func (b *%[6]s) Where%[1]s%[2]s(%[7]s) *%[6]s {
+	b.req.Where = append(b.req.Where, entdomain.Filter{Field: %[8]s, Op: entdomain.%[9]s, Value: %[10]s})
+	return b
+}`, name, suffix, node.Name, field.Name, doc, builder, params, column, op, value))
+	}
+	for _, o := range filterOperators {
+		if !ops[o.pred] {
+			continue
+		}
+		suffix := o.pred.Name()
+		if o.pred == gen.EQ {
+			suffix = ""
+		}
+		doc := map[gen.Op]string{
+			gen.EQ: "equals v", gen.NEQ: "differs from v",
+			gen.GT: "is greater than v", gen.GTE: "is at least v",
+			gen.LT: "is less than v", gen.LTE: "is at most v",
+			gen.HasPrefix: "starts with v",
+		}[o.pred]
+		if o.pred == gen.EQ && isCaseInsensitive(field) {
+			doc = "equals v, ignoring case"
+		}
+		add(suffix, doc, "v "+ft, o.op, "v")
+		if o.pred == gen.EQ && ops[gen.In] {
+			add("In", "is one of vs", "vs ..."+ft, "OpIn", "vs")
+		}
+	}
+	if ft == "string" && ops[gen.Contains] {
+		add("Like", "matches the SQL LIKE pattern", "pattern string", "OpLike", "pattern")
+	}
+	if ops[gen.IsNil] {
+		add("IsNil", "is NULL", "", "OpIsNull", "true")
+		add("NotNil", "is not NULL", "", "OpIsNull", "false")
+	}
+	return strings.Join(methods, "\n\n")
+}
+
 // findByMethod generates a filter predicate for FindBy methods (standard indentation).
 func findByMethod(field *gen.Field, node *gen.Type) string {
 	return fieldPredicate(field, node, "\t\t", false)
 }
 
+// benchSeedValue returns a Go expression producing a value for field in
+// generated benchmark seeding, varying with the int variable idx so unique
+// constraints hold. It returns "" for types it cannot synthesize
+// (custom Go types, JSON, bytes, ...).
+func benchSeedValue(field *gen.Field, node *gen.Type, idx string) string {
+	if field.HasGoType() && !isUUIDType(field.Type.String()) {
+		return ""
+	}
+	ft := field.Type.String()
+	switch {
+	case field.IsEnum():
+		if len(field.Enums) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s.%s", getEntityPackageName(node), field.Enums[0].Name)
+	case ft == "string":
+		return fmt.Sprintf(`fmt.Sprintf("%s-%%d", %s)`, field.Name, idx)
+	case ft == "bool":
+		return fmt.Sprintf("%s%%2 == 0", idx)
+	case ft == "time.Time":
+		return "time.Now()"
+	case isUUIDType(ft):
+		return "uuid.New()"
+	case strings.HasPrefix(ft, "int") || strings.HasPrefix(ft, "uint") || strings.HasPrefix(ft, "float"):
+		return fmt.Sprintf("%s(%s)", ft, idx)
+	}
+	return ""
+}
+
+// benchSeedFields returns the fields generated benchmarks must set when
+// seeding rows: the required fields without a default.
+func benchSeedFields(node *gen.Type) []*gen.Field {
+	var fields []*gen.Field
+	for _, field := range node.Fields {
+		if !field.Optional && !field.Default {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// benchSeedable reports whether benchmark rows of node can be synthesized:
+// the ID is defaulted, auto-incremented or a string (seeded as "bench-N"),
+// every seed field has a benchSeedValue and no edge is required.
+func benchSeedable(node *gen.Type) bool {
+	if id := node.ID; id != nil && !id.Default && !id.Type.Numeric() && id.Type.String() != "string" {
+		return false
+	}
+	for _, field := range benchSeedFields(node) {
+		if field.IsEdgeField() || benchSeedValue(field, node, "i") == "" {
+			return false
+		}
+	}
+	for _, edge := range node.Edges {
+		if !edge.Optional {
+			return false
+		}
+	}
+	return true
+}
+
 // last checks if this is the last element in slice
 func last(slice []*gen.Field) *gen.Field {
 	if len(slice) == 0 {
@@ -249,3 +652,106 @@ func last(slice []*gen.Field) *gen.Field {
 	return slice[len(slice)-1]
 }
 
+// queryParamKind classifies how a query field is bound in generated
+// QueryParams: "eq" for a single equality filter, "range" for a from/to pair
+// on an ordered RangeLookup field, "time" and "time_range" for their time
+// counterparts, and "" for types that cannot be parsed from a query string.
+func queryParamKind(field *gen.Field, node *gen.Type) string {
+	annotation := getDomainFieldAnnotation(field)
+	isRange := annotation != nil && annotation.RangeLookup
+	if isTimeField(field) {
+		if isRange {
+			return "time_range"
+		}
+		return "time"
+	}
+	if queryParamParser(field, node) == "" {
+		return ""
+	}
+	switch field.Type.String() {
+	case "string", "int", "int32", "int64", "float64":
+		if isRange && !field.IsEnum() {
+			return "range"
+		}
+	}
+	return "eq"
+}
+
+// queryParamParser returns the Go expression of a func(string) (T, error)
+// parsing a query string value into the field's type, or "" when the type is
+// not supported. Time fields use timeLayoutArgs instead.
+func queryParamParser(field *gen.Field, node *gen.Type) string {
+	ft := field.Type.String()
+	if field.IsEnum() {
+		validator := fmt.Sprintf("%s.%sValidator", getEntityPackageName(node), field.StructField())
+		return fmt.Sprintf("func(s string) (%s, error) { v := %s(s); return v, %s(v) }", ft, ft, validator)
+	}
+	if isUUIDType(ft) {
+		return "uuid.Parse"
+	}
+	if field.HasGoType() {
+		return ""
+	}
+	switch ft {
+	case "string":
+		return "entdomain.ParseString"
+	case "int":
+		return "strconv.Atoi"
+	case "int32":
+		return "entdomain.ParseInt32"
+	case "int64":
+		return "entdomain.ParseInt64"
+	case "float64":
+		return "entdomain.ParseFloat64"
+	case "bool":
+		return "strconv.ParseBool"
+	}
+	return ""
+}
+
+// importCellParser returns the Go expression of a func(string) (T, error)
+// parsing a CSV cell of a generated importer into the field's type: the
+// queryParamParser of the field, entdomain.ParseTime with its timeLayoutArgs
+// for time fields, and entdomain.ParseJSON for the other types.
+func importCellParser(field *gen.Field, node *gen.Type) string {
+	if isTimeField(field) {
+		return fmt.Sprintf("func(s string) (time.Time, error) { return entdomain.ParseTime(s%s) }", timeLayoutArgs(field))
+	}
+	if parser := queryParamParser(field, node); parser != "" {
+		return parser
+	}
+	return fmt.Sprintf("entdomain.ParseJSON[%s]", field.Type)
+}
+
+// fieldTitle returns the title of the field from its FieldMetadata, or "".
+func fieldTitle(field *gen.Field) string {
+	annotation := getDomainFieldAnnotation(field)
+	if annotation == nil || annotation.Metadata == nil {
+		return ""
+	}
+	return annotation.Metadata.Title
+}
+
+// isDeprecated reports whether the field is marked with
+// DomainField.AsDeprecated.
+func isDeprecated(field *gen.Field) bool {
+	annotation := getDomainFieldAnnotation(field)
+	return annotation != nil && annotation.Metadata != nil && annotation.Metadata.Deprecated
+}
+
+// timeLayoutArgs returns the trailing layout arguments for the runtime time
+// parsers of a field: ", time.DateOnly" for the "date" format, ", time.RFC3339Nano"
+// for "date-time", and "" (entdomain.DefaultTimeLayouts) otherwise.
+func timeLayoutArgs(field *gen.Field) string {
+	annotation := getDomainFieldAnnotation(field)
+	if annotation == nil || annotation.Metadata == nil {
+		return ""
+	}
+	switch annotation.Metadata.Format {
+	case "date":
+		return ", time.DateOnly"
+	case "date-time":
+		return ", time.RFC3339Nano"
+	}
+	return ""
+}
@@ -63,6 +63,23 @@ func TestFieldPredicate_StringSkipEmpty(t *testing.T) {
 
 	got := fieldPredicate(f, node, "\t", true)
 	assertContains(t, got, `v != ""`)
+	// Empty strings stay skipped; only values of other types are converted.
+	assertContains(t, got, `} else if !ok {`)
+}
+
+func TestFieldPredicate_OptionalNullCheck(t *testing.T) {
+	f := newTimeField("deleted_at", nil)
+	f.Optional = true
+	node := newTestType("User")
+
+	got := fieldPredicate(f, node, "\t", false)
+	assertContains(t, got, `if null, ok := entdomain.IsNullCheck(value); ok {`)
+	assertContains(t, got, `query = query.Where(user.DeletedAtIsNil())`)
+	assertContains(t, got, `query = query.Where(user.DeletedAtNotNil())`)
+	assertContains(t, got, `} else if v, ok := value.(time.Time); ok {`)
+
+	required := fieldPredicate(newTimeField("created_at", nil), node, "\t", false)
+	assertNotContains(t, required, `IsNullCheck`)
 }
 
 func TestFieldPredicate_Int(t *testing.T) {
@@ -75,6 +92,9 @@ func TestFieldPredicate_Int(t *testing.T) {
 	// int fields also have int64 fallback
 	assertContains(t, got, `value.(int64)`)
 	assertContains(t, got, `int(v)`)
+	// Other values, such as JSON float64s, are converted or rejected.
+	assertContains(t, got, `v, err := entdomain.FilterValue[int](entdomain.Filter{Field: user.FieldAge, Op: entdomain.OpEq, Value: value})`)
+	assertContains(t, got, `return nil, err`)
 }
 
 func TestFieldPredicate_Int32(t *testing.T) {
@@ -151,6 +171,35 @@ func TestGenerateSearchCondition_StringField(t *testing.T) {
 	assertContains(t, got, "user.NameContains(req.Query)")
 }
 
+func TestGenerateSearchCondition_CaseInsensitive(t *testing.T) {
+	f := newStringField("name", ptr(NewDomainField().AsCaseInsensitive()))
+	node := newTestType("User")
+
+	assertContains(t, generateSearchCondition(f, node), "user.NameContainsFold(req.Query)")
+	assertContains(t, fieldPredicate(f, node, "\t", true), "user.NameEqualFold(v)")
+	assertContains(t, filterPredicate(f, node), "return user.NameEqualFold(v), nil")
+}
+
+func TestEqualPredicate(t *testing.T) {
+	node := newTestType("User")
+	if got := equalPredicate(newStringField("email", nil), node); got != "user.EmailEQ" {
+		t.Errorf("equalPredicate() = %q", got)
+	}
+	folded := newStringField("email", ptr(NewDomainField().AsCaseInsensitive()))
+	if got := equalPredicate(folded, node); got != "user.EmailEqualFold" {
+		t.Errorf("equalPredicate(case-insensitive) = %q", got)
+	}
+}
+
+func TestGenerateSearchCondition_FullText(t *testing.T) {
+	f := newStringField("bio", ptr(NewDomainField().AsFullTextSearchable()))
+	node := newTestType("User")
+
+	if got := generateSearchCondition(f, node); got != "" {
+		t.Errorf("expected no condition for full-text field, got %q", got)
+	}
+}
+
 func TestGenerateSearchCondition_NonString(t *testing.T) {
 	f := newIntField("age", nil)
 	node := newTestType("User")
@@ -180,7 +229,7 @@ func TestGenerateIdOperation_Get(t *testing.T) {
 		},
 		{
 			"uuid id", field.TypeUUID, "uuid.UUID",
-			[]string{"uuid.Parse(id.String())", "User.Get(ctx, uid)"},
+			[]string{"entdomain.UUIDFromID(id)", "User.Get(ctx, uid)"},
 		},
 		{
 			"default id", field.TypeInt, "int",
@@ -223,7 +272,7 @@ func TestGenerateIdOperation_Delete(t *testing.T) {
 		},
 		{
 			"uuid id", field.TypeUUID, "uuid.UUID",
-			[]string{"uuid.Parse(id.String())", "DeleteOneID(uid).Exec(ctx)"},
+			[]string{"entdomain.UUIDFromID(id)", "DeleteOneID(uid).Exec(ctx)"},
 		},
 		{
 			"default id", field.TypeInt, "int",
@@ -266,7 +315,7 @@ func TestGenerateIdOperation_Exists(t *testing.T) {
 		},
 		{
 			"uuid id", field.TypeUUID, "uuid.UUID",
-			[]string{"uuid.Parse(id.String())", "IDEQ(uid)"},
+			[]string{"entdomain.UUIDFromID(id)", "IDEQ(uid)"},
 		},
 		{
 			"default id", field.TypeInt, "int",
@@ -309,7 +358,7 @@ func TestGenerateIdOperation_BatchDelete(t *testing.T) {
 		},
 		{
 			"uuid id", field.TypeUUID, "uuid.UUID",
-			[]string{"uuidIds", "uuid.Parse(id.String())", "IDIn(uuidIds...)"},
+			[]string{"uuidIds", "entdomain.UUIDFromID(id)", "IDIn(uuidIds...)"},
 		},
 		{
 			"default id", field.TypeInt, "int",
@@ -347,6 +396,90 @@ func TestGenerateIdOperation_Unknown(t *testing.T) {
 	assertContains(t, got, "Unknown operation: unknown_op")
 }
 
+func TestGenerateIdOperation_CompositeKey(t *testing.T) {
+	node := newCompositeTestType("Membership",
+		newIntField("user_id", nil), newUUIDField("group_id", nil), newStringField("role", nil))
+
+	tests := []struct {
+		operation string
+		fragments []string
+	}{
+		{
+			"get",
+			[]string{
+				"entdomain.CompositeIDFromID(id, 3)",
+				"userId, keyErr := key[0].Int64()",
+				"groupId, keyErr := entdomain.UUIDFromID(key[1])",
+				"role := key[2].String()",
+				"return nil, keyErr",
+				"Membership.Query().Where(membership.UserID(int(userId)), membership.GroupID(groupId), membership.Role(role)).Only(ctx)",
+			},
+		},
+		{
+			"delete",
+			[]string{"return keyErr", "Membership.Delete().Where(", "return err"},
+		},
+		{
+			"exists",
+			[]string{"return false, keyErr", "Membership.Query().Where(", ").Count(ctx)"},
+		},
+		{
+			"batchDelete",
+			[]string{"unsupported operation for composite key: batchDelete"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			got := generateIdOperation(node, tt.operation, "id")
+			for _, frag := range tt.fragments {
+				assertContains(t, got, frag)
+			}
+		})
+	}
+}
+
+func TestCompositeKeyHelpers(t *testing.T) {
+	node := newCompositeTestType("Membership",
+		newIntField("user_id", nil), newUUIDField("group_id", nil), newStringField("role", nil))
+
+	if got, want := compositeKeyParams(node), "userId int, groupId uuid.UUID, role string"; got != want {
+		t.Errorf("compositeKeyParams() = %q, want %q", got, want)
+	}
+	if got, want := compositeKeyPredicates(node), "membership.UserID(userId), membership.GroupID(groupId), membership.Role(role)"; got != want {
+		t.Errorf("compositeKeyPredicates() = %q, want %q", got, want)
+	}
+	want := "entdomain.NewCompositeID(entdomain.NewInt64ID(int64(userId)), entdomain.NewIDFromUUID(groupId), entdomain.NewStringID(role))"
+	if got := compositeKeyID(node); got != want {
+		t.Errorf("compositeKeyID() = %q, want %q", got, want)
+	}
+}
+
+func TestIDString(t *testing.T) {
+	tests := []struct {
+		id            *gen.Field
+		example, want string
+		value         string
+	}{
+		{newUUIDField("id", nil), "uuid.New()", "e.ID.String()", "entdomain.NewIDFromUUID(e.ID)"},
+		{newStringField("id", nil), `"01J00000000000000000000000"`, "e.ID", "entdomain.NewStringID(e.ID)"},
+		{newIntField("id", nil), "1", "entdomain.NewInt64ID(int64(e.ID)).String()", "entdomain.NewInt64ID(int64(e.ID))"},
+		{newInt64Field("id", nil), "int64(1)", "entdomain.NewInt64ID(int64(e.ID)).String()", "entdomain.NewInt64ID(int64(e.ID))"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id.Type.String(), func(t *testing.T) {
+			if got := idString(tt.id, "e.ID"); got != tt.want {
+				t.Errorf("idString() = %q, want %q", got, tt.want)
+			}
+			if got := idExample(tt.id); got != tt.example {
+				t.Errorf("idExample() = %q, want %q", got, tt.example)
+			}
+			if got := idValue(tt.id, "e.ID"); got != tt.value {
+				t.Errorf("idValue() = %q, want %q", got, tt.value)
+			}
+		})
+	}
+}
+
 func TestCamelCase(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -374,6 +507,30 @@ func TestCamelCase(t *testing.T) {
 	}
 }
 
+func TestSnakeCase(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"User", "user"},
+		{"UserProfile", "user_profile"},
+		{"HTTPRequest", "http_request"},
+		{"UserID", "user_id"},
+		{"OAuth2Token", "o_auth2_token"},
+		{"already_snake", "already_snake"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := snakeCase(tt.input)
+			if got != tt.expect {
+				t.Errorf("snakeCase(%q) = %q, want %q", tt.input, got, tt.expect)
+			}
+		})
+	}
+}
+
 func TestSearchMethod(t *testing.T) {
 	f := newStringField("name", nil)
 	node := newTestType("User")
@@ -393,3 +550,246 @@ func TestFindByMethod(t *testing.T) {
 	assertContains(t, got, `user.NameEQ(v)`)
 	assertNotContains(t, got, `v != ""`)
 }
+
+func TestFilterPredicate(t *testing.T) {
+	node := newTestType("User")
+
+	name := filterPredicate(newStringField("name", nil), node)
+	assertContains(t, name, `entdomain.FilterValue[string](f)`)
+	assertContains(t, name, `user.NameIn(values...)`)
+	assertContains(t, name, `return user.NameGTE(v), nil`)
+	assertContains(t, name, `return user.NameHasPrefix(v), nil`)
+	assertContains(t, name, `predicate.User(entdomain.LikePredicate(user.FieldName, v))`)
+	assertContains(t, name, `if f.Op == entdomain.OpIsNull {
+			break`)
+	assertNotContains(t, name, `IsNil()`)
+
+	age := newIntField("age", nil)
+	age.Optional = true
+	got := filterPredicate(age, node)
+	assertContains(t, got, `return user.AgeIsNil(), nil`)
+	assertContains(t, got, `return user.AgeNotNil(), nil`)
+	assertContains(t, got, `return user.AgeLT(v), nil`)
+	assertNotContains(t, got, `OpPrefix`)
+	assertNotContains(t, got, `OpLike`)
+	assertNotContains(t, got, `break`)
+
+	active := filterPredicate(newBoolField("active", nil), node)
+	assertContains(t, active, `return user.ActiveNEQ(v), nil`)
+	assertContains(t, active, `if f.Op == entdomain.OpIsNull || f.Op == entdomain.OpIn {`)
+	assertNotContains(t, active, `ActiveGT`)
+}
+
+func TestWhereMethods(t *testing.T) {
+	node := newTestType("User")
+
+	name := whereMethods(newStringField("name", nil), node)
+	assertContains(t, name, `func (b *UserSearchBuilder) WhereName(v string) *UserSearchBuilder {`)
+	assertContains(t, name, `entdomain.Filter{Field: user.FieldName, Op: entdomain.OpEq, Value: v}`)
+	assertContains(t, name, `WhereNameIn(vs ...string)`)
+	assertContains(t, name, `WhereNameHasPrefix(v string)`)
+	assertContains(t, name, `WhereNameLike(pattern string)`)
+	assertNotContains(t, name, `WhereNameIsNil`)
+
+	age := newIntField("age", nil)
+	age.Optional = true
+	got := whereMethods(age, node)
+	assertContains(t, got, `WhereAgeGTE(v int)`)
+	assertContains(t, got, `WhereAgeIsNil() *UserSearchBuilder`)
+	assertContains(t, got, `Op: entdomain.OpIsNull, Value: false}`)
+	assertNotContains(t, got, `HasPrefix`)
+
+	active := whereMethods(newBoolField("active", nil), node)
+	assertContains(t, active, `WhereActiveNEQ(v bool)`)
+	assertNotContains(t, active, `WhereActiveGT`)
+}
+
+func TestBenchSeedValue(t *testing.T) {
+	node := newTestType("User")
+	status := newEnumField("status", nil)
+	status.Enums = []gen.Enum{{Name: "StatusActive", Value: "active"}}
+
+	tests := []struct {
+		name  string
+		field *gen.Field
+		want  string
+	}{
+		{"string", newStringField("email", nil), `fmt.Sprintf("email-%d", i)`},
+		{"int", newIntField("age", nil), "int(i)"},
+		{"int64", newInt64Field("score", nil), "int64(i)"},
+		{"bool", newBoolField("active", nil), "i%2 == 0"},
+		{"time", newTimeField("born_at", nil), "time.Now()"},
+		{"uuid", newUUIDField("ref", nil), "uuid.New()"},
+		{"enum", status, "user.StatusActive"},
+		{"enum without values", newEnumField("kind", nil), ""},
+		{"json", newField("data", &field.TypeInfo{Type: field.TypeJSON, Ident: "json.RawMessage"}, nil), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := benchSeedValue(tt.field, node, "i"); got != tt.want {
+				t.Errorf("benchSeedValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBenchSeedFields(t *testing.T) {
+	name := newStringField("name", nil)
+	nickname := newStringField("nickname", nil)
+	nickname.Optional = true
+	status := newEnumField("status", nil)
+	status.Default = true
+
+	got := benchSeedFields(newTestType("User", name, nickname, status))
+	if len(got) != 1 || got[0] != name {
+		t.Errorf("benchSeedFields() = %v, want only the required field without default", got)
+	}
+}
+
+func TestBenchSeedable(t *testing.T) {
+	if !benchSeedable(newTestType("User", newStringField("name", nil))) {
+		t.Error("expected a type with only string fields to be seedable")
+	}
+
+	data := newField("data", &field.TypeInfo{Type: field.TypeJSON, Ident: "json.RawMessage"}, nil)
+	if benchSeedable(newTestType("Item", data)) {
+		t.Error("expected a required JSON field to make the type unseedable")
+	}
+
+	optionalData := newField("data", &field.TypeInfo{Type: field.TypeJSON, Ident: "json.RawMessage"}, nil)
+	optionalData.Optional = true
+	if !benchSeedable(newTestType("Item", optionalData)) {
+		t.Error("expected optional fields to be ignored")
+	}
+
+	stringID := newTestType("Note", newStringField("body", nil))
+	stringID.ID = newStringField("id", nil)
+	if !benchSeedable(stringID) {
+		t.Error("expected a string ID without default to be seeded")
+	}
+	if benchSeedable(newUUIDTestType("Item", newStringField("name", nil))) {
+		t.Error("expected a UUID ID without default to make the type unseedable")
+	}
+
+	withEdge := newTestType("Post", newStringField("title", nil))
+	withEdge.Edges = []*gen.Edge{{Name: "author", Optional: false}}
+	if benchSeedable(withEdge) {
+		t.Error("expected a required edge to make the type unseedable")
+	}
+}
+
+func TestQueryParamKind(t *testing.T) {
+	node := newTestType("User")
+	status := newField("status", &field.TypeInfo{Type: field.TypeEnum, Ident: "user.Status"}, ptr(DefaultField().AsRangeLookup()))
+
+	tests := []struct {
+		name  string
+		field *gen.Field
+		want  string
+	}{
+		{"string", newStringField("name", ptr(DefaultField())), "eq"},
+		{"int range", newIntField("age", ptr(DefaultField().AsRangeLookup())), "range"},
+		{"bool range is eq", newBoolField("active", ptr(DefaultField().AsRangeLookup())), "eq"},
+		{"enum range is eq", status, "eq"},
+		{"time", newTimeField("updated_at", ptr(DefaultField())), "time"},
+		{"time range", newTimeField("created_at", ptr(DefaultField().AsRangeLookup())), "time_range"},
+		{"json", newField("data", &field.TypeInfo{Type: field.TypeJSON, Ident: "json.RawMessage"}, ptr(DefaultField())), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryParamKind(tt.field, node); got != tt.want {
+				t.Errorf("queryParamKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryParamParser(t *testing.T) {
+	node := newTestType("Order")
+	tests := []struct {
+		name  string
+		field *gen.Field
+		want  string
+	}{
+		{"string", newStringField("name", nil), "entdomain.ParseString"},
+		{"int", newIntField("qty", nil), "strconv.Atoi"},
+		{"int32", newInt32Field("rank", nil), "entdomain.ParseInt32"},
+		{"int64", newInt64Field("total", nil), "entdomain.ParseInt64"},
+		{"bool", newBoolField("paid", nil), "strconv.ParseBool"},
+		{"uuid", newUUIDField("ref", nil), "uuid.Parse"},
+		{"time", newTimeField("paid_at", nil), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryParamParser(tt.field, node); got != tt.want {
+				t.Errorf("queryParamParser() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("enum validates", func(t *testing.T) {
+		status := newField("status", &field.TypeInfo{Type: field.TypeEnum, Ident: "order.Status"}, nil)
+		got := queryParamParser(status, node)
+		assertContains(t, got, "func(s string) (order.Status, error)")
+		assertContains(t, got, "order.StatusValidator(v)")
+	})
+}
+
+func TestTimeLayoutArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation *DomainField
+		want       string
+	}{
+		{"no annotation", nil, ""},
+		{"no format", ptr(DefaultField()), ""},
+		{"date", ptr(DefaultField().WithFormat("date")), ", time.DateOnly"},
+		{"date-time", ptr(DefaultField().WithFormat("date-time")), ", time.RFC3339Nano"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := timeLayoutArgs(newTimeField("at", tt.annotation)); got != tt.want {
+				t.Errorf("timeLayoutArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportCellParser(t *testing.T) {
+	node := newTestType("Order")
+	tests := []struct {
+		name  string
+		field *gen.Field
+		want  string
+	}{
+		{"int", newIntField("qty", nil), "strconv.Atoi"},
+		{"time", newTimeField("paid_at", nil), "func(s string) (time.Time, error) { return entdomain.ParseTime(s) }"},
+		{"date", newTimeField("due_on", ptr(DefaultField().WithFormat("date"))), "func(s string) (time.Time, error) { return entdomain.ParseTime(s, time.DateOnly) }"},
+		{"json", newField("tags", &field.TypeInfo{Type: field.TypeJSON, Ident: "[]string"}, nil), "entdomain.ParseJSON[[]string]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := importCellParser(tt.field, node); got != tt.want {
+				t.Errorf("importCellParser() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDeprecated(t *testing.T) {
+	if isDeprecated(newStringField("name", nil)) {
+		t.Error("isDeprecated() without metadata = true")
+	}
+	if !isDeprecated(newStringField("name", ptr(DefaultField().AsDeprecated()))) {
+		t.Error("isDeprecated() of an AsDeprecated field = false")
+	}
+}
+
+func TestFieldTitle(t *testing.T) {
+	if got := fieldTitle(newStringField("name", nil)); got != "" {
+		t.Errorf("fieldTitle() without metadata = %q", got)
+	}
+	if got := fieldTitle(newStringField("name", ptr(DefaultField().WithTitle("Full name")))); got != "Full name" {
+		t.Errorf("fieldTitle() = %q, want Full name", got)
+	}
+}
@@ -0,0 +1,307 @@
+package entdomain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"entgo.io/ent/entc/gen"
+	"entgo.io/ent/schema/field"
+	"github.com/go-openapi/inflect"
+)
+
+// getDomainConfigAnnotation extracts the entity-level DomainConfig annotation
+// from a gen.Type. Like getDomainFieldAnnotation, it accepts both the typed
+// value and the map[string]interface{} form produced by schema serialization.
+func getDomainConfigAnnotation(node *gen.Type) *DomainConfig {
+	if node == nil {
+		return nil
+	}
+	annotation, ok := node.Annotations[DomainConfig{}.Name()]
+	if !ok {
+		return nil
+	}
+
+	switch c := annotation.(type) {
+	case *DomainConfig:
+		return c
+	case DomainConfig:
+		return &c
+	case map[string]interface{}:
+		data, err := json.Marshal(c)
+		if err != nil {
+			return nil
+		}
+		var cfg DomainConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil
+		}
+		return &cfg
+	}
+
+	return nil
+}
+
+// resourceName returns the snake_case resource name used in permissions and
+// other string identifiers. DomainConfig.EntityName takes precedence over the
+// schema name.
+func resourceName(node *gen.Type) string {
+	if cfg := getDomainConfigAnnotation(node); cfg != nil && cfg.EntityName != "" {
+		return cfg.EntityName
+	}
+	return snakeCase(node.Name)
+}
+
+// pluralName returns the snake_case plural of the resource name.
+// DomainConfig.Plural takes precedence over automatic pluralization,
+// which gets irregular nouns wrong often enough to need an escape hatch.
+func pluralName(node *gen.Type) string {
+	if cfg := getDomainConfigAnnotation(node); cfg != nil && cfg.Plural != "" {
+		return cfg.Plural
+	}
+	return inflect.Pluralize(resourceName(node))
+}
+
+// resourcePath returns the collection route of the entity, e.g. "/user-profiles".
+// DomainConfig.URLPath takes precedence over the kebab-cased plural.
+func resourcePath(node *gen.Type) string {
+	segment := strings.ReplaceAll(pluralName(node), "_", "-")
+	if cfg := getDomainConfigAnnotation(node); cfg != nil && cfg.URLPath != "" {
+		segment = cfg.URLPath
+	}
+	return "/" + strings.Trim(segment, "/")
+}
+
+// apiVersions returns the API versions the entity is served under, current
+// version first. DomainConfig.APIVersions takes precedence over the
+// extension-wide fallback; nil means routes are unversioned.
+func apiVersions(node *gen.Type, fallback string) []string {
+	if cfg := getDomainConfigAnnotation(node); cfg != nil && len(cfg.APIVersions) > 0 {
+		return cfg.APIVersions
+	}
+	if fallback != "" {
+		return []string{fallback}
+	}
+	return nil
+}
+
+// versionedPath prefixes path with the API version segment: ("v1", "/users") → "/v1/users".
+// An empty version leaves path unchanged.
+func versionedPath(version, path string) string {
+	version = strings.Trim(version, "/")
+	if version == "" {
+		return path
+	}
+	return "/" + version + path
+}
+
+// versionIdent turns an API version into an identifier fragment for generated
+// constant names: "v2" → "V2", "v1-beta" → "V1beta".
+func versionIdent(version string) string {
+	var b strings.Builder
+	for _, r := range version {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		if b.Len() == 0 {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// deprecationNotice returns the DomainConfig deprecation notice, or "" when the
+// entity's API is not deprecated.
+func deprecationNotice(node *gen.Type) string {
+	if cfg := getDomainConfigAnnotation(node); cfg != nil {
+		return cfg.Deprecation
+	}
+	return ""
+}
+
+// sunsetExpr returns the Go expression for the DomainConfig sunset date,
+// e.g. "time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC)", or "time.Time{}"
+// when none is set. A malformed date fails generation.
+func sunsetExpr(node *gen.Type) (string, error) {
+	cfg := getDomainConfigAnnotation(node)
+	if cfg == nil || cfg.Sunset == "" {
+		return "time.Time{}", nil
+	}
+	t, err := time.Parse(time.DateOnly, cfg.Sunset)
+	if err != nil {
+		return "", fmt.Errorf("%s: invalid sunset date %q: %w", node.Name, cfg.Sunset, err)
+	}
+	return fmt.Sprintf("time.Date(%d, time.%s, %d, 0, 0, 0, 0, time.UTC)", t.Year(), t.Month(), t.Day()), nil
+}
+
+// idGeneratorExpr returns the Go expression of the IDGenerator named by
+// DomainConfig.IDGenerator, e.g. "entdomain.ULIDGenerator{}", or "" when none
+// is set. Unknown names and generators that do not fit the ID field type fail
+// generation.
+func idGeneratorExpr(node *gen.Type) (string, error) {
+	cfg := getDomainConfigAnnotation(node)
+	if cfg == nil || cfg.IDGenerator == "" {
+		return "", nil
+	}
+	switch IDGeneratorName(cfg.IDGenerator) {
+	case ULID:
+		if node.ID == nil || node.ID.Type.String() != "string" {
+			return "", fmt.Errorf("%s: id generator %q requires a string ID field", node.Name, cfg.IDGenerator)
+		}
+		return "entdomain.ULIDGenerator{}", nil
+	case Snowflake:
+		if node.ID == nil {
+			return "", fmt.Errorf("%s: id generator %q requires an int, int64 or uint64 ID field", node.Name, cfg.IDGenerator)
+		}
+		switch node.ID.Type.Type {
+		case field.TypeInt, field.TypeInt64, field.TypeUint64:
+			return "entdomain.DefaultSnowflake", nil
+		}
+		return "", fmt.Errorf("%s: id generator %q requires an int, int64 or uint64 ID field", node.Name, cfg.IDGenerator)
+	}
+	return "", fmt.Errorf("%s: unknown id generator %q", node.Name, cfg.IDGenerator)
+}
+
+// idPrefix returns the DomainConfig ID prefix ("usr"), or "" when IDs are
+// exposed unprefixed. Prefixes must be lowercase alphanumerics starting with a
+// letter, and need a single-field ID.
+func idPrefix(node *gen.Type) (string, error) {
+	cfg := getDomainConfigAnnotation(node)
+	if cfg == nil || cfg.IDPrefix == "" {
+		return "", nil
+	}
+	if !node.HasOneFieldID() {
+		return "", fmt.Errorf("%s: id prefix %q requires a single-field ID", node.Name, cfg.IDPrefix)
+	}
+	if !validIDPrefix(cfg.IDPrefix) {
+		return "", fmt.Errorf("%s: invalid id prefix %q: use lowercase letters and digits, starting with a letter", node.Name, cfg.IDPrefix)
+	}
+	return cfg.IDPrefix, nil
+}
+
+// optimisticLockField returns the field named by DomainConfig.OptimisticLock,
+// or nil when updates are not version-checked. The field must be a required,
+// mutable integer field.
+func optimisticLockField(node *gen.Type) (*gen.Field, error) {
+	cfg := getDomainConfigAnnotation(node)
+	if cfg == nil || cfg.OptimisticLock == "" {
+		return nil, nil
+	}
+	for _, f := range node.Fields {
+		if f.Name != cfg.OptimisticLock {
+			continue
+		}
+		if !f.Type.Type.Integer() || f.Optional || f.Immutable {
+			return nil, fmt.Errorf("%s: optimistic lock field %q must be a required, mutable integer field", node.Name, f.Name)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("%s: unknown optimistic lock field %q", node.Name, cfg.OptimisticLock)
+}
+
+// etagField returns the response field whose value versions an entity for
+// the ETag of its response: the optimistic lock field, or else a mutable
+// updated_at time field. It returns nil when neither is a response field.
+func etagField(node *gen.Type) *gen.Field {
+	lock, _ := optimisticLockField(node)
+	var updatedAt *gen.Field
+	for _, f := range responseFields(node) {
+		switch {
+		case lock != nil && f.Name == lock.Name:
+			return f
+		case f.Name == "updated_at" && isTimeField(f) && !f.Immutable:
+			updatedAt = f
+		}
+	}
+	return updatedAt
+}
+
+// fieldMutationFields returns the fields that get DeleteBy and CountBy
+// methods: the filterable fields, when DomainConfig.FieldMutations is set.
+// The soft-delete field, which deleted rows only match, and the owner field,
+// which callers may only reach for their own owner, are left out.
+func fieldMutationFields(node *gen.Type) []*gen.Field {
+	cfg := getDomainConfigAnnotation(node)
+	if cfg == nil || !cfg.FieldMutations {
+		return nil
+	}
+	var fields []*gen.Field
+	for _, f := range filterableFields(node) {
+		if f.Name == cfg.OwnerField || (f.Name == "deleted_at" && hasSoftDelete(node)) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// isCached reports whether node gets a caching {Entity}CachedService, as
+// DomainConfig.Cached requests.
+func isCached(node *gen.Type) bool {
+	cfg := getDomainConfigAnnotation(node)
+	return cfg != nil && cfg.Cached
+}
+
+// ownerField returns the field named by DomainConfig.OwnerField, or nil when
+// rows have no owner. The field must be a string, UUID or integer field, and
+// the entity needs a single-field ID.
+func ownerField(node *gen.Type) (*gen.Field, error) {
+	cfg := getDomainConfigAnnotation(node)
+	if cfg == nil || cfg.OwnerField == "" {
+		return nil, nil
+	}
+	if !node.HasOneFieldID() {
+		return nil, fmt.Errorf("%s: owner field %q requires a single-field ID", node.Name, cfg.OwnerField)
+	}
+	for _, f := range node.Fields {
+		if f.Name != cfg.OwnerField {
+			continue
+		}
+		switch t := f.Type.Type; {
+		case t == field.TypeString, t == field.TypeUUID, t.Integer():
+			return f, nil
+		}
+		return nil, fmt.Errorf("%s: owner field %q must be a string, UUID or integer field", node.Name, f.Name)
+	}
+	return nil, fmt.Errorf("%s: unknown owner field %q", node.Name, cfg.OwnerField)
+}
+
+// archivableField returns the field named by DomainConfig.ArchivableField, or
+// nil when rows are not archivable. The field must be an Optional and
+// Nillable time field, nil while the row is not archived.
+func archivableField(node *gen.Type) (*gen.Field, error) {
+	cfg := getDomainConfigAnnotation(node)
+	if cfg == nil || cfg.ArchivableField == "" {
+		return nil, nil
+	}
+	if !node.HasOneFieldID() {
+		return nil, fmt.Errorf("%s: archivable field %q requires a single-field ID", node.Name, cfg.ArchivableField)
+	}
+	for _, f := range node.Fields {
+		if f.Name != cfg.ArchivableField {
+			continue
+		}
+		if f.Type.Type != field.TypeTime || !f.Optional || !f.Nillable {
+			return nil, fmt.Errorf("%s: archivable field %q must be an optional, nillable time field", node.Name, f.Name)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("%s: unknown archivable field %q", node.Name, cfg.ArchivableField)
+}
+
+// hasEvents reports whether node's service publishes domain events, as
+// DomainConfig.Events requests. Events carry the entity's ID, so it must be a
+// single field.
+func hasEvents(node *gen.Type) (bool, error) {
+	cfg := getDomainConfigAnnotation(node)
+	if cfg == nil || !cfg.Events {
+		return false, nil
+	}
+	if !node.HasOneFieldID() {
+		return false, fmt.Errorf("%s: events require a single-field ID", node.Name)
+	}
+	return true, nil
+}
@@ -0,0 +1,444 @@
+package entdomain
+
+import (
+	"testing"
+
+	"entgo.io/ent/entc/gen"
+)
+
+func TestGetDomainConfigAnnotation(t *testing.T) {
+	t.Run("nil node", func(t *testing.T) {
+		if getDomainConfigAnnotation(nil) != nil {
+			t.Error("expected nil for nil node")
+		}
+	})
+
+	t.Run("no annotation", func(t *testing.T) {
+		node := newTestType("User")
+		if getDomainConfigAnnotation(node) != nil {
+			t.Error("expected nil when DomainConfig is absent")
+		}
+	})
+
+	t.Run("typed value", func(t *testing.T) {
+		node := newTestType("User")
+		node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{EntityName: "member"}}
+		cfg := getDomainConfigAnnotation(node)
+		if cfg == nil || cfg.EntityName != "member" {
+			t.Errorf("getDomainConfigAnnotation() = %+v, want EntityName=member", cfg)
+		}
+	})
+
+	t.Run("pointer value", func(t *testing.T) {
+		node := newTestType("User")
+		node.Annotations = gen.Annotations{"DomainConfig": &DomainConfig{EntityName: "member"}}
+		cfg := getDomainConfigAnnotation(node)
+		if cfg == nil || cfg.EntityName != "member" {
+			t.Errorf("getDomainConfigAnnotation() = %+v, want EntityName=member", cfg)
+		}
+	})
+
+	t.Run("serialized map", func(t *testing.T) {
+		node := newTestType("User")
+		node.Annotations = gen.Annotations{"DomainConfig": map[string]interface{}{"entity_name": "member"}}
+		cfg := getDomainConfigAnnotation(node)
+		if cfg == nil || cfg.EntityName != "member" {
+			t.Errorf("getDomainConfigAnnotation() = %+v, want EntityName=member", cfg)
+		}
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		node := newTestType("User")
+		node.Annotations = gen.Annotations{"DomainConfig": 42}
+		if getDomainConfigAnnotation(node) != nil {
+			t.Error("expected nil for unsupported annotation type")
+		}
+	})
+}
+
+func TestResourceName(t *testing.T) {
+	t.Run("derived from schema name", func(t *testing.T) {
+		node := newTestType("UserProfile")
+		if got := resourceName(node); got != "user_profile" {
+			t.Errorf("resourceName() = %q, want %q", got, "user_profile")
+		}
+	})
+
+	t.Run("EntityName override", func(t *testing.T) {
+		node := newTestType("UserProfile")
+		node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{EntityName: "profile"}}
+		if got := resourceName(node); got != "profile" {
+			t.Errorf("resourceName() = %q, want %q", got, "profile")
+		}
+	})
+}
+
+func TestPluralName(t *testing.T) {
+	tests := []struct {
+		name   string
+		node   string
+		config *DomainConfig
+		want   string
+	}{
+		{"regular", "UserProfile", nil, "user_profiles"},
+		{"irregular", "Person", nil, "people"},
+		{"Plural override", "Person", &DomainConfig{Plural: "persons"}, "persons"},
+		{"follows EntityName", "UserProfile", &DomainConfig{EntityName: "profile"}, "profiles"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newTestType(tt.node)
+			if tt.config != nil {
+				node.Annotations = gen.Annotations{"DomainConfig": *tt.config}
+			}
+			if got := pluralName(node); got != tt.want {
+				t.Errorf("pluralName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourcePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		node   string
+		config *DomainConfig
+		want   string
+	}{
+		{"kebab-cased plural", "UserProfile", nil, "/user-profiles"},
+		{"Plural override", "Person", &DomainConfig{Plural: "humans"}, "/humans"},
+		{"URLPath override", "Person", &DomainConfig{Plural: "people", URLPath: "persons"}, "/persons"},
+		{"URLPath slashes trimmed", "Person", &DomainConfig{URLPath: "/admin/persons/"}, "/admin/persons"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newTestType(tt.node)
+			if tt.config != nil {
+				node.Annotations = gen.Annotations{"DomainConfig": *tt.config}
+			}
+			if got := resourcePath(node); got != tt.want {
+				t.Errorf("resourcePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIVersions(t *testing.T) {
+	node := newTestType("User")
+	if got := apiVersions(node, ""); got != nil {
+		t.Errorf("apiVersions() = %v, want nil", got)
+	}
+	if got := apiVersions(node, "v1"); len(got) != 1 || got[0] != "v1" {
+		t.Errorf("apiVersions() = %v, want [v1]", got)
+	}
+
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{APIVersions: []string{"v2", "v1"}}}
+	if got := apiVersions(node, "v1"); len(got) != 2 || got[0] != "v2" {
+		t.Errorf("apiVersions() = %v, want [v2 v1]", got)
+	}
+}
+
+func TestVersionedPath(t *testing.T) {
+	tests := []struct {
+		version, path, want string
+	}{
+		{"", "/users", "/users"},
+		{"v1", "/users", "/v1/users"},
+		{"/v2/", "/blog/articles", "/v2/blog/articles"},
+	}
+	for _, tt := range tests {
+		if got := versionedPath(tt.version, tt.path); got != tt.want {
+			t.Errorf("versionedPath(%q, %q) = %q, want %q", tt.version, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestVersionIdent(t *testing.T) {
+	tests := map[string]string{
+		"v1":      "V1",
+		"v1-beta": "V1beta",
+		"2024_01": "202401",
+	}
+	for in, want := range tests {
+		if got := versionIdent(in); got != want {
+			t.Errorf("versionIdent(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDeprecationNotice(t *testing.T) {
+	node := newTestType("Account")
+	if got := deprecationNotice(node); got != "" {
+		t.Errorf("deprecationNotice() = %q, want empty", got)
+	}
+
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.Deprecated("use AccountV2")}
+	if got := deprecationNotice(node); got != "use AccountV2" {
+		t.Errorf("deprecationNotice() = %q, want %q", got, "use AccountV2")
+	}
+}
+
+func TestSunsetExpr(t *testing.T) {
+	tests := []struct {
+		name    string
+		sunset  string
+		want    string
+		wantErr bool
+	}{
+		{"unset", "", "time.Time{}", false},
+		{"date", "2027-06-30", "time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC)", false},
+		{"malformed", "30/06/2027", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newTestType("Account")
+			node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{Sunset: tt.sunset}}
+			got, err := sunsetExpr(node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sunsetExpr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("sunsetExpr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDGeneratorExpr(t *testing.T) {
+	stringID := func(cfg DomainConfig) *gen.Type {
+		node := newTestType("Note")
+		node.ID = newStringField("id", nil)
+		node.Annotations = gen.Annotations{"DomainConfig": cfg}
+		return node
+	}
+
+	if got, err := idGeneratorExpr(stringID(DomainConfig{})); err != nil || got != "" {
+		t.Errorf("idGeneratorExpr() without generator = %q, %v, want empty", got, err)
+	}
+	if got, err := idGeneratorExpr(stringID(DomainConfig{}.WithIDGenerator("ulid"))); err != nil || got != "entdomain.ULIDGenerator{}" {
+		t.Errorf("idGeneratorExpr(ulid) = %q, %v", got, err)
+	}
+	if _, err := idGeneratorExpr(stringID(DomainConfig{}.WithIDGenerator("nope"))); err == nil {
+		t.Error("expected an unknown generator to fail")
+	}
+
+	intID := newTestType("Note")
+	intID.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithIDGenerator("ulid")}
+	if _, err := idGeneratorExpr(intID); err == nil {
+		t.Error("expected ulid on an integer ID to fail")
+	}
+
+	intID.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithGeneratedID(Snowflake)}
+	if got, err := idGeneratorExpr(intID); err != nil || got != "entdomain.DefaultSnowflake" {
+		t.Errorf("idGeneratorExpr(snowflake) = %q, %v", got, err)
+	}
+	if _, err := idGeneratorExpr(stringID(DomainConfig{}.WithGeneratedID(Snowflake))); err == nil {
+		t.Error("expected snowflake on a string ID to fail")
+	}
+}
+
+func TestIDPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"usr", "usr", false},
+		{"v2acct", "v2acct", false},
+		{"Usr", "", true},
+		{"usr_", "", true},
+		{"2fa", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			node := newTestType("User")
+			node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithIDPrefix(tt.prefix)}
+			got, err := idPrefix(node)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("idPrefix() = %q, %v, want %q (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	composite := newCompositeTestType("Membership", newIntField("user_id", nil), newIntField("group_id", nil))
+	composite.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithIDPrefix("mem")}
+	if _, err := idPrefix(composite); err == nil {
+		t.Error("expected a prefix on a composite key to fail")
+	}
+}
+
+func TestOptimisticLockField(t *testing.T) {
+	immutable := func(f *gen.Field) *gen.Field { f.Immutable = true; return f }
+	optional := func(f *gen.Field) *gen.Field { f.Optional = true; return f }
+	tests := []struct {
+		name    string
+		lock    string
+		want    string
+		wantErr bool
+	}{
+		{"unset", "", "", false},
+		{"int", "version", "version", false},
+		{"unknown", "revision", "", true},
+		{"string", "name", "", true},
+		{"immutable", "created_seq", "", true},
+		{"optional", "edits", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newTestType("Doc",
+				newIntField("version", ptr(OutputOnlyField())),
+				newStringField("name", ptr(DefaultField())),
+				immutable(newIntField("created_seq", nil)),
+				optional(newIntField("edits", nil)),
+			)
+			node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOptimisticLock(tt.lock)}
+			got, err := optimisticLockField(node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("optimisticLockField() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			var name string
+			if got != nil {
+				name = got.Name
+			}
+			if name != tt.want {
+				t.Errorf("optimisticLockField() = %q, want %q", name, tt.want)
+			}
+		})
+	}
+}
+
+func TestETagField(t *testing.T) {
+	version := newIntField("version", ptr(OutputOnlyField()))
+	updatedAt := newTimeField("updated_at", ptr(OutputOnlyField()))
+	hidden := newTimeField("updated_at", ptr(InputOnlyField()))
+
+	node := newTestType("Doc", updatedAt, version)
+	if got := etagField(node); got != updatedAt {
+		t.Errorf("etagField() without a lock = %v, want updated_at", got)
+	}
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOptimisticLock("version")}
+	if got := etagField(node); got != version {
+		t.Errorf("etagField() with a lock = %v, want version", got)
+	}
+	if got := etagField(newTestType("Doc", hidden)); got != nil {
+		t.Errorf("etagField() without response fields = %v, want nil", got)
+	}
+}
+
+func TestFieldMutationFields(t *testing.T) {
+	node := newTestType("User",
+		newStringField("status", ptr(NewDomainField().AsFilterable())),
+		newStringField("name", ptr(NewDomainField())),
+	)
+	if got := fieldMutationFields(node); got != nil {
+		t.Errorf("fieldMutationFields() without WithFieldMutations = %v", got)
+	}
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithFieldMutations()}
+	if got := fieldMutationFields(node); len(got) != 1 || got[0].Name != "status" {
+		t.Errorf("fieldMutationFields() = %v, want only status", got)
+	}
+
+	deleted := newTimeField("deleted_at", ptr(NewDomainField().AsFilterable()))
+	deleted.Optional, deleted.Nillable = true, true
+	node.Fields = append(node.Fields, deleted, newStringField("owner_id", ptr(NewDomainField().AsFilterable())))
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithFieldMutations().WithOwnerField("owner_id")}
+	if got := fieldMutationFields(node); len(got) != 1 || got[0].Name != "status" {
+		t.Errorf("fieldMutationFields() with soft delete and owner fields = %v, want only status", got)
+	}
+}
+
+func TestIsCached(t *testing.T) {
+	node := newTestType("User")
+	if isCached(node) {
+		t.Error("isCached() without WithCache = true")
+	}
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithCache()}
+	if !isCached(node) {
+		t.Error("isCached() with WithCache = false")
+	}
+}
+
+func TestHasEvents(t *testing.T) {
+	node := newTestType("User")
+	if got, err := hasEvents(node); got || err != nil {
+		t.Errorf("hasEvents() without WithEvents = %v, %v", got, err)
+	}
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithEvents()}
+	if got, err := hasEvents(node); !got || err != nil {
+		t.Errorf("hasEvents() with WithEvents = %v, %v", got, err)
+	}
+	node.ID = nil
+	if _, err := hasEvents(node); err == nil {
+		t.Error("hasEvents() without a single-field ID should fail")
+	}
+}
+
+func TestOwnerField(t *testing.T) {
+	tests := []struct {
+		name    string
+		owner   string
+		want    string
+		wantErr bool
+	}{
+		{"unset", "", "", false},
+		{"uuid", "user_id", "user_id", false},
+		{"int", "team_id", "team_id", false},
+		{"unknown", "owner_id", "", true},
+		{"time", "created_at", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newTestType("Post",
+				newUUIDField("user_id", ptr(DefaultField())),
+				newIntField("team_id", ptr(DefaultField())),
+				newTimeField("created_at", ptr(OutputOnlyField())),
+			)
+			node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOwnerField(tt.owner)}
+			got, err := ownerField(node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ownerField() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			var name string
+			if got != nil {
+				name = got.Name
+			}
+			if name != tt.want {
+				t.Errorf("ownerField() = %q, want %q", name, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchivableField(t *testing.T) {
+	archived := newTimeField("archived_at", ptr(DefaultField()))
+	archived.Optional, archived.Nillable = true, true
+	tests := []struct {
+		name    string
+		field   string
+		want    string
+		wantErr bool
+	}{
+		{"unset", "", "", false},
+		{"nillable time", "archived_at", "archived_at", false},
+		{"required time", "created_at", "", true},
+		{"unknown", "hidden_at", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newTestType("Post", archived, newTimeField("created_at", ptr(OutputOnlyField())))
+			node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithArchivableField(tt.field)}
+			got, err := archivableField(node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("archivableField() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			var name string
+			if got != nil {
+				name = got.Name
+			}
+			if name != tt.want {
+				t.Errorf("archivableField() = %q, want %q", name, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package entdomain
 
 import (
+	"fmt"
+	"go/token"
+	"strings"
+
 	"entgo.io/ent/entc/gen"
 )
 
@@ -28,11 +32,16 @@ func createFields(node *gen.Type) []*gen.Field {
 	return fields
 }
 
-// updateFields returns fields that can be used in update requests
+// updateFields returns fields that can be used in update requests. The
+// optimistic lock field is left out: Update increments it itself.
 func updateFields(node *gen.Type) []*gen.Field {
+	var lock string
+	if cfg := getDomainConfigAnnotation(node); cfg != nil {
+		lock = cfg.OptimisticLock
+	}
 	var fields []*gen.Field
 	for _, field := range node.Fields {
-		if annotation := getDomainFieldAnnotation(field); annotation != nil {
+		if annotation := getDomainFieldAnnotation(field); annotation != nil && field.Name != lock {
 			if hasDomainScope(field, ScopeUpdate) {
 				fields = append(fields, field)
 			}
@@ -41,6 +50,31 @@ func updateFields(node *gen.Type) []*gen.Field {
 	return fields
 }
 
+// clearableFields returns the optional update fields, which a patch removing
+// them clears.
+func clearableFields(node *gen.Type) []*gen.Field {
+	var fields []*gen.Field
+	for _, field := range updateFields(node) {
+		if field.Optional {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// patchDocFields returns the update fields whose current values a patch
+// is applied to. Sensitive fields are left out, so patches can set them but
+// not read them back through test operations.
+func patchDocFields(node *gen.Type) []*gen.Field {
+	var fields []*gen.Field
+	for _, field := range updateFields(node) {
+		if !isSensitive(field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
 // responseFields returns fields that can be used in responses
 func responseFields(node *gen.Type) []*gen.Field {
 	var fields []*gen.Field
@@ -81,6 +115,87 @@ func searchableFields(node *gen.Type) []*gen.Field {
 	return fields
 }
 
+// fullTextFields returns the string fields matched by full-text search
+func fullTextFields(node *gen.Type) []*gen.Field {
+	var fields []*gen.Field
+	for _, field := range node.Fields {
+		if isFullTextField(field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// isFullTextField reports whether field is a full-text searchable string field
+func isFullTextField(field *gen.Field) bool {
+	annotation := getDomainFieldAnnotation(field)
+	return annotation != nil && annotation.FullTextSearchable && field.Type.String() == "string"
+}
+
+// searchAlias is a logical query field matching any of several string fields.
+type searchAlias struct {
+	Name   string
+	Fields []*gen.Field
+}
+
+// searchAliases returns the search aliases of node in order of first use.
+// Non-string fields and aliases that clash with a field name are skipped;
+// strict mode reports them.
+func searchAliases(node *gen.Type) []searchAlias {
+	var aliases []searchAlias
+	index := make(map[string]int)
+	for _, field := range node.Fields {
+		annotation := getDomainFieldAnnotation(field)
+		if annotation == nil || annotation.SearchAlias == "" || field.Type.String() != "string" || searchAliasClash(node, annotation.SearchAlias) {
+			continue
+		}
+		i, ok := index[annotation.SearchAlias]
+		if !ok {
+			i = len(aliases)
+			index[annotation.SearchAlias] = i
+			aliases = append(aliases, searchAlias{Name: annotation.SearchAlias})
+		}
+		aliases[i].Fields = append(aliases[i].Fields, field)
+	}
+	return aliases
+}
+
+// searchAliasClash reports whether alias is also the name of a field of node,
+// which would collide in QueryParams and Filters.
+func searchAliasClash(node *gen.Type, alias string) bool {
+	for _, field := range node.Fields {
+		if field.Name == alias || field.StorageKey() == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// isCaseInsensitive reports whether field is a string field compared ignoring case
+func isCaseInsensitive(field *gen.Field) bool {
+	annotation := getDomainFieldAnnotation(field)
+	return annotation != nil && annotation.CaseInsensitive && field.Type.String() == "string"
+}
+
+// filterableFields returns fields that can be filtered by equality in Search:
+// Filterable fields of the types searchMethod knows how to compare.
+func filterableFields(node *gen.Type) []*gen.Field {
+	var fields []*gen.Field
+	for _, field := range node.Fields {
+		annotation := getDomainFieldAnnotation(field)
+		if annotation == nil || !annotation.Filterable {
+			continue
+		}
+		switch ft := field.Type.String(); {
+		case field.IsEnum(), isUUIDType(ft),
+			ft == "string", ft == "int", ft == "int32", ft == "int64",
+			ft == "bool", ft == "time.Time", ft == "float64", ft == "float32":
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
 // sortableFields returns fields that can be sorted
 func sortableFields(node *gen.Type) []*gen.Field {
 	var fields []*gen.Field
@@ -107,19 +222,117 @@ func uniqueLookupFields(node *gen.Type) []*gen.Field {
 	return fields
 }
 
-// responseEdges returns edges suitable for inclusion in HTTP responses.
-// An edge qualifies when: (1) it has a FK field on this entity,
-// (2) that FK field has ScopeResponse, and (3) the target type is a domain entity.
+// uniqueIndexLookup is a composite unique index, looked up by generated
+// FindBy{Name} methods taking one parameter per field.
+type uniqueIndexLookup struct {
+	Name   string
+	Fields []*gen.Field
+	Params []string
+}
+
+// uniqueIndexLookups returns the unique indexes of node over two or more
+// fields, e.g. tenant_id and slug, named "TenantIDAndSlug". Indexes on columns
+// that are not fields, such as edge columns without an edge field, are
+// skipped.
+func uniqueIndexLookups(node *gen.Type) []uniqueIndexLookup {
+	byColumn := make(map[string]*gen.Field, len(node.Fields))
+	for _, field := range node.Fields {
+		byColumn[field.StorageKey()] = field
+	}
+	var lookups []uniqueIndexLookup
+	seen := make(map[string]bool)
+	for _, index := range node.Indexes {
+		if !index.Unique || len(index.Columns) < 2 {
+			continue
+		}
+		var lookup uniqueIndexLookup
+		var names []string
+		for _, column := range index.Columns {
+			if field, ok := byColumn[column]; ok {
+				names = append(names, field.StructField())
+				lookup.Fields = append(lookup.Fields, field)
+				lookup.Params = append(lookup.Params, paramName(field.StructField()))
+			}
+		}
+		lookup.Name = strings.Join(names, "And")
+		if len(lookup.Fields) < len(index.Columns) || seen[lookup.Name] {
+			continue
+		}
+		seen[lookup.Name] = true
+		lookups = append(lookups, lookup)
+	}
+	return lookups
+}
+
+// paramName returns the camelCase parameter name for a field's struct name,
+// suffixed with "Value" when it is a Go keyword or a name generated methods
+// use themselves.
+func paramName(structField string) string {
+	name := camelCase(structField)
+	switch name {
+	case "ctx", "s", "db", "err", "owned":
+		return name + "Value"
+	}
+	if token.IsKeyword(name) {
+		return name + "Value"
+	}
+	return name
+}
+
+// upsertFields returns the Unique create fields an upsert can be keyed on,
+// unique lookup fields first. The first one keys Upsert.
+func upsertFields(node *gen.Type) []*gen.Field {
+	var lookups, others []*gen.Field
+	for _, field := range createFields(node) {
+		switch {
+		case !field.Unique:
+		case isUniqueLookupField(field):
+			lookups = append(lookups, field)
+		default:
+			others = append(others, field)
+		}
+	}
+	return append(lookups, others...)
+}
+
+// responseEdges returns edges suitable for inclusion in HTTP responses, which
+// are also the edges services eager load by name. An edge qualifies when it
+// carries the DomainEdge annotation and its target type is a domain entity, or
+// when: (1) it has a FK field on this entity, (2) that FK field has
+// ScopeResponse, and (3) the target type is a domain entity.
 func responseEdges(node *gen.Type) []*gen.Edge {
 	var edges []*gen.Edge
 	for _, edge := range node.Edges {
-		if edgeQualifiesForResponse(edge.Field(), edge.Type) {
+		if isDomainEdge(edge) || edgeQualifiesForResponse(edge.Field(), edge.Type) {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// mutationEdges returns the DomainEdge edges that services get mutation
+// methods for: Add and Remove on to-many edges, Set and Clear on to-one ones.
+// Immutable edges and edges to types without a single ID field are skipped,
+// as ent generates no ID setters for them.
+func mutationEdges(node *gen.Type) []*gen.Edge {
+	var edges []*gen.Edge
+	for _, edge := range node.Edges {
+		if isDomainEdge(edge) && !edge.Immutable && edge.Type.HasOneFieldID() {
 			edges = append(edges, edge)
 		}
 	}
 	return edges
 }
 
+// isDomainEdge reports whether edge carries the DomainEdge annotation and
+// leads to a domain entity.
+func isDomainEdge(edge *gen.Edge) bool {
+	if _, ok := edge.Annotations[DomainEdge{}.Name()]; !ok {
+		return false
+	}
+	return len(domainFields(edge.Type)) > 0
+}
+
 // edgeQualifiesForResponse checks if an edge with the given FK field and target
 // type qualifies for inclusion in response structs. Separated from responseEdges
 // for testability, since edge.Field() depends on unexported ent internals.
@@ -144,3 +357,58 @@ func rangeLookupFields(node *gen.Type) []*gen.Field {
 	}
 	return fields
 }
+
+// shardKeyField returns the field annotated with AsShardKey, or nil if the
+// entity is not sharded. Only the first annotated field is used.
+func shardKeyField(node *gen.Type) *gen.Field {
+	for _, field := range domainFields(node) {
+		if annotation := getDomainFieldAnnotation(field); annotation != nil && annotation.ShardKey {
+			return field
+		}
+	}
+	return nil
+}
+
+// auditedFields returns the fields annotated with AsAudited. Audit entries
+// carry the entity's ID, so it must be a single field, and they are stored
+// outside the handler layer, so sensitive fields are rejected.
+func auditedFields(node *gen.Type) ([]*gen.Field, error) {
+	var fields []*gen.Field
+	for _, field := range domainFields(node) {
+		annotation := getDomainFieldAnnotation(field)
+		if !annotation.Audited {
+			continue
+		}
+		if annotation.Sensitive {
+			return nil, fmt.Errorf("%s: sensitive field %q cannot be audited", node.Name, field.Name)
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) > 0 && !node.HasOneFieldID() {
+		return nil, fmt.Errorf("%s: audited fields require a single-field ID", node.Name)
+	}
+	return fields, nil
+}
+
+// defaultSortField returns the field annotated with AsDefaultSort, or nil when
+// the entity has none (List then orders by ID). Only the first annotated
+// sortable field is used.
+func defaultSortField(node *gen.Type) *gen.Field {
+	for _, field := range sortableFields(node) {
+		if annotation := getDomainFieldAnnotation(field); annotation != nil && annotation.DefaultSort != "" {
+			return field
+		}
+	}
+	return nil
+}
+
+// defaultSortOrder returns the normalized default List order, "asc" or
+// "desc". It is "asc" when there is no default sort field.
+func defaultSortOrder(node *gen.Type) string {
+	if field := defaultSortField(node); field != nil {
+		if strings.EqualFold(getDomainFieldAnnotation(field).DefaultSort, "desc") {
+			return "desc"
+		}
+	}
+	return "asc"
+}
@@ -68,6 +68,20 @@ func TestUpdateFields(t *testing.T) {
 	}
 }
 
+func TestPatchFieldSets(t *testing.T) {
+	nickname := newStringField("nickname", ptr(DefaultField()))
+	nickname.Optional = true
+	password := newStringField("password", ptr(InputOnlyField().AsSensitive()))
+	node := newTestType("User", newStringField("name", ptr(DefaultField())), nickname, password)
+
+	if got := clearableFields(node); len(got) != 1 || got[0].Name != "nickname" {
+		t.Errorf("clearableFields() = %v, want [nickname]", got)
+	}
+	if got := patchDocFields(node); len(got) != 2 || got[0].Name != "name" || got[1].Name != "nickname" {
+		t.Errorf("patchDocFields() = %v, want [name nickname]", got)
+	}
+}
+
 func TestResponseFields(t *testing.T) {
 	withResp := ptr(DomainFieldWithScopes(ScopeResponse))
 	withCreate := ptr(DomainFieldWithScopes(ScopeCreate))
@@ -124,6 +138,45 @@ func TestSearchableFields(t *testing.T) {
 	}
 }
 
+func TestFullTextFields(t *testing.T) {
+	fullText := ptr(NewDomainField().AsFullTextSearchable())
+	searchable := ptr(DomainField{Searchable: true, Scopes: AllFieldScopes})
+
+	node := newTestType("User",
+		newStringField("bio", fullText),
+		newStringField("name", searchable),
+		newIntField("age", fullText),
+	)
+
+	got := fullTextFields(node)
+	if len(got) != 1 || got[0].Name != "bio" {
+		t.Fatalf("expected only 'bio', got %d fields", len(got))
+	}
+}
+
+func TestSearchAliases(t *testing.T) {
+	alias := func(name string) *DomainField { return ptr(DefaultField().WithSearchAlias(name)) }
+
+	node := newTestType("User",
+		newStringField("first_name", alias("name")),
+		newStringField("email", alias("contact")),
+		newStringField("last_name", alias("name")),
+		newIntField("age", alias("name")),
+		newStringField("phone", alias("email")),
+	)
+
+	got := searchAliases(node)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 aliases, got %d", len(got))
+	}
+	if got[0].Name != "name" || len(got[0].Fields) != 2 || got[0].Fields[0].Name != "first_name" || got[0].Fields[1].Name != "last_name" {
+		t.Errorf("unexpected 'name' alias %+v", got[0])
+	}
+	if got[1].Name != "contact" || len(got[1].Fields) != 1 {
+		t.Errorf("unexpected 'contact' alias %+v", got[1])
+	}
+}
+
 func TestSortableFields(t *testing.T) {
 	sortable := ptr(DomainField{Sortable: true, Scopes: AllFieldScopes})
 	notSortable := ptr(DomainFieldWithScopes(ScopeCreate))
@@ -153,6 +206,24 @@ func TestSortableFieldsExcludesComplex(t *testing.T) {
 	}
 }
 
+func TestFilterableFields(t *testing.T) {
+	filterable := ptr(DomainField{Filterable: true, Scopes: AllFieldScopes})
+	notFilterable := ptr(DefaultField())
+	notFilterable.Filterable = false
+
+	node := newTestType("User",
+		newStringField("name", filterable),
+		newIntField("age", filterable),
+		newField("tags", &field.TypeInfo{Type: field.TypeJSON, Ident: "[]string"}, filterable),
+		newStringField("code", notFilterable),
+	)
+
+	got := filterableFields(node)
+	if len(got) != 2 || got[0].Name != "name" || got[1].Name != "age" {
+		t.Fatalf("filterableFields() = %v, want [name age]", got)
+	}
+}
+
 func TestUniqueLookupFields(t *testing.T) {
 	withLookup := ptr(DomainField{UniqueLookup: true, Scopes: AllFieldScopes})
 	withoutLookup := ptr(DefaultField())
@@ -171,6 +242,61 @@ func TestUniqueLookupFields(t *testing.T) {
 	}
 }
 
+func TestUpdateFieldsExcludeOptimisticLock(t *testing.T) {
+	node := newTestType("Doc",
+		newStringField("title", ptr(DefaultField())),
+		newIntField("version", ptr(DefaultField())),
+	)
+	node.Annotations = gen.Annotations{"DomainConfig": DomainConfig{}.WithOptimisticLock("version")}
+
+	got := updateFields(node)
+	if len(got) != 1 || got[0].Name != "title" {
+		t.Fatalf("updateFields() = %v, want [title]", got)
+	}
+}
+
+func TestUpsertFields(t *testing.T) {
+	unique := func(f *gen.Field) *gen.Field { f.Unique = true; return f }
+
+	node := newTestType("User",
+		unique(newStringField("slug", ptr(DefaultField()))),
+		unique(newStringField("email", ptr(DefaultField().AsUniqueLookup()))),
+		newStringField("name", ptr(DefaultField())),
+		unique(newStringField("token", ptr(OutputOnlyField()))),
+	)
+
+	got := upsertFields(node)
+	if len(got) != 2 || got[0].Name != "email" || got[1].Name != "slug" {
+		t.Fatalf("upsertFields() = %v, want [email slug]", got)
+	}
+}
+
+func TestUniqueIndexLookups(t *testing.T) {
+	node := newTestType("Page",
+		newIntField("tenant_id", ptr(DefaultField())),
+		newStringField("slug", ptr(DefaultField())),
+		newStringField("type", ptr(DefaultField())),
+	)
+	node.Indexes = []*gen.Index{
+		{Unique: true, Columns: []string{"tenant_id", "slug"}},
+		{Unique: true, Columns: []string{"slug"}},
+		{Columns: []string{"tenant_id", "type"}},
+		{Unique: true, Columns: []string{"type", "owner_pages"}},
+		{Unique: true, Columns: []string{"tenant_id", "type"}},
+	}
+
+	got := uniqueIndexLookups(node)
+	if len(got) != 2 {
+		t.Fatalf("uniqueIndexLookups() = %+v, want 2 lookups", got)
+	}
+	if got[0].Name != "TenantIDAndSlug" || got[0].Params[0] != "tenantID" || got[0].Params[1] != "slug" {
+		t.Errorf("first lookup = %s(%v)", got[0].Name, got[0].Params)
+	}
+	if got[1].Name != "TenantIDAndType" || got[1].Params[1] != "typeValue" {
+		t.Errorf("second lookup = %s(%v)", got[1].Name, got[1].Params)
+	}
+}
+
 func TestRangeLookupFields(t *testing.T) {
 	withRange := ptr(DomainField{RangeLookup: true, Scopes: AllFieldScopes})
 	withoutRange := ptr(DefaultField())
@@ -253,3 +379,120 @@ func TestResponseEdges_EdgesWithoutFK(t *testing.T) {
 		t.Fatalf("expected 0 response edges (no FK), got %d", len(got))
 	}
 }
+
+func TestResponseEdges_DomainEdge(t *testing.T) {
+	// The DomainEdge annotation exposes edges without a FK on this entity,
+	// as long as the target is a domain entity.
+	node := newTestType("User", newStringField("name", ptr(DefaultField())))
+	target := newTestType("Post", newStringField("title", ptr(DefaultField())))
+	annotated := gen.Annotations{DomainEdge{}.Name(): map[string]interface{}{}}
+	node.Edges = []*gen.Edge{
+		{Name: "posts", Type: target, Annotations: annotated},
+		{Name: "groups", Type: newTestType("Group"), Annotations: annotated},
+		{Name: "profile", Type: target, Unique: true},
+	}
+	got := responseEdges(node)
+	if len(got) != 1 || got[0].Name != "posts" {
+		t.Fatalf("responseEdges() = %v, want only posts", got)
+	}
+}
+
+func TestMutationEdges(t *testing.T) {
+	node := newTestType("User", newStringField("name", ptr(DefaultField())))
+	target := newTestType("Post", newStringField("title", ptr(DefaultField())))
+	keyless := newTestType("Membership", newStringField("role", ptr(DefaultField())))
+	keyless.ID = nil
+	annotated := gen.Annotations{DomainEdge{}.Name(): map[string]interface{}{}}
+	node.Edges = []*gen.Edge{
+		{Name: "posts", Type: target, Annotations: annotated},
+		{Name: "pinned", Type: target, Unique: true, Immutable: true, Annotations: annotated},
+		{Name: "memberships", Type: keyless, Annotations: annotated},
+		{Name: "drafts", Type: target},
+	}
+	got := mutationEdges(node)
+	if len(got) != 1 || got[0].Name != "posts" {
+		t.Fatalf("mutationEdges() = %v, want only posts", got)
+	}
+}
+
+func TestShardKeyField(t *testing.T) {
+	t.Run("returns annotated field", func(t *testing.T) {
+		node := newTestType("User",
+			newStringField("name", ptr(DefaultField())),
+			newStringField("region", ptr(CreateOnlyField().AsShardKey())),
+		)
+		got := shardKeyField(node)
+		if got == nil || got.Name != "region" {
+			t.Fatalf("shardKeyField() = %v, want region", got)
+		}
+	})
+
+	t.Run("nil when not sharded", func(t *testing.T) {
+		node := newTestType("User", newStringField("name", ptr(DefaultField())))
+		if got := shardKeyField(node); got != nil {
+			t.Errorf("shardKeyField() = %v, want nil", got.Name)
+		}
+	})
+}
+
+func TestAuditedFields(t *testing.T) {
+	node := newTestType("User",
+		newStringField("name", ptr(DefaultField().AsAudited())),
+		newStringField("bio", ptr(DefaultField())),
+	)
+	got, err := auditedFields(node)
+	if err != nil || len(got) != 1 || got[0].Name != "name" {
+		t.Fatalf("auditedFields() = %v, %v, want [name]", got, err)
+	}
+
+	secret := newTestType("User", newStringField("password", ptr(InputOnlyField().AsSensitive().AsAudited())))
+	if _, err := auditedFields(secret); err == nil {
+		t.Error("auditedFields() with a sensitive field should fail")
+	}
+
+	composite := newTestType("Membership", newStringField("role", ptr(DefaultField().AsAudited())))
+	composite.ID = nil
+	if _, err := auditedFields(composite); err == nil {
+		t.Error("auditedFields() without a single-field ID should fail")
+	}
+}
+
+func TestDefaultSortField(t *testing.T) {
+	t.Run("returns annotated field and order", func(t *testing.T) {
+		node := newTestType("User",
+			newStringField("name", ptr(DefaultField())),
+			newTimeField("created_at", ptr(OutputOnlyField().AsDefaultSort("DESC"))),
+		)
+		got := defaultSortField(node)
+		if got == nil || got.Name != "created_at" {
+			t.Fatalf("defaultSortField() = %v, want created_at", got)
+		}
+		if order := defaultSortOrder(node); order != "desc" {
+			t.Errorf("defaultSortOrder() = %q, want desc", order)
+		}
+	})
+
+	t.Run("unknown order falls back to asc", func(t *testing.T) {
+		node := newTestType("User", newStringField("name", ptr(DefaultField().AsDefaultSort("sideways"))))
+		if order := defaultSortOrder(node); order != "asc" {
+			t.Errorf("defaultSortOrder() = %q, want asc", order)
+		}
+	})
+
+	t.Run("nil without annotation", func(t *testing.T) {
+		node := newTestType("User", newStringField("name", ptr(DefaultField())))
+		if got := defaultSortField(node); got != nil {
+			t.Errorf("defaultSortField() = %v, want nil", got.Name)
+		}
+		if order := defaultSortOrder(node); order != "asc" {
+			t.Errorf("defaultSortOrder() = %q, want asc", order)
+		}
+	})
+
+	t.Run("ignores complex field types", func(t *testing.T) {
+		tags := newField("tags", &field.TypeInfo{Type: field.TypeJSON, Ident: "[]string"}, ptr(DefaultField().AsDefaultSort("asc")))
+		if got := defaultSortField(newTestType("Post", tags)); got != nil {
+			t.Errorf("defaultSortField() = %v, want nil for JSON field", got.Name)
+		}
+	})
+}
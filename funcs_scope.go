@@ -2,6 +2,9 @@ package entdomain
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 
 	"entgo.io/ent/entc/gen"
 )
@@ -36,6 +39,38 @@ func isDomainRequired(field *gen.Field, scope FieldScope) bool {
 	return exists && required
 }
 
+// validateTag returns the go-playground/validator rules of the validate tag
+// field gets in the request of scope: "required" when it is required there,
+// otherwise "omitempty", followed by its DomainField.Validation rules by name.
+// A rule set to true takes no parameter ("email"); others render as "min=3".
+// A field neither required nor with rules gets no tag, and "" is returned.
+func validateTag(field *gen.Field, scope FieldScope) string {
+	var rules []string
+	if annotation := getDomainFieldAnnotation(field); annotation != nil {
+		names := make([]string, 0, len(annotation.Validation))
+		for name := range annotation.Validation {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			switch v := annotation.Validation[name]; v {
+			case nil, false:
+			case true:
+				rules = append(rules, name)
+			default:
+				rules = append(rules, fmt.Sprintf("%s=%v", name, v))
+			}
+		}
+	}
+	if isDomainRequired(field, scope) {
+		return strings.Join(append([]string{"required"}, rules...), ",")
+	}
+	if len(rules) == 0 {
+		return ""
+	}
+	return strings.Join(append([]string{"omitempty"}, rules...), ",")
+}
+
 // getDomainFieldAnnotation extracts a DomainField annotation from a gen.Field.
 // Ent annotations arrive as *DomainField at codegen time, but as
 // map[string]interface{} when loaded from a serialized schema. This function
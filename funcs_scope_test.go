@@ -221,3 +221,25 @@ func TestGetDomainFieldAnnotation_NilAnnotations(t *testing.T) {
 		t.Errorf("expected nil for nil annotations, got %v", got)
 	}
 }
+
+func TestValidateTag(t *testing.T) {
+	rules := map[string]interface{}{"min": 3, "email": true, "alpha": false}
+	tests := []struct {
+		name  string
+		field *gen.Field
+		scope FieldScope
+		want  string
+	}{
+		{"no annotation", newStringField("name", nil), ScopeCreate, ""},
+		{"required", newStringField("name", ptr(DomainField{Required: map[FieldScope]bool{ScopeCreate: true}})), ScopeCreate, "required"},
+		{"required with rules", newStringField("email", ptr(DomainField{Required: map[FieldScope]bool{ScopeCreate: true}, Validation: rules})), ScopeCreate, "required,email,min=3"},
+		{"optional with rules", newStringField("email", ptr(DomainField{Required: map[FieldScope]bool{ScopeCreate: true}, Validation: rules})), ScopeUpdate, "omitempty,email,min=3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateTag(tt.field, tt.scope); got != tt.want {
+				t.Errorf("validateTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
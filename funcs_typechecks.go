@@ -11,6 +11,13 @@ func isUniqueField(field *gen.Field) bool {
 	return field.Unique
 }
 
+// isSensitive checks if a field is sensitive, via ent's Sensitive() builder or
+// the DomainField annotation.
+func isSensitive(field *gen.Field) bool {
+	annotation := getDomainFieldAnnotation(field)
+	return field.Sensitive() || (annotation != nil && annotation.Sensitive)
+}
+
 // isTimeField checks if a field is a time field.
 func isTimeField(field *gen.Field) bool {
 	return strings.Contains(field.Type.String(), "time.Time")
@@ -61,6 +68,24 @@ func hasSoftDelete(node *gen.Type) bool {
 	return false
 }
 
+// hasUpdatedAt checks if an entity has a mutable updated_at time field
+// (convention-based detection), which the generated Touch method sets.
+func hasUpdatedAt(node *gen.Type) bool {
+	for _, field := range node.Fields {
+		if field.Name == "updated_at" && isTimeField(field) && !field.Immutable {
+			return true
+		}
+	}
+	return false
+}
+
+// hasExpiry checks if an entity has an expires_at time field (convention-based
+// expiry detection). Rows whose expires_at is in the past are purged by the
+// generated PurgeExpired job.
+func hasExpiry(node *gen.Type) bool {
+	return hasTimeField(node, "expires_at")
+}
+
 // isComplexFieldType checks if a field type is too complex for basic
 // operations like sorting (slices, maps, JSON types).
 func isComplexFieldType(fieldType string) bool {
@@ -18,6 +18,18 @@ func TestIsUniqueField(t *testing.T) {
 	}
 }
 
+func TestIsSensitive(t *testing.T) {
+	annotated := newStringField("token", ptr(DefaultField().AsSensitive()))
+	plain := newStringField("name", ptr(DefaultField()))
+
+	if !isSensitive(annotated) {
+		t.Error("expected AsSensitive field to return true")
+	}
+	if isSensitive(plain) || isSensitive(newStringField("bio", nil)) {
+		t.Error("expected other fields to return false")
+	}
+}
+
 func TestIsTimeField(t *testing.T) {
 	timeField := newTimeField("created_at", nil)
 	stringField := newStringField("name", nil)
@@ -84,6 +96,35 @@ func TestHasTimeField(t *testing.T) {
 	}
 }
 
+func TestHasExpiry(t *testing.T) {
+	df := ptr(DefaultField())
+
+	if !hasExpiry(newTestType("Session", newTimeField("expires_at", df))) {
+		t.Error("expected hasExpiry to return true for a type with expires_at")
+	}
+	if hasExpiry(newTestType("Session", newStringField("expires_at", df))) {
+		t.Error("expected hasExpiry to return false when expires_at is not a time field")
+	}
+	if hasExpiry(newTestType("User", newTimeField("created_at", df))) {
+		t.Error("expected hasExpiry to return false without expires_at")
+	}
+}
+
+func TestHasUpdatedAt(t *testing.T) {
+	updated := newTimeField("updated_at", nil)
+	if !hasUpdatedAt(newTestType("User", updated)) {
+		t.Error("expected hasUpdatedAt to return true for a type with updated_at")
+	}
+	frozen := newTimeField("updated_at", nil)
+	frozen.Immutable = true
+	if hasUpdatedAt(newTestType("User", frozen)) {
+		t.Error("expected hasUpdatedAt to return false for an immutable updated_at")
+	}
+	if hasUpdatedAt(newTestType("User", newTimeField("created_at", nil))) {
+		t.Error("expected hasUpdatedAt to return false without updated_at")
+	}
+}
+
 func TestIsUUIDType(t *testing.T) {
 	tests := []struct {
 		input  string
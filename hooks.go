@@ -0,0 +1,87 @@
+package entdomain
+
+import "context"
+
+// RepoHooks are callbacks a generated base service runs around its writes,
+// after the hook methods dispatched through SetSelf. They are plain fields, so
+// cross-cutting concerns such as audit logging or cache invalidation attach
+// when the service is constructed, without embedding it:
+//
+//	users := &ent.BaseUserService{
+//		DB: client,
+//		RepoHooks: ent.UserRepoHooks{
+//			AfterUpdate: func(ctx context.Context, u *ent.User) error {
+//				return cache.Delete(ctx, userKey(u.ID))
+//			},
+//		},
+//	}
+//
+// T is the entity, ID its ID type, C and U its create and update requests;
+// generated code declares an {Entity}RepoHooks alias for each entity. Nil
+// callbacks are skipped. An error from a Before callback aborts the operation;
+// one from an After callback is returned after the write, which is only undone
+// when the call runs in a transaction.
+type RepoHooks[T, ID, C, U any] struct {
+	BeforeCreate func(ctx context.Context, req C) error
+	AfterCreate  func(ctx context.Context, entity T) error
+	BeforeUpdate func(ctx context.Context, id ID, req U) error
+	AfterUpdate  func(ctx context.Context, entity T) error
+	BeforeDelete func(ctx context.Context, id ID) error
+	AfterDelete  func(ctx context.Context, id ID) error
+}
+
+// ChainRepoHooks returns RepoHooks running the callbacks of each of hooks in
+// order, stopping at the first error.
+func ChainRepoHooks[T, ID, C, U any](hooks ...RepoHooks[T, ID, C, U]) RepoHooks[T, ID, C, U] {
+	return RepoHooks[T, ID, C, U]{
+		BeforeCreate: chainHook(hooks, func(h RepoHooks[T, ID, C, U]) func(context.Context, C) error { return h.BeforeCreate }),
+		AfterCreate:  chainHook(hooks, func(h RepoHooks[T, ID, C, U]) func(context.Context, T) error { return h.AfterCreate }),
+		BeforeUpdate: chainHook2(hooks, func(h RepoHooks[T, ID, C, U]) func(context.Context, ID, U) error { return h.BeforeUpdate }),
+		AfterUpdate:  chainHook(hooks, func(h RepoHooks[T, ID, C, U]) func(context.Context, T) error { return h.AfterUpdate }),
+		BeforeDelete: chainHook(hooks, func(h RepoHooks[T, ID, C, U]) func(context.Context, ID) error { return h.BeforeDelete }),
+		AfterDelete:  chainHook(hooks, func(h RepoHooks[T, ID, C, U]) func(context.Context, ID) error { return h.AfterDelete }),
+	}
+}
+
+// chainHook combines the callbacks that pick selects from hooks, returning
+// nil when none is set.
+func chainHook[H, A any](hooks []H, pick func(H) func(context.Context, A) error) func(context.Context, A) error {
+	var fns []func(context.Context, A) error
+	for _, h := range hooks {
+		if fn := pick(h); fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+	if len(fns) == 0 {
+		return nil
+	}
+	return func(ctx context.Context, a A) error {
+		for _, fn := range fns {
+			if err := fn(ctx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// chainHook2 is chainHook for two-argument callbacks.
+func chainHook2[H, A, B any](hooks []H, pick func(H) func(context.Context, A, B) error) func(context.Context, A, B) error {
+	var fns []func(context.Context, A, B) error
+	for _, h := range hooks {
+		if fn := pick(h); fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+	if len(fns) == 0 {
+		return nil
+	}
+	return func(ctx context.Context, a A, b B) error {
+		for _, fn := range fns {
+			if err := fn(ctx, a, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
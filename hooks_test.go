@@ -0,0 +1,41 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestChainRepoHooks(t *testing.T) {
+	type hooks = RepoHooks[string, int, string, string]
+	var calls []string
+	record := func(name string, err error) func(context.Context, int) error {
+		return func(context.Context, int) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	fail := errors.New("fail")
+	h := ChainRepoHooks(
+		hooks{AfterDelete: record("first", nil)},
+		hooks{},
+		hooks{AfterDelete: record("second", fail), BeforeDelete: record("before", nil)},
+		hooks{AfterDelete: record("third", nil)},
+	)
+	if err := h.AfterDelete(context.Background(), 1); !errors.Is(err, fail) {
+		t.Errorf("AfterDelete() = %v, want %v", err, fail)
+	}
+	if want := []string{"first", "second"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	if err := h.BeforeDelete(context.Background(), 1); err != nil || len(calls) != 1 {
+		t.Errorf("BeforeDelete() = %v, calls %v", err, calls)
+	}
+	if h.BeforeCreate != nil || h.AfterCreate != nil || h.BeforeUpdate != nil || h.AfterUpdate != nil {
+		t.Error("callbacks set by no hooks should stay nil")
+	}
+}
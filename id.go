@@ -0,0 +1,232 @@
+package entdomain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ID is a storage-independent entity identifier. It lets domain code pass
+// identifiers around without depending on the primary key type of the ent
+// schema; generated code converts it to the concrete key type at the edge.
+type ID interface {
+	// String returns the canonical text form of the identifier.
+	String() string
+
+	// IsZero reports whether the identifier is unset.
+	IsZero() bool
+
+	// Int64 returns the identifier as an int64, or an ErrValidation error
+	// when it has no integer form.
+	Int64() (int64, error)
+}
+
+// IDGenerator assigns identifiers to new entities. Generated services call
+// it on Create for schemas annotated with DomainConfig.WithIDGenerator.
+type IDGenerator interface {
+	NewID() (ID, error)
+}
+
+// IDGeneratorFunc adapts an ordinary function to IDGenerator.
+type IDGeneratorFunc func() (ID, error)
+
+// NewID implements IDGenerator.
+func (f IDGeneratorFunc) NewID() (ID, error) { return f() }
+
+// StringID is an ID backed by a string primary key.
+type StringID string
+
+// NewStringID returns the ID for a string primary key.
+func NewStringID(s string) StringID { return StringID(s) }
+
+// String implements ID.
+func (id StringID) String() string { return string(id) }
+
+// IsZero implements ID.
+func (id StringID) IsZero() bool { return id == "" }
+
+// Int64 implements ID by parsing the string as a base-10 integer.
+func (id StringID) Int64() (int64, error) {
+	v, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: id %q is not an integer", ErrValidation, string(id))
+	}
+	return v, nil
+}
+
+// Int64ID is an ID backed by an integer primary key.
+type Int64ID int64
+
+// NewInt64ID returns the ID for an integer primary key.
+func NewInt64ID(v int64) Int64ID { return Int64ID(v) }
+
+// String implements ID. Small non-negative IDs come from a precomputed
+// table and do not allocate.
+func (id Int64ID) String() string { return formatInt64ID(int64(id)) }
+
+// IsZero implements ID.
+func (id Int64ID) IsZero() bool { return id == 0 }
+
+// Int64 implements ID.
+func (id Int64ID) Int64() (int64, error) { return int64(id), nil }
+
+// UUIDID is an ID backed by a uuid.UUID primary key.
+type UUIDID uuid.UUID
+
+// NewIDFromUUID returns the ID for a UUID primary key.
+func NewIDFromUUID(u uuid.UUID) UUIDID { return UUIDID(u) }
+
+// UUID returns the underlying uuid.UUID.
+func (id UUIDID) UUID() uuid.UUID { return uuid.UUID(id) }
+
+// String implements ID.
+func (id UUIDID) String() string { return uuid.UUID(id).String() }
+
+// IsZero implements ID. The nil UUID is the zero value.
+func (id UUIDID) IsZero() bool { return uuid.UUID(id) == uuid.Nil }
+
+// Int64 implements ID. UUIDs have no integer form, so it always fails.
+func (id UUIDID) Int64() (int64, error) {
+	return 0, fmt.Errorf("%w: uuid id %s has no integer form", ErrValidation, id)
+}
+
+// UUIDFromID converts id to a uuid.UUID. A UUIDID converts directly; other
+// ID types are parsed from their String form.
+func UUIDFromID(id ID) (uuid.UUID, error) {
+	if u, ok := id.(UUIDID); ok {
+		return uuid.UUID(u), nil
+	}
+	u, err := uuid.Parse(id.String())
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: id %q is not a uuid", ErrValidation, id.String())
+	}
+	return u, nil
+}
+
+// idPrefixSeparator separates the prefix of a PrefixedID from its value.
+const idPrefixSeparator = "_"
+
+// validIDPrefix reports whether prefix is usable in a PrefixedID: lowercase
+// letters and digits, starting with a letter.
+func validIDPrefix(prefix string) bool {
+	if prefix == "" {
+		return false
+	}
+	for i, r := range prefix {
+		if !(r >= 'a' && r <= 'z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// PrefixedID is an ID exposed with a resource-type prefix, Stripe style:
+// "usr_01H8XGJWBWBAQ4Z4B5E3GZB2D4". The prefix makes IDs self-describing in
+// logs and URLs; ID is the unprefixed ID stored in the database.
+type PrefixedID struct {
+	Prefix string
+	ID     ID
+}
+
+// NewPrefixedID returns value exposed under prefix.
+func NewPrefixedID(prefix string, value ID) PrefixedID {
+	return PrefixedID{Prefix: prefix, ID: value}
+}
+
+// ParsePrefixedID parses s, which must carry the given prefix, into a
+// PrefixedID holding the rest as a StringID.
+func ParsePrefixedID(s, prefix string) (PrefixedID, error) {
+	value, err := StripIDPrefix(s, prefix)
+	if err != nil {
+		return PrefixedID{}, err
+	}
+	return NewPrefixedID(prefix, StringID(value)), nil
+}
+
+// StripIDPrefix returns s without its "{prefix}_" part, or an ErrValidation
+// error when s does not carry that prefix or has nothing after it.
+func StripIDPrefix(s, prefix string) (string, error) {
+	value, ok := strings.CutPrefix(s, prefix+idPrefixSeparator)
+	if !ok || value == "" {
+		return "", fmt.Errorf("%w: id %q must have the form %s%s<id>", ErrValidation, s, prefix, idPrefixSeparator)
+	}
+	return value, nil
+}
+
+// String implements ID, returning "{prefix}_{value}".
+func (id PrefixedID) String() string {
+	if id.ID == nil {
+		return ""
+	}
+	return id.Prefix + idPrefixSeparator + id.ID.String()
+}
+
+// IsZero implements ID. The prefix alone does not make an ID set.
+func (id PrefixedID) IsZero() bool { return id.ID == nil || id.ID.IsZero() }
+
+// Int64 implements ID by converting the unprefixed value.
+func (id PrefixedID) Int64() (int64, error) {
+	if id.ID == nil {
+		return 0, fmt.Errorf("%w: prefixed id has no value", ErrValidation)
+	}
+	return id.ID.Int64()
+}
+
+// compositeIDSeparator joins the parts of a CompositeID in its String form.
+const compositeIDSeparator = ":"
+
+// CompositeID is an ID for an entity keyed by several columns, such as an ent
+// edge schema whose primary key is its pair of edge fields. Parts are in key
+// column order.
+type CompositeID []ID
+
+// NewCompositeID returns the ID for a multi-column primary key.
+func NewCompositeID(parts ...ID) CompositeID { return CompositeID(parts) }
+
+// String implements ID. Parts are joined with ":", e.g. "1:42".
+func (id CompositeID) String() string {
+	parts := make([]string, len(id))
+	for i, p := range id {
+		parts[i] = p.String()
+	}
+	return strings.Join(parts, compositeIDSeparator)
+}
+
+// IsZero implements ID. A composite key is zero when it has no parts or any
+// part is zero.
+func (id CompositeID) IsZero() bool {
+	if len(id) == 0 {
+		return true
+	}
+	for _, p := range id {
+		if p == nil || p.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// Int64 implements ID. Composite keys have no integer form, so it always fails.
+func (id CompositeID) Int64() (int64, error) {
+	return 0, fmt.Errorf("%w: composite id %s has no integer form", ErrValidation, id)
+}
+
+// CompositeIDFromID converts id to a CompositeID of n parts. A CompositeID
+// converts directly; other ID types are split from their String form, so
+// "1:42" taken from a URL path becomes two StringID parts.
+func CompositeIDFromID(id ID, n int) (CompositeID, error) {
+	key, ok := id.(CompositeID)
+	if !ok {
+		raw := strings.Split(id.String(), compositeIDSeparator)
+		key = make(CompositeID, len(raw))
+		for i, r := range raw {
+			key[i] = StringID(r)
+		}
+	}
+	if len(key) != n {
+		return nil, fmt.Errorf("%w: id %q has %d key parts, want %d", ErrValidation, id.String(), len(key), n)
+	}
+	return key, nil
+}
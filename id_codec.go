@@ -0,0 +1,154 @@
+package entdomain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ParseID parses s into the most specific ID type it matches, trying in
+// order: a canonical UUID, a ULID, a base-10 int64 (without leading zeros, so
+// the text form round-trips), and a "{prefix}_{id}" PrefixedID whose value is
+// one of those. Anything else is a StringID. The empty string is an
+// ErrValidation error.
+func ParseID(s string) (ID, error) {
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty id", ErrValidation)
+	}
+	if id, ok := parseTypedID(s); ok {
+		return id, nil
+	}
+	if prefix, rest, ok := strings.Cut(s, idPrefixSeparator); ok && validIDPrefix(prefix) {
+		if value, ok := parseTypedID(rest); ok {
+			return NewPrefixedID(prefix, value), nil
+		}
+	}
+	return StringID(s), nil
+}
+
+// parseTypedID parses s as a UUID, ULID or int64 ID, the forms ParseID can
+// recognize unambiguously.
+func parseTypedID(s string) (ID, bool) {
+	switch len(s) {
+	case 36:
+		if u, err := uuid.Parse(s); err == nil {
+			return UUIDID(u), true
+		}
+	case 26:
+		if id, err := ParseULID(s); err == nil {
+			return id, true
+		}
+	}
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(v, 10) == s {
+		return Int64ID(v), true
+	}
+	return nil, false
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id StringID) MarshalText() ([]byte, error) { return []byte(id), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *StringID) UnmarshalText(text []byte) error {
+	*id = StringID(text)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler with the base-10 form.
+func (id Int64ID) MarshalText() ([]byte, error) { return id.AppendText(nil) }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *Int64ID) UnmarshalText(text []byte) error {
+	v, err := strconv.ParseInt(string(text), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: id %q is not an integer", ErrValidation, text)
+	}
+	*id = Int64ID(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the ID as a JSON number.
+func (id Int64ID) MarshalJSON() ([]byte, error) { return id.MarshalText() }
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a JSON number or a
+// string holding one, as sent by JavaScript clients that keep 64-bit IDs in
+// strings to avoid precision loss. null leaves the ID unchanged.
+func (id *Int64ID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		data = []byte(s)
+	}
+	return id.UnmarshalText(data)
+}
+
+// MarshalText implements encoding.TextMarshaler with the canonical UUID form.
+func (id UUIDID) MarshalText() ([]byte, error) { return id.AppendText(nil) }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *UUIDID) UnmarshalText(text []byte) error {
+	u, err := uuid.ParseBytes(text)
+	if err != nil {
+		return fmt.Errorf("%w: id %q is not a uuid", ErrValidation, text)
+	}
+	*id = UUIDID(u)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler with the 26-character form.
+func (id ULIDID) MarshalText() ([]byte, error) { return id.AppendText(nil) }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *ULIDID) UnmarshalText(text []byte) error {
+	v, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler with the "{prefix}_{value}" form.
+func (id PrefixedID) MarshalText() ([]byte, error) { return []byte(id.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler. The value after the
+// prefix is parsed with ParseID.
+func (id *PrefixedID) UnmarshalText(text []byte) error {
+	prefix, rest, ok := strings.Cut(string(text), idPrefixSeparator)
+	if !ok || !validIDPrefix(prefix) || rest == "" {
+		return fmt.Errorf("%w: id %q must have the form <prefix>%s<id>", ErrValidation, text, idPrefixSeparator)
+	}
+	value, err := ParseID(rest)
+	if err != nil {
+		return err
+	}
+	*id = NewPrefixedID(prefix, value)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler with the ":"-joined form.
+func (id CompositeID) MarshalText() ([]byte, error) { return []byte(id.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler. Each part is parsed
+// with ParseID.
+func (id *CompositeID) UnmarshalText(text []byte) error {
+	raw := strings.Split(string(text), compositeIDSeparator)
+	key := make(CompositeID, len(raw))
+	for i, r := range raw {
+		part, err := ParseID(r)
+		if err != nil {
+			return err
+		}
+		key[i] = part
+	}
+	*id = key
+	return nil
+}
@@ -0,0 +1,156 @@
+package entdomain
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Compile-time checks that every ID type round-trips through text.
+var (
+	_ encoding.TextMarshaler   = StringID("")
+	_ encoding.TextUnmarshaler = (*StringID)(nil)
+	_ encoding.TextUnmarshaler = (*Int64ID)(nil)
+	_ encoding.TextUnmarshaler = (*UUIDID)(nil)
+	_ encoding.TextUnmarshaler = (*ULIDID)(nil)
+	_ encoding.TextUnmarshaler = (*PrefixedID)(nil)
+	_ encoding.TextUnmarshaler = (*CompositeID)(nil)
+	_ json.Unmarshaler         = (*Int64ID)(nil)
+)
+
+func TestParseID(t *testing.T) {
+	u := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	tests := []struct {
+		in   string
+		want ID
+	}{
+		{u, NewIDFromUUID(uuid.MustParse(u))},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", mustParseULID(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV")},
+		{"42", NewInt64ID(42)},
+		{"-7", NewInt64ID(-7)},
+		{"007", NewStringID("007")},
+		{"usr_42", NewPrefixedID("usr", NewInt64ID(42))},
+		{"usr_01ARZ3NDEKTSV4RRFFQ69G5FAV", NewPrefixedID("usr", mustParseULID(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV"))},
+		{"hello_world", NewStringID("hello_world")},
+		{"Usr_42", NewStringID("Usr_42")},
+		{"alice", NewStringID("alice")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseID(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseID(%q) = %#v, want %#v", tt.in, got, tt.want)
+			}
+			if got.String() != tt.in {
+				t.Errorf("String() = %q, want the input back", got.String())
+			}
+		})
+	}
+
+	if _, err := ParseID(""); !errors.Is(err, ErrValidation) {
+		t.Errorf("ParseID(\"\") error = %v, want ErrValidation", err)
+	}
+}
+
+func mustParseULID(t *testing.T, s string) ULIDID {
+	t.Helper()
+	id, err := ParseULID(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestIDs_JSON(t *testing.T) {
+	type dto struct {
+		S StringID    `json:"s"`
+		I Int64ID     `json:"i"`
+		U UUIDID      `json:"u"`
+		L ULIDID      `json:"l"`
+		P PrefixedID  `json:"p"`
+		C CompositeID `json:"c"`
+	}
+	in := dto{
+		S: "alice",
+		I: 9007199254740993,
+		U: NewIDFromUUID(uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")),
+		L: mustParseULID(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV"),
+		P: NewPrefixedID("usr", NewInt64ID(42)),
+		C: NewCompositeID(NewInt64ID(1), NewInt64ID(2)),
+	}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"s":"alice","i":9007199254740993,"u":"6ba7b810-9dad-11d1-80b4-00c04fd430c8",` +
+		`"l":"01ARZ3NDEKTSV4RRFFQ69G5FAV","p":"usr_42","c":"1:2"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	var out dto
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.S != in.S || out.I != in.I || out.U != in.U || out.L != in.L ||
+		out.P != in.P || out.C.String() != in.C.String() {
+		t.Errorf("json round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestInt64ID_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Int64ID
+		wantErr bool
+	}{
+		{`42`, 42, false},
+		{`"9007199254740993"`, 9007199254740993, false},
+		{`null`, 0, false},
+		{`"abc"`, 0, true},
+		{`4.2`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var got Int64ID
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("error = %v, want ErrValidation", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Unmarshal(%s) = %d, %v, want %d", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDs_UnmarshalTextInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   encoding.TextUnmarshaler
+		text string
+	}{
+		{"uuid", new(UUIDID), "not-a-uuid"},
+		{"ulid", new(ULIDID), "short"},
+		{"prefixed without prefix", new(PrefixedID), "42"},
+		{"prefixed with empty value", new(PrefixedID), "usr_"},
+		{"composite with empty part", new(CompositeID), "1:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.id.UnmarshalText([]byte(tt.text)); !errors.Is(err, ErrValidation) {
+				t.Errorf("UnmarshalText(%q) error = %v, want ErrValidation", tt.text, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package entdomain
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// smallInt64IDs is the number of non-negative integer IDs whose String form
+// is precomputed. Young tables and test fixtures mostly hold IDs this small.
+const smallInt64IDs = 1024
+
+// smallInt64IDStrings holds the String forms of 0..smallInt64IDs-1, sliced
+// out of one backing string.
+var smallInt64IDStrings = func() []string {
+	var b strings.Builder
+	ends := make([]int, smallInt64IDs)
+	for i := range ends {
+		b.WriteString(strconv.Itoa(i))
+		ends[i] = b.Len()
+	}
+	all := b.String()
+	s := make([]string, smallInt64IDs)
+	start := 0
+	for i, end := range ends {
+		s[i] = all[start:end]
+		start = end
+	}
+	return s
+}()
+
+// formatInt64ID returns the base-10 form of v without allocating for
+// small non-negative values.
+func formatInt64ID(v int64) string {
+	if v >= 0 && v < smallInt64IDs {
+		return smallInt64IDStrings[v]
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// AppendText appends the String form of the ID to dst. It implements the
+// encoding.TextAppender interface of Go 1.24.
+func (id StringID) AppendText(dst []byte) ([]byte, error) { return append(dst, id...), nil }
+
+// AppendText appends the base-10 form of the ID to dst.
+func (id Int64ID) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendInt(dst, int64(id), 10), nil
+}
+
+// AppendText appends the canonical UUID form of the ID to dst.
+func (id UUIDID) AppendText(dst []byte) ([]byte, error) {
+	var buf [36]byte
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+	return append(dst, buf[:]...), nil
+}
+
+// AppendText appends the 26-character form of the ID to dst.
+func (id ULIDID) AppendText(dst []byte) ([]byte, error) {
+	var buf [26]byte
+	id.encode(&buf)
+	return append(dst, buf[:]...), nil
+}
+
+// AppendText appends the "{prefix}_{value}" form of the ID to dst.
+func (id PrefixedID) AppendText(dst []byte) ([]byte, error) {
+	if id.ID == nil {
+		return dst, nil
+	}
+	dst = append(dst, id.Prefix...)
+	dst = append(dst, idPrefixSeparator...)
+	return AppendID(dst, id.ID), nil
+}
+
+// AppendText appends the ":"-joined form of the ID to dst.
+func (id CompositeID) AppendText(dst []byte) ([]byte, error) {
+	for i, p := range id {
+		if i > 0 {
+			dst = append(dst, compositeIDSeparator...)
+		}
+		dst = AppendID(dst, p)
+	}
+	return dst, nil
+}
+
+// textAppender is encoding.TextAppender, which needs Go 1.24.
+type textAppender interface {
+	AppendText(dst []byte) ([]byte, error)
+}
+
+// AppendID appends the String form of id to dst. The ID types of this
+// package append without allocating once dst has room, so hot loops can
+// format many IDs into one reused buffer.
+func AppendID(dst []byte, id ID) []byte {
+	if a, ok := id.(textAppender); ok {
+		if out, err := a.AppendText(dst); err == nil {
+			return out
+		}
+	}
+	return append(dst, id.String()...)
+}
+
+// IDStrings returns the String forms of ids. They share one backing string,
+// so formatting a page of IDs costs a fixed number of allocations instead of
+// one per ID. Every ID type of this package qualifies.
+func IDStrings[T interface {
+	ID
+	textAppender
+}](ids []T) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	buf := make([]byte, 0, len(ids)*20)
+	ends := make([]int, len(ids))
+	for i, id := range ids {
+		buf, _ = id.AppendText(buf)
+		ends[i] = len(buf)
+	}
+	all := string(buf)
+	out := make([]string, len(ids))
+	start := 0
+	for i, end := range ends {
+		out[i] = all[start:end]
+		start = end
+	}
+	return out
+}
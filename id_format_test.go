@@ -0,0 +1,124 @@
+package entdomain
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestInt64ID_StringCache(t *testing.T) {
+	for _, v := range []int64{0, 7, 99, 100, smallInt64IDs - 1, smallInt64IDs, -1, 1 << 40} {
+		if got, want := NewInt64ID(v).String(), strconv.FormatInt(v, 10); got != want {
+			t.Errorf("Int64ID(%d).String() = %q, want %q", v, got, want)
+		}
+	}
+	if n := testing.AllocsPerRun(100, func() { _ = NewInt64ID(smallInt64IDs - 1).String() }); n != 0 {
+		t.Errorf("cached String() allocates %v times, want 0", n)
+	}
+}
+
+func TestAppendID(t *testing.T) {
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	ulid := mustParseULID(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	tests := []struct {
+		name string
+		id   ID
+	}{
+		{"StringID", NewStringID("alice")},
+		{"Int64ID", NewInt64ID(-123456789)},
+		{"UUIDID", NewIDFromUUID(u)},
+		{"ULIDID", ulid},
+		{"PrefixedID", NewPrefixedID("usr", ulid)},
+		{"PrefixedID without value", PrefixedID{Prefix: "usr"}},
+		{"CompositeID", NewCompositeID(NewInt64ID(1), NewIDFromUUID(u))},
+		{"TypedID", NewTypedID(42)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(AppendID([]byte("x="), tt.id)); got != "x="+tt.id.String() {
+				t.Errorf("AppendID() = %q, want %q", got, "x="+tt.id.String())
+			}
+		})
+	}
+}
+
+func TestAppendID_NoAllocs(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	ids := []ID{NewInt64ID(1 << 40), NewIDFromUUID(uuid.New()), mustParseULID(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV")}
+	for _, id := range ids {
+		if n := testing.AllocsPerRun(100, func() { buf = AppendID(buf[:0], id) }); n != 0 {
+			t.Errorf("AppendID(%T) allocates %v times, want 0", id, n)
+		}
+	}
+}
+
+func TestIDStrings(t *testing.T) {
+	if got := IDStrings([]Int64ID(nil)); got != nil {
+		t.Errorf("IDStrings(nil) = %v, want nil", got)
+	}
+	ids := []Int64ID{1, 22, 1 << 40}
+	got := IDStrings(ids)
+	if len(got) != len(ids) {
+		t.Fatalf("IDStrings() = %v", got)
+	}
+	for i, id := range ids {
+		if got[i] != id.String() {
+			t.Errorf("IDStrings()[%d] = %q, want %q", i, got[i], id.String())
+		}
+	}
+}
+
+func BenchmarkInt64ID_String(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = NewInt64ID(int64(i % smallInt64IDs)).String()
+		}
+	})
+	b.Run("large", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = NewInt64ID(1<<40 + int64(i)).String()
+		}
+	})
+}
+
+func BenchmarkAppendID(b *testing.B) {
+	ids := map[string]ID{
+		"Int64ID": NewInt64ID(1 << 40),
+		"UUIDID":  NewIDFromUUID(uuid.New()),
+		"ULIDID":  ULIDID(uuid.New()),
+	}
+	for name, id := range ids {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			buf := make([]byte, 0, 64)
+			for i := 0; i < b.N; i++ {
+				buf = AppendID(buf[:0], id)
+			}
+		})
+	}
+}
+
+func BenchmarkIDStrings(b *testing.B) {
+	ids := make([]Int64ID, 100)
+	for i := range ids {
+		ids[i] = Int64ID(1<<40 + i)
+	}
+	b.Run("IDStrings", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = IDStrings(ids)
+		}
+	})
+	b.Run("String", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			out := make([]string, len(ids))
+			for j, id := range ids {
+				out[j] = id.String()
+			}
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package entdomain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// The ID types implement driver.Valuer and sql.Scanner so they can be passed
+// to raw SQL, sql.Selector modifiers and Scan calls without converting to the
+// column type by hand. CompositeID spans several columns and implements
+// neither; use its parts.
+
+// Value implements driver.Valuer.
+func (id StringID) Value() (driver.Value, error) { return string(id), nil }
+
+// Scan implements sql.Scanner. Integer columns are formatted in base 10;
+// NULL scans as the empty ID.
+func (id *StringID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ""
+	case string:
+		*id = StringID(v)
+	case []byte:
+		*id = StringID(v)
+	case int64:
+		*id = StringID(strconv.FormatInt(v, 10))
+	default:
+		return fmt.Errorf("%w: cannot scan %T into StringID", ErrValidation, src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (id Int64ID) Value() (driver.Value, error) { return int64(id), nil }
+
+// Scan implements sql.Scanner. Text columns holding an integer are parsed;
+// NULL scans as the zero ID.
+func (id *Int64ID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = 0
+	case int64:
+		*id = Int64ID(v)
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		return id.UnmarshalText(v)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into Int64ID", ErrValidation, src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer with the canonical text form, like uuid.UUID.
+func (id UUIDID) Value() (driver.Value, error) { return id.String(), nil }
+
+// Scan implements sql.Scanner, accepting the text and 16-byte binary forms.
+func (id *UUIDID) Scan(src any) error {
+	var u uuid.UUID
+	if err := u.Scan(src); err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	*id = UUIDID(u)
+	return nil
+}
+
+// Value implements driver.Valuer with the 26-character text form.
+func (id ULIDID) Value() (driver.Value, error) { return id.String(), nil }
+
+// Scan implements sql.Scanner, accepting the text and 16-byte binary forms.
+// NULL scans as the zero ID.
+func (id *ULIDID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ULIDID{}
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(id) {
+			copy(id[:], v)
+			return nil
+		}
+		return id.UnmarshalText(v)
+	}
+	return fmt.Errorf("%w: cannot scan %T into ULIDID", ErrValidation, src)
+}
+
+// Value implements driver.Valuer with the unprefixed value: the prefix is a
+// presentation detail and is not stored.
+func (id PrefixedID) Value() (driver.Value, error) {
+	switch v := id.ID.(type) {
+	case nil:
+		return nil, nil
+	case driver.Valuer:
+		return v.Value()
+	default:
+		return v.String(), nil
+	}
+}
+
+// Scan implements sql.Scanner. The column holds the unprefixed value, parsed
+// with ParseID; Prefix is kept, so set it before scanning.
+func (id *PrefixedID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		id.ID = nil
+		return nil
+	case int64:
+		id.ID = Int64ID(v)
+		return nil
+	case string:
+		return id.scanText(v)
+	case []byte:
+		return id.scanText(string(v))
+	}
+	return fmt.Errorf("%w: cannot scan %T into PrefixedID", ErrValidation, src)
+}
+
+// scanText sets the value of a scanned PrefixedID from its text form.
+func (id *PrefixedID) scanText(s string) error {
+	value, err := ParseID(s)
+	if err != nil {
+		return err
+	}
+	id.ID = value
+	return nil
+}
@@ -0,0 +1,112 @@
+package entdomain
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Compile-time checks that the single-column ID types work with database/sql.
+var (
+	_ driver.Valuer = StringID("")
+	_ driver.Valuer = Int64ID(0)
+	_ driver.Valuer = UUIDID{}
+	_ driver.Valuer = ULIDID{}
+	_ driver.Valuer = PrefixedID{}
+	_ sql.Scanner   = (*StringID)(nil)
+	_ sql.Scanner   = (*Int64ID)(nil)
+	_ sql.Scanner   = (*UUIDID)(nil)
+	_ sql.Scanner   = (*ULIDID)(nil)
+	_ sql.Scanner   = (*PrefixedID)(nil)
+)
+
+func TestIDs_Value(t *testing.T) {
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	ulid := mustParseULID(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	tests := []struct {
+		name string
+		id   driver.Valuer
+		want driver.Value
+	}{
+		{"StringID", NewStringID("alice"), "alice"},
+		{"Int64ID", NewInt64ID(42), int64(42)},
+		{"UUIDID", NewIDFromUUID(u), u.String()},
+		{"ULIDID", ulid, "01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{"PrefixedID stores the unprefixed value", NewPrefixedID("usr", NewInt64ID(42)), int64(42)},
+		{"PrefixedID without value", PrefixedID{Prefix: "usr"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.id.Value()
+			if err != nil || got != tt.want {
+				t.Errorf("Value() = %#v, %v, want %#v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDs_Scan(t *testing.T) {
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	ulid := mustParseULID(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+
+	var s StringID
+	for _, src := range []any{"42", []byte("42"), int64(42)} {
+		if err := s.Scan(src); err != nil || s != "42" {
+			t.Errorf("StringID.Scan(%#v) = %q, %v", src, s, err)
+		}
+	}
+
+	var i Int64ID
+	for _, src := range []any{int64(42), "42", []byte("42")} {
+		if err := i.Scan(src); err != nil || i != 42 {
+			t.Errorf("Int64ID.Scan(%#v) = %d, %v", src, i, err)
+		}
+	}
+	if err := i.Scan(nil); err != nil || i != 0 {
+		t.Errorf("Int64ID.Scan(nil) = %d, %v, want 0", i, err)
+	}
+
+	var uid UUIDID
+	for _, src := range []any{u.String(), u[:]} {
+		if err := uid.Scan(src); err != nil || uid.UUID() != u {
+			t.Errorf("UUIDID.Scan(%#v) = %v, %v", src, uid, err)
+		}
+	}
+
+	var l ULIDID
+	for _, src := range []any{ulid.String(), []byte(ulid.String()), ulid[:]} {
+		if err := l.Scan(src); err != nil || l != ulid {
+			t.Errorf("ULIDID.Scan(%#v) = %v, %v", src, l, err)
+		}
+	}
+
+	p := PrefixedID{Prefix: "usr"}
+	if err := p.Scan(int64(42)); err != nil || p.String() != "usr_42" {
+		t.Errorf("PrefixedID.Scan(42) = %v, %v", p, err)
+	}
+	if err := p.Scan(ulid.String()); err != nil || p.ID != ulid {
+		t.Errorf("PrefixedID.Scan(ulid) = %#v, %v", p, err)
+	}
+
+	invalid := []struct {
+		name string
+		id   sql.Scanner
+		src  any
+	}{
+		{"StringID from float", new(StringID), 4.2},
+		{"Int64ID from text", new(Int64ID), "abc"},
+		{"UUIDID from text", new(UUIDID), "not-a-uuid"},
+		{"ULIDID from int", new(ULIDID), int64(1)},
+		{"PrefixedID from bool", new(PrefixedID), true},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.id.Scan(tt.src); !errors.Is(err, ErrValidation) {
+				t.Errorf("Scan(%#v) error = %v, want ErrValidation", tt.src, err)
+			}
+		})
+	}
+}
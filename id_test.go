@@ -0,0 +1,171 @@
+package entdomain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Compile-time checks that every ID type implements ID.
+var (
+	_ ID = StringID("")
+	_ ID = Int64ID(0)
+	_ ID = UUIDID{}
+	_ ID = CompositeID{}
+	_ ID = PrefixedID{}
+)
+
+func TestStringID(t *testing.T) {
+	if !NewStringID("").IsZero() || NewStringID("a").IsZero() {
+		t.Error("IsZero() should be true only for the empty string")
+	}
+	if got := NewStringID("42").String(); got != "42" {
+		t.Errorf("String() = %q, want %q", got, "42")
+	}
+	if v, err := NewStringID("42").Int64(); err != nil || v != 42 {
+		t.Errorf("Int64() = %d, %v, want 42", v, err)
+	}
+	if _, err := NewStringID("abc").Int64(); !errors.Is(err, ErrValidation) {
+		t.Errorf("Int64() error = %v, want ErrValidation", err)
+	}
+}
+
+func TestInt64ID(t *testing.T) {
+	if !NewInt64ID(0).IsZero() || NewInt64ID(7).IsZero() {
+		t.Error("IsZero() should be true only for 0")
+	}
+	if got := NewInt64ID(-7).String(); got != "-7" {
+		t.Errorf("String() = %q, want %q", got, "-7")
+	}
+	if v, err := NewInt64ID(7).Int64(); err != nil || v != 7 {
+		t.Errorf("Int64() = %d, %v, want 7", v, err)
+	}
+}
+
+func TestUUIDID(t *testing.T) {
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	id := NewIDFromUUID(u)
+
+	if id.String() != u.String() {
+		t.Errorf("String() = %q, want %q", id.String(), u.String())
+	}
+	if id.UUID() != u {
+		t.Errorf("UUID() = %v, want %v", id.UUID(), u)
+	}
+	if id.IsZero() || !NewIDFromUUID(uuid.Nil).IsZero() {
+		t.Error("IsZero() should be true only for the nil UUID")
+	}
+	if _, err := id.Int64(); !errors.Is(err, ErrValidation) {
+		t.Errorf("Int64() error = %v, want ErrValidation", err)
+	}
+}
+
+func TestUUIDFromID(t *testing.T) {
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	tests := []struct {
+		name    string
+		id      ID
+		wantErr bool
+	}{
+		{"UUIDID", NewIDFromUUID(u), false},
+		{"StringID holding a uuid", NewStringID(u.String()), false},
+		{"StringID holding garbage", NewStringID("not-a-uuid"), true},
+		{"Int64ID", NewInt64ID(1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UUIDFromID(tt.id)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("UUIDFromID() error = %v, want ErrValidation", err)
+				}
+				return
+			}
+			if err != nil || got != u {
+				t.Errorf("UUIDFromID() = %v, %v, want %v", got, err, u)
+			}
+		})
+	}
+}
+
+func TestCompositeID(t *testing.T) {
+	id := NewCompositeID(NewInt64ID(1), NewStringID("42"))
+
+	if got := id.String(); got != "1:42" {
+		t.Errorf("String() = %q, want %q", got, "1:42")
+	}
+	if id.IsZero() {
+		t.Error("IsZero() = true for a fully set key")
+	}
+	if !NewCompositeID().IsZero() || !NewCompositeID(NewInt64ID(1), NewInt64ID(0)).IsZero() {
+		t.Error("IsZero() should be true for an empty key or a zero part")
+	}
+	if _, err := id.Int64(); !errors.Is(err, ErrValidation) {
+		t.Errorf("Int64() error = %v, want ErrValidation", err)
+	}
+}
+
+func TestCompositeIDFromID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      ID
+		want    string
+		wantErr bool
+	}{
+		{"CompositeID", NewCompositeID(NewInt64ID(1), NewInt64ID(2)), "1:2", false},
+		{"StringID from a path", NewStringID("1:2"), "1:2", false},
+		{"too few parts", NewStringID("1"), "", true},
+		{"too many parts", NewCompositeID(NewInt64ID(1), NewInt64ID(2), NewInt64ID(3)), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompositeIDFromID(tt.id, 2)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("CompositeIDFromID() error = %v, want ErrValidation", err)
+				}
+				return
+			}
+			if err != nil || got.String() != tt.want {
+				t.Errorf("CompositeIDFromID() = %v, %v, want %s", got, err, tt.want)
+			}
+			if v, err := got[1].Int64(); err != nil || v != 2 {
+				t.Errorf("part 1 Int64() = %d, %v, want 2", v, err)
+			}
+		})
+	}
+}
+
+func TestPrefixedID(t *testing.T) {
+	id := NewPrefixedID("usr", NewInt64ID(42))
+	if got := id.String(); got != "usr_42" {
+		t.Errorf("String() = %q, want %q", got, "usr_42")
+	}
+	if v, err := id.Int64(); err != nil || v != 42 {
+		t.Errorf("Int64() = %d, %v, want 42", v, err)
+	}
+	if id.IsZero() || !NewPrefixedID("usr", nil).IsZero() || !NewPrefixedID("usr", NewInt64ID(0)).IsZero() {
+		t.Error("IsZero() should follow the value")
+	}
+	if got := NewPrefixedID("usr", nil).String(); got != "" {
+		t.Errorf("String() without value = %q, want empty", got)
+	}
+}
+
+func TestParsePrefixedID(t *testing.T) {
+	id, err := ParsePrefixedID("usr_01H8XGJWBWBAQ4Z4B5E3GZB2D4", "usr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Prefix != "usr" || id.ID != StringID("01H8XGJWBWBAQ4Z4B5E3GZB2D4") {
+		t.Errorf("ParsePrefixedID() = %+v", id)
+	}
+
+	for _, s := range []string{"01H8XGJWBWBAQ4Z4B5E3GZB2D4", "org_01H8", "usr_", "usr01H8"} {
+		if _, err := ParsePrefixedID(s, "usr"); !errors.Is(err, ErrValidation) {
+			t.Errorf("ParsePrefixedID(%q) error = %v, want ErrValidation", s, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package entdomain
+
+import (
+	"context"
+	"fmt"
+)
+
+// IDValidator checks an ID before a generated service queries with it, for
+// example its UUID version or a tenant-specific format. Return an ErrValidation
+// error to reject the ID; the query is then never run.
+type IDValidator interface {
+	ValidateID(ctx context.Context, resource string, id ID) error
+}
+
+// IDValidatorFunc adapts an ordinary function to IDValidator.
+type IDValidatorFunc func(ctx context.Context, resource string, id ID) error
+
+// ValidateID implements IDValidator.
+func (f IDValidatorFunc) ValidateID(ctx context.Context, resource string, id ID) error {
+	return f(ctx, resource, id)
+}
+
+// ValidateID rejects nil and zero IDs with an ErrValidation error, then runs
+// v when it is not nil. Generated services built with WithIDValidation call it
+// at the start of every ID-based operation.
+func ValidateID(ctx context.Context, v IDValidator, resource string, id ID) error {
+	if id == nil || id.IsZero() {
+		return fmt.Errorf("%w: %s id is required", ErrValidation, resource)
+	}
+	if v == nil {
+		return nil
+	}
+	return v.ValidateID(ctx, resource, id)
+}
+
+// ChainIDValidators returns an IDValidator that runs validators in order and
+// stops at the first error.
+func ChainIDValidators(validators ...IDValidator) IDValidator {
+	return IDValidatorFunc(func(ctx context.Context, resource string, id ID) error {
+		for _, v := range validators {
+			if err := v.ValidateID(ctx, resource, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UUIDVersionValidator accepts only UUIDs of the given version, e.g. 4 for
+// random or 7 for time-ordered UUIDs.
+func UUIDVersionValidator(version int) IDValidator {
+	return IDValidatorFunc(func(_ context.Context, resource string, id ID) error {
+		u, err := UUIDFromID(id)
+		if err != nil {
+			return err
+		}
+		if got := int(u.Version()); got != version {
+			return fmt.Errorf("%w: %s id %s is a version %d uuid, want version %d", ErrValidation, resource, u, got, version)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,69 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateID(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	counting := IDValidatorFunc(func(context.Context, string, ID) error {
+		calls++
+		return nil
+	})
+
+	for _, id := range []ID{nil, NewInt64ID(0), NewStringID(""), NewIDFromUUID(uuid.Nil)} {
+		if err := ValidateID(ctx, counting, "user", id); !errors.Is(err, ErrValidation) {
+			t.Errorf("ValidateID(%v) err = %v, want ErrValidation", id, err)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("validator called %d times for zero IDs, want 0", calls)
+	}
+	if err := ValidateID(ctx, nil, "user", NewInt64ID(1)); err != nil {
+		t.Errorf("ValidateID without validator = %v", err)
+	}
+	if err := ValidateID(ctx, counting, "user", NewInt64ID(1)); err != nil || calls != 1 {
+		t.Errorf("ValidateID = %v after %d calls, want nil after 1", err, calls)
+	}
+}
+
+func TestUUIDVersionValidator(t *testing.T) {
+	ctx := context.Background()
+	v4 := NewIDFromUUID(uuid.New())
+	v7 := NewIDFromUUID(uuid.MustParse("01890a5d-ac96-774b-bcce-b302099a8057"))
+
+	v := UUIDVersionValidator(7)
+	if err := v.ValidateID(ctx, "user", v7); err != nil {
+		t.Errorf("v7 uuid rejected: %v", err)
+	}
+	if err := v.ValidateID(ctx, "user", v4); !errors.Is(err, ErrValidation) {
+		t.Errorf("v4 uuid err = %v, want ErrValidation", err)
+	}
+	if err := v.ValidateID(ctx, "user", NewStringID("nope")); !errors.Is(err, ErrValidation) {
+		t.Errorf("non-uuid err = %v, want ErrValidation", err)
+	}
+}
+
+func TestChainIDValidators(t *testing.T) {
+	ctx := context.Background()
+	reject := errors.New("rejected")
+	var order []int
+	step := func(n int, err error) IDValidator {
+		return IDValidatorFunc(func(context.Context, string, ID) error {
+			order = append(order, n)
+			return err
+		})
+	}
+	v := ChainIDValidators(step(1, nil), step(2, reject), step(3, nil))
+	if err := v.ValidateID(ctx, "user", NewInt64ID(1)); !errors.Is(err, reject) {
+		t.Errorf("ValidateID err = %v, want %v", err, reject)
+	}
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("ran validators %v, want [1 2]", order)
+	}
+}
@@ -0,0 +1,197 @@
+package entdomain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultIdempotencyTTL is how long generated services keep idempotency keys
+// when their IdempotencyTTL is zero.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying the idempotency key of
+// the request, e.g. its Idempotency-Key header. Generated services with an
+// IdempotencyStore run Create once per key and replay its result on retries.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// IdempotencyKeyFrom returns the key stored by WithIdempotencyKey, or "" when
+// none is.
+func IdempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+// IdempotencyRecord is what an IdempotencyStore keeps of a request run with
+// an idempotency key.
+type IdempotencyRecord struct {
+	// RequestHash identifies the request, see HashRequest.
+	RequestHash string `json:"request_hash"`
+	// Response is the JSON of the result, nil while the request runs.
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// IdempotencyStore keeps the requests run with idempotency keys, e.g. in
+// Redis or a database table. The keys it is given are scoped by resource,
+// tenant and actor, so callers cannot replay each other's requests.
+type IdempotencyStore interface {
+	// Begin records a running request with requestHash under key, for ttl,
+	// and returns nil. When key is already recorded, it returns that record
+	// instead. It must be atomic, e.g. SET NX, so that of concurrent retries
+	// only one begins.
+	Begin(ctx context.Context, key, requestHash string, ttl time.Duration) (*IdempotencyRecord, error)
+	// Complete stores the response of the request begun under key.
+	Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error
+	// Release forgets the request begun under key, which failed, so that a
+	// retry runs it again.
+	Release(ctx context.Context, key string) error
+}
+
+// HashRequest returns the hex SHA-256 of the JSON of req, with which
+// Idempotent tells retries from other requests reusing a key.
+func HashRequest(req any) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Idempotent runs fn, a write of resource such as a generated Create, once per
+// idempotency key of ctx. The first call with a key records its result in
+// store for ttl (DefaultIdempotencyTTL when zero); retries with the same key
+// and req return the recorded result decoded from JSON, without calling fn.
+// Reusing a key for another req is rejected with ErrValidation, and retrying
+// while the first call runs with ErrConflict. Failed calls are not recorded.
+// Without a store or a key, and on dry runs, fn is simply called.
+//
+// Results are recorded when fn returns, even if it ran in a transaction of
+// ctx that is rolled back later. Record values that survive JSON, such as the
+// ID of a created entity to read it again: ent entities decoded from JSON have
+// no client to load edges with. Authorize the caller before calling
+// Idempotent, since replays do not call fn.
+func Idempotent[T any](ctx context.Context, store IdempotencyStore, resource string, req any, ttl time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	key := IdempotencyKeyFrom(ctx)
+	if key != "" {
+		// fn runs without the key, so that it may call the write wrapping it.
+		ctx = WithIdempotencyKey(ctx, "")
+	}
+	if store == nil || key == "" || IsDryRun(ctx) {
+		return fn(ctx)
+	}
+	hash, err := HashRequest(req)
+	if err != nil {
+		return zero, fmt.Errorf("hashing %s request: %w", resource, err)
+	}
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	scoped := resource + ":" + TenantFrom(ctx) + ":" + ActorFrom(ctx) + ":" + key
+
+	record, err := store.Begin(ctx, scoped, hash, ttl)
+	if err != nil {
+		return zero, err
+	}
+	if record != nil {
+		switch {
+		case record.RequestHash != hash:
+			return zero, fmt.Errorf("%w: idempotency key %q was used for another request", ErrValidation, key)
+		case record.Response == nil:
+			return zero, fmt.Errorf("%w: the request with idempotency key %q is still running", ErrConflict, key)
+		}
+		var result T
+		if err := json.Unmarshal(record.Response, &result); err != nil {
+			return zero, fmt.Errorf("replaying idempotency key %q: %w", key, err)
+		}
+		return result, nil
+	}
+
+	result, err := fn(ctx)
+	if err == nil {
+		var response []byte
+		if response, err = json.Marshal(result); err == nil {
+			if err := store.Complete(ctx, scoped, response, ttl); err != nil {
+				return zero, err
+			}
+			return result, nil
+		}
+	}
+	if releaseErr := store.Release(ctx, scoped); releaseErr != nil {
+		err = errors.Join(err, releaseErr)
+	}
+	return zero, err
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore, for tests and
+// single-instance deployments. The zero value is ready to use. Expired
+// records are swept by Begin at most once a minute.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]memoryIdempotencyRecord
+	swept   time.Time
+}
+
+type memoryIdempotencyRecord struct {
+	record  IdempotencyRecord
+	expires time.Time
+}
+
+// Begin implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Begin(_ context.Context, key, requestHash string, ttl time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.swept) > time.Minute {
+		for k, r := range s.records {
+			if !now.Before(r.expires) {
+				delete(s.records, k)
+			}
+		}
+		s.swept = now
+	}
+	if r, ok := s.records[key]; ok && now.Before(r.expires) {
+		record := r.record
+		return &record, nil
+	}
+	if s.records == nil {
+		s.records = make(map[string]memoryIdempotencyRecord)
+	}
+	s.records[key] = memoryIdempotencyRecord{
+		record:  IdempotencyRecord{RequestHash: requestHash},
+		expires: now.Add(ttl),
+	}
+	return nil, nil
+}
+
+// Complete implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, response []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[key]
+	if !ok {
+		return fmt.Errorf("idempotency key %q was not begun", key)
+	}
+	r.record.Response = response
+	r.expires = time.Now().Add(ttl)
+	s.records[key] = r
+	return nil
+}
+
+// Release implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
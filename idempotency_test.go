@@ -0,0 +1,124 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type idempotentResult struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestIdempotent(t *testing.T) {
+	store := &MemoryIdempotencyStore{}
+	var calls atomic.Int32
+	create := func(name string) func(ctx context.Context) (*idempotentResult, error) {
+		return func(ctx context.Context) (*idempotentResult, error) {
+			if key := IdempotencyKeyFrom(ctx); key != "" {
+				t.Errorf("fn ran with idempotency key %q", key)
+			}
+			return &idempotentResult{ID: int(calls.Add(1)), Name: name}, nil
+		}
+	}
+	ctx := WithIdempotencyKey(context.Background(), "k1")
+	req := map[string]string{"name": "a"}
+
+	first, err := Idempotent(ctx, store, "user", req, 0, create("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay, err := Idempotent(ctx, store, "user", req, 0, create("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *replay != *first || calls.Load() != 1 {
+		t.Errorf("replay = %+v after %d calls, want %+v after 1", replay, calls.Load(), first)
+	}
+
+	_, err = Idempotent(ctx, store, "user", map[string]string{"name": "b"}, 0, create("b"))
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("reused key: err = %v, want ErrValidation", err)
+	}
+
+	for name, ctx := range map[string]context.Context{
+		"other resource": ctx,
+		"other tenant":   WithTenant(ctx, "acme"),
+		"other actor":    WithActor(ctx, "u1"),
+		"no key":         context.Background(),
+		"dry run":        WithDryRun(ctx),
+	} {
+		resource := "user"
+		if name == "other resource" {
+			resource = "group"
+		}
+		before := calls.Load()
+		if _, err := Idempotent(ctx, store, resource, req, 0, create("a")); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if calls.Load() != before+1 {
+			t.Errorf("%s: fn was not called", name)
+		}
+	}
+
+	if _, err := Idempotent[*idempotentResult](ctx, nil, "user", req, 0, create("a")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIdempotentInProgress(t *testing.T) {
+	store := &MemoryIdempotencyStore{}
+	ctx := WithIdempotencyKey(context.Background(), "k1")
+	_, err := Idempotent(ctx, store, "user", "req", 0, func(ctx context.Context) (int, error) {
+		_, err := Idempotent(WithIdempotencyKey(ctx, "k1"), store, "user", "req", 0, func(context.Context) (int, error) {
+			t.Error("retry ran while the first call was running")
+			return 0, nil
+		})
+		return 0, err
+	})
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("err = %v, want ErrConflict", err)
+	}
+}
+
+func TestIdempotentReleasesFailures(t *testing.T) {
+	store := &MemoryIdempotencyStore{}
+	ctx := WithIdempotencyKey(context.Background(), "k1")
+	errBoom := errors.New("boom")
+	if _, err := Idempotent(ctx, store, "user", "req", 0, func(context.Context) (int, error) {
+		return 0, errBoom
+	}); !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	got, err := Idempotent(ctx, store, "user", "req", 0, func(context.Context) (int, error) {
+		return 7, nil
+	})
+	if err != nil || got != 7 {
+		t.Errorf("retry after failure = %d, %v, want 7", got, err)
+	}
+}
+
+func TestMemoryIdempotencyStoreExpiry(t *testing.T) {
+	store := &MemoryIdempotencyStore{}
+	ctx := context.Background()
+	if r, err := store.Begin(ctx, "k", "h", time.Nanosecond); r != nil || err != nil {
+		t.Fatalf("Begin() = %v, %v", r, err)
+	}
+	time.Sleep(time.Millisecond)
+	if r, err := store.Begin(ctx, "k", "h2", time.Hour); r != nil || err != nil {
+		t.Errorf("Begin() of an expired key = %v, %v, want nil", r, err)
+	}
+	if err := store.Complete(ctx, "k", []byte(`1`), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	r, err := store.Begin(ctx, "k", "h3", time.Hour)
+	if err != nil || r == nil || r.RequestHash != "h2" || string(r.Response) != "1" {
+		t.Errorf("Begin() = %+v, %v, want the completed h2 record", r, err)
+	}
+	if err := store.Complete(ctx, "missing", nil, time.Hour); err == nil {
+		t.Error("Complete() of a key never begun should fail")
+	}
+}
@@ -0,0 +1,344 @@
+package entdomain
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ImportFormat is the encoding of the input of generated {Entity}Importers.
+type ImportFormat string
+
+const (
+	// ImportCSV is comma-separated values whose first row names the columns.
+	ImportCSV ImportFormat = "csv"
+	// ImportJSONL is JSON Lines: one create request per line, as a JSON
+	// object keyed like the request's json tags.
+	ImportJSONL ImportFormat = "jsonl"
+)
+
+// DefaultImportBatchSize is the number of rows generated importers insert per
+// statement when their BatchSize is zero.
+const DefaultImportBatchSize = 100
+
+// ImportColumn is a create request field a CSV column can fill. The header of
+// the column names either its Field, the column name, or its Title, from
+// DomainField.WithTitle, ignoring case.
+type ImportColumn struct {
+	Field string
+	Title string
+}
+
+// ImportRowError is a row Import did not create. Rows failing validation have
+// one per violated field.
+type ImportRowError struct {
+	// Line is the line of the input the row starts on; the CSV header is
+	// line 1.
+	Line int `json:"line"`
+	// Field is the field at fault, when known.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	// Err is the error of the row.
+	Err error `json:"-"`
+}
+
+// Error implements error.
+func (e *ImportRowError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Unwrap returns Err.
+func (e *ImportRowError) Unwrap() error { return e.Err }
+
+// ImportReport is the outcome of an Import, ready to return to its caller.
+type ImportReport struct {
+	// Rows is the number of rows read, Created the number inserted.
+	Rows    int `json:"rows"`
+	Created int `json:"created"`
+	// Errors lists the failed rows, in input order.
+	Errors []ImportRowError `json:"errors,omitempty"`
+}
+
+// ImportSpec describes how Import reads, checks and writes the rows of an
+// entity whose create request is R. Generated {Entity}Importers fill it in.
+type ImportSpec[R any] struct {
+	// Columns are the fields CSV input may have columns for.
+	Columns []ImportColumn
+	// ParseCSV converts the cells of a CSV row, keyed by ImportColumn.Field,
+	// into a request. Empty cells are left out.
+	ParseCSV func(cells map[string]string) (*R, error)
+	// Validate, when set, checks each request before it is queued.
+	Validate func(ctx context.Context, req *R) error
+	// Insert writes a batch of valid requests, all or none of them.
+	Insert func(ctx context.Context, reqs []*R) error
+	// BatchSize is the number of requests per Insert, DefaultImportBatchSize
+	// when zero.
+	BatchSize int
+	// MaxErrors, when positive, stops Import once that many rows failed.
+	MaxErrors int
+}
+
+// Import reads the rows of src in format, decodes and validates each one and
+// inserts the valid ones in batches, without holding more than a batch in
+// memory. When a batch fails, its rows are inserted one by one, so that a
+// failing row does not fail the others. Rows that cannot be decoded, are
+// invalid or fail to insert are listed in the Errors of the report, which
+// Import returns with the rows created so far even when it fails.
+//
+// Import fails when the input cannot be read, with an ErrValidation error for
+// an unknown CSV column or a malformed line, when ctx is done, or when
+// MaxErrors is reached. Outside a transaction, the batches inserted until
+// then are kept.
+func Import[R any](ctx context.Context, src io.Reader, format ImportFormat, spec ImportSpec[R]) (*ImportReport, error) {
+	if spec.BatchSize <= 0 {
+		spec.BatchSize = DefaultImportBatchSize
+	}
+	im := &importer[R]{spec: spec, report: &ImportReport{}}
+	var err error
+	switch format {
+	case ImportCSV:
+		err = im.readCSV(ctx, src)
+	case ImportJSONL:
+		err = im.readJSONL(ctx, src)
+	default:
+		return nil, fmt.Errorf("%w: unknown import format %q", ErrValidation, format)
+	}
+	if err == nil {
+		err = im.flush(ctx)
+	}
+	// Rows failing to insert are only known once their batch is written.
+	slices.SortStableFunc(im.report.Errors, func(a, b ImportRowError) int { return cmp.Compare(a.Line, b.Line) })
+	return im.report, err
+}
+
+// importRow is a valid row waiting for its batch to be inserted.
+type importRow[R any] struct {
+	line int
+	req  *R
+}
+
+// importer holds the state of one Import.
+type importer[R any] struct {
+	spec    ImportSpec[R]
+	report  *ImportReport
+	pending []importRow[R]
+	failed  int
+}
+
+// readCSV reads the header of src, then each of its rows.
+func (im *importer[R]) readCSV(ctx context.Context, src io.Reader) error {
+	r := csv.NewReader(src)
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	fields, err := im.columns(header)
+	if err != nil {
+		return err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		line, _ := r.FieldPos(0)
+		var req *R
+		if err == nil {
+			cells := make(map[string]string, len(record))
+			for i, cell := range record {
+				if cell != "" {
+					cells[fields[i]] = cell
+				}
+			}
+			req, err = im.spec.ParseCSV(cells)
+		} else {
+			err = fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		if err := im.add(ctx, line, req, err); err != nil {
+			return err
+		}
+	}
+}
+
+// columns returns the fields of the CSV columns named by header.
+func (im *importer[R]) columns(header []string) ([]string, error) {
+	byName := make(map[string]string, 2*len(im.spec.Columns))
+	for _, c := range im.spec.Columns {
+		if c.Title != "" {
+			byName[strings.ToLower(c.Title)] = c.Field
+		}
+		byName[strings.ToLower(c.Field)] = c.Field
+	}
+	fields := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		field, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%w: unknown import column %q", ErrValidation, name)
+		case seen[field]:
+			return nil, fmt.Errorf("%w: import column %q is repeated", ErrValidation, name)
+		}
+		seen[field] = true
+		fields[i] = field
+	}
+	return fields, nil
+}
+
+// readJSONL reads each non-blank line of src as a request.
+func (im *importer[R]) readJSONL(ctx context.Context, src io.Reader) error {
+	r := bufio.NewReader(src)
+	for line := 1; ; line++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, readErr := r.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return readErr
+		}
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			req := new(R)
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.DisallowUnknownFields()
+			err := dec.Decode(req)
+			if err == nil && dec.More() {
+				err = errors.New("unexpected data after the JSON object")
+			}
+			if err != nil {
+				err = fmt.Errorf("%w: %w", ErrValidation, err)
+			}
+			if err := im.add(ctx, line, req, err); err != nil {
+				return err
+			}
+		}
+		if readErr != nil {
+			return nil
+		}
+	}
+}
+
+// add validates the row decoded from line, or records err, and queues it,
+// inserting the batch once full.
+func (im *importer[R]) add(ctx context.Context, line int, req *R, err error) error {
+	im.report.Rows++
+	if err == nil && im.spec.Validate != nil {
+		err = im.spec.Validate(ctx, req)
+	}
+	if err != nil {
+		return im.fail(line, err)
+	}
+	im.pending = append(im.pending, importRow[R]{line: line, req: req})
+	if len(im.pending) < im.spec.BatchSize {
+		return nil
+	}
+	return im.flush(ctx)
+}
+
+// flush inserts the pending rows, one by one when they fail together.
+func (im *importer[R]) flush(ctx context.Context) error {
+	rows := im.pending
+	im.pending = nil
+	if len(rows) == 0 {
+		return nil
+	}
+	reqs := make([]*R, len(rows))
+	for i, row := range rows {
+		reqs[i] = row.req
+	}
+	err := im.spec.Insert(ctx, reqs)
+	if err == nil {
+		im.report.Created += len(rows)
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if len(rows) == 1 {
+		return im.fail(rows[0].line, err)
+	}
+	for _, row := range rows {
+		if err := im.spec.Insert(ctx, []*R{row.req}); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err := im.fail(row.line, err); err != nil {
+				return err
+			}
+			continue
+		}
+		im.report.Created++
+	}
+	return nil
+}
+
+// fail records the error of the row from line, one ImportRowError per field
+// of ValidationErrors, and stops the import once MaxErrors rows failed.
+func (im *importer[R]) fail(line int, err error) error {
+	var violations ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &violations) && len(violations) > 0:
+		for _, v := range violations {
+			im.report.Errors = append(im.report.Errors, ImportRowError{Line: line, Field: v.Field, Message: v.describe(), Err: err})
+		}
+	case errors.As(err, &typeErr):
+		im.report.Errors = append(im.report.Errors, ImportRowError{Line: line, Field: typeErr.Field, Message: err.Error(), Err: err})
+	default:
+		im.report.Errors = append(im.report.Errors, ImportRowError{Line: line, Message: err.Error(), Err: err})
+	}
+	im.failed++
+	if im.spec.MaxErrors > 0 && im.failed >= im.spec.MaxErrors {
+		return fmt.Errorf("%w: import stopped after %d failed rows", ErrValidation, im.failed)
+	}
+	return nil
+}
+
+// ImportCell parses the cell of field in cells with parse, as generated
+// importers read CSV rows. It reports false when the cell is absent, or when
+// parse fails, in which case a violation of the "type" rule, with the Go type
+// as param, is added to violations.
+func ImportCell[T any](cells map[string]string, field string, parse func(string) (T, error), violations *ValidationErrors) (T, bool) {
+	cell, ok := cells[field]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	v, err := parse(cell)
+	if err != nil {
+		var zero T
+		*violations = append(*violations, FieldViolation{Field: field, Rule: "type", Param: fmt.Sprintf("%T", zero)})
+		return zero, false
+	}
+	return v, true
+}
+
+// ParseJSON decodes s as JSON, for CSV cells of fields without a plain text
+// form such as JSON fields.
+func ParseJSON[T any](s string) (T, error) {
+	var v T
+	err := json.Unmarshal([]byte(s), &v)
+	return v, err
+}
@@ -0,0 +1,160 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type importRequest struct {
+	Name string `json:"name"`
+	Age  *int   `json:"age,omitempty"`
+}
+
+// importFixture records the batches inserted by the spec it returns, which
+// rejects names some other row already used, as a unique column would.
+type importFixture struct {
+	batches [][]string
+	names   map[string]bool
+}
+
+func (f *importFixture) spec() ImportSpec[importRequest] {
+	f.names = map[string]bool{}
+	return ImportSpec[importRequest]{
+		Columns: []ImportColumn{{Field: "name", Title: "Full name"}, {Field: "age"}},
+		ParseCSV: func(cells map[string]string) (*importRequest, error) {
+			req := &importRequest{}
+			var violations ValidationErrors
+			if v, ok := ImportCell(cells, "name", ParseString, &violations); ok {
+				req.Name = v
+			}
+			if v, ok := ImportCell(cells, "age", strconv.Atoi, &violations); ok {
+				req.Age = &v
+			}
+			if len(violations) > 0 {
+				return nil, violations
+			}
+			return req, nil
+		},
+		Validate: func(_ context.Context, req *importRequest) error {
+			if req.Name == "" {
+				return ValidationErrors{{Field: "name", Rule: "required"}}
+			}
+			return nil
+		},
+		Insert: func(_ context.Context, reqs []*importRequest) error {
+			batch := make([]string, len(reqs))
+			for i, req := range reqs {
+				if f.names[req.Name] {
+					return fmt.Errorf("%w: name %s", ErrAlreadyExists, req.Name)
+				}
+				batch[i] = req.Name
+			}
+			for _, name := range batch {
+				f.names[name] = true
+			}
+			f.batches = append(f.batches, batch)
+			return nil
+		},
+		BatchSize: 2,
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	f := &importFixture{}
+	src := "\ufeffFull Name,age\nann,30\nbob,\n,5\ncy,old\ndee,1,extra\nann,2\neve,3\n"
+	report, err := Import(context.Background(), strings.NewReader(src), ImportCSV, f.spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Rows != 7 || report.Created != 3 {
+		t.Errorf("report = %d rows, %d created, want 7 and 3", report.Rows, report.Created)
+	}
+	if got := fmt.Sprint(f.batches); got != "[[ann bob] [eve]]" {
+		t.Errorf("batches = %s", got)
+	}
+
+	want := []struct {
+		line  int
+		field string
+		is    error
+	}{
+		{4, "name", ErrValidation},
+		{5, "age", ErrValidation},
+		{6, "", ErrValidation},
+		{7, "", ErrAlreadyExists},
+	}
+	if len(report.Errors) != len(want) {
+		t.Fatalf("errors = %v, want %d", report.Errors, len(want))
+	}
+	for i, w := range want {
+		got := report.Errors[i]
+		if got.Line != w.line || got.Field != w.field || !errors.Is(&got, w.is) {
+			t.Errorf("error %d = %+v, want line %d field %q matching %v", i, got, w.line, w.field, w.is)
+		}
+	}
+	if msg := report.Errors[1].Error(); msg != "line 5: age: type=int" {
+		t.Errorf("Error() = %q", msg)
+	}
+}
+
+func TestImportJSONL(t *testing.T) {
+	f := &importFixture{}
+	src := "{\"name\":\"ann\",\"age\":30}\n\n{\"name\":\"bob\",\"age\":\"x\"}\n{\"name\":\"cy\",\"nick\":\"c\"}\n{\"name\":\"dee\"} {}\n{\"name\":\"eve\"}"
+	report, err := Import(context.Background(), strings.NewReader(src), ImportJSONL, f.spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Rows != 5 || report.Created != 2 {
+		t.Errorf("report = %d rows, %d created, want 5 and 2", report.Rows, report.Created)
+	}
+	var lines []int
+	for _, e := range report.Errors {
+		lines = append(lines, e.Line)
+		if !errors.Is(&e, ErrValidation) {
+			t.Errorf("line %d: %v should match ErrValidation", e.Line, e.Err)
+		}
+	}
+	if fmt.Sprint(lines) != "[3 4 5]" {
+		t.Errorf("failed lines = %v, want [3 4 5]", lines)
+	}
+	if report.Errors[0].Field != "age" {
+		t.Errorf("type error field = %q, want age", report.Errors[0].Field)
+	}
+}
+
+func TestImportFailures(t *testing.T) {
+	ctx := context.Background()
+	f := &importFixture{}
+	for name, src := range map[string]string{
+		"unknown column":  "name,nick\n",
+		"repeated column": "name,Full name\n",
+		"bad quote":       "name\n\"ann\n",
+	} {
+		if _, err := Import(ctx, strings.NewReader(src), ImportCSV, f.spec()); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: err = %v, want ErrValidation", name, err)
+		}
+	}
+	if _, err := Import(ctx, strings.NewReader(""), "xml", f.spec()); !errors.Is(err, ErrValidation) {
+		t.Errorf("unknown format: err = %v, want ErrValidation", err)
+	}
+	if report, err := Import(ctx, strings.NewReader(""), ImportCSV, f.spec()); err != nil || report.Rows != 0 {
+		t.Errorf("empty input = %+v, %v", report, err)
+	}
+
+	spec := f.spec()
+	spec.MaxErrors = 2
+	report, err := Import(ctx, strings.NewReader("name,age\nann,x\nbob,y\ncy,1\n"), ImportCSV, spec)
+	if !errors.Is(err, ErrValidation) || report == nil || len(report.Errors) != 2 {
+		t.Errorf("MaxErrors: report = %+v, err = %v", report, err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := Import(canceled, strings.NewReader("name\nann\n"), ImportCSV, f.spec()); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled: err = %v", err)
+	}
+}
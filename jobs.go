@@ -0,0 +1,49 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Job is a unit of scheduled maintenance work, such as purging expired rows.
+// Generated services expose their maintenance routines as Jobs so they can be
+// registered with cron, Temporal, or any other scheduler the same way.
+type Job interface {
+	// Name returns a stable identifier like "user.purge_deleted", suitable
+	// for schedule keys, metrics, and logs.
+	Name() string
+
+	// Run performs one pass of the job. It should be safe to run repeatedly.
+	Run(ctx context.Context) error
+}
+
+// funcJob adapts a function to the Job interface.
+type funcJob struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func (j funcJob) Name() string                  { return j.name }
+func (j funcJob) Run(ctx context.Context) error { return j.run(ctx) }
+
+// NewJob returns a Job with the given name that calls run.
+func NewJob(name string, run func(ctx context.Context) error) Job {
+	return funcJob{name: name, run: run}
+}
+
+// RunJobs runs jobs sequentially. A failing job does not stop the others;
+// all failures are returned joined, each prefixed with the job's name.
+func RunJobs(ctx context.Context, jobs ...Job) error {
+	var errs []error
+	for _, job := range jobs {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := job.Run(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", job.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewJob(t *testing.T) {
+	var ran bool
+	job := NewJob("user.purge_deleted", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+
+	if job.Name() != "user.purge_deleted" {
+		t.Errorf("Name() = %q, want %q", job.Name(), "user.purge_deleted")
+	}
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !ran {
+		t.Error("Run() did not call the job function")
+	}
+}
+
+func TestRunJobs(t *testing.T) {
+	boom := errors.New("boom")
+
+	t.Run("runs all jobs and joins failures", func(t *testing.T) {
+		var order []string
+		record := func(name string, err error) Job {
+			return NewJob(name, func(ctx context.Context) error {
+				order = append(order, name)
+				return err
+			})
+		}
+
+		err := RunJobs(context.Background(), record("a", nil), record("b", boom), record("c", nil))
+		if !errors.Is(err, boom) {
+			t.Fatalf("RunJobs() error = %v, want wrapping %v", err, boom)
+		}
+		if !strings.Contains(err.Error(), "b: boom") {
+			t.Errorf("error %q should be prefixed with the job name", err)
+		}
+		if strings.Join(order, ",") != "a,b,c" {
+			t.Errorf("run order = %v, want [a b c]", order)
+		}
+	})
+
+	t.Run("no jobs", func(t *testing.T) {
+		if err := RunJobs(context.Background()); err != nil {
+			t.Errorf("RunJobs() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("stops on cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var ran bool
+		err := RunJobs(ctx, NewJob("a", func(ctx context.Context) error {
+			ran = true
+			return nil
+		}))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("RunJobs() error = %v, want context.Canceled", err)
+		}
+		if ran {
+			t.Error("job should not run with a cancelled context")
+		}
+	})
+}
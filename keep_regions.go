@@ -0,0 +1,135 @@
+package entdomain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keep-region markers. Lines between a begin and end marker in a generated
+// file are carried over when the file is regenerated. A begin marker may be
+// followed by a name ("// entdomain:begin keep imports"); named regions are
+// matched by name, unnamed ones by their order in the file.
+const (
+	keepBeginMarker = "// entdomain:begin keep"
+	keepEndMarker   = "// entdomain:end keep"
+)
+
+// keepRegion is the body of one keep region, keyed by name or ordinal.
+type keepRegion struct {
+	key  string
+	body []string
+}
+
+// parseKeepRegions extracts the keep regions of src in file order.
+func parseKeepRegions(src []byte) ([]keepRegion, error) {
+	var (
+		regions []keepRegion
+		current *keepRegion
+		unnamed int
+	)
+	for i, line := range strings.Split(string(src), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case isKeepBegin(trimmed):
+			if current != nil {
+				return nil, fmt.Errorf("line %d: nested %q", i+1, keepBeginMarker)
+			}
+			current = &keepRegion{key: keepRegionKey(trimmed, &unnamed)}
+		case trimmed == keepEndMarker:
+			if current == nil {
+				return nil, fmt.Errorf("line %d: %q without matching begin", i+1, keepEndMarker)
+			}
+			regions = append(regions, *current)
+			current = nil
+		case current != nil:
+			current.body = append(current.body, line)
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("unterminated keep region %q", current.key)
+	}
+	return regions, nil
+}
+
+// mergeKeepRegions returns generated with the body of each keep region
+// replaced by the matching region of existing. Regions of existing that no
+// longer have a counterpart in generated are appended at the end so custom
+// code is never silently dropped. The second result lists those orphans.
+func mergeKeepRegions(generated, existing []byte) ([]byte, []string, error) {
+	old, err := parseKeepRegions(existing)
+	if err != nil {
+		return nil, nil, fmt.Errorf("existing file: %w", err)
+	}
+	if len(old) == 0 {
+		return generated, nil, nil
+	}
+	if _, err := parseKeepRegions(generated); err != nil {
+		return nil, nil, fmt.Errorf("generated file: %w", err)
+	}
+
+	kept := make(map[string][]string, len(old))
+	for _, r := range old {
+		kept[r.key] = r.body
+	}
+
+	var (
+		out     []string
+		inside  bool
+		unnamed int
+	)
+	for _, line := range strings.Split(string(generated), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case isKeepBegin(trimmed):
+			key := keepRegionKey(trimmed, &unnamed)
+			out = append(out, line)
+			if body, ok := kept[key]; ok {
+				out = append(out, body...)
+				delete(kept, key)
+				inside = true
+			}
+		case trimmed == keepEndMarker:
+			out = append(out, line)
+			inside = false
+		case !inside:
+			out = append(out, line)
+		}
+	}
+
+	var orphans []string
+	for _, r := range old {
+		body, ok := kept[r.key]
+		if !ok {
+			continue
+		}
+		orphans = append(orphans, r.key)
+		begin := keepBeginMarker
+		if !strings.HasPrefix(r.key, "#") {
+			begin += " " + r.key
+		}
+		out = append(out, begin)
+		out = append(out, body...)
+		out = append(out, keepEndMarker, "")
+	}
+
+	return []byte(strings.Join(out, "\n")), orphans, nil
+}
+
+// isKeepBegin reports whether a trimmed line is a begin marker.
+func isKeepBegin(trimmed string) bool {
+	if !strings.HasPrefix(trimmed, keepBeginMarker) {
+		return false
+	}
+	rest := trimmed[len(keepBeginMarker):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+// keepRegionKey returns the region's name, or "#<n>" for the n-th unnamed region.
+func keepRegionKey(trimmed string, unnamed *int) string {
+	if name := strings.TrimSpace(trimmed[len(keepBeginMarker):]); name != "" {
+		return name
+	}
+	key := fmt.Sprintf("#%d", *unnamed)
+	*unnamed++
+	return key
+}
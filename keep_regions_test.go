@@ -0,0 +1,155 @@
+package entdomain
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKeepRegions(t *testing.T) {
+	src := []byte(`package ent
+
+// entdomain:begin keep
+var a = 1
+// entdomain:end keep
+
+func f() {
+	// entdomain:begin keep body
+	x := 2
+	_ = x
+	// entdomain:end keep
+}
+
+// entdomain:begin keep
+// entdomain:end keep
+`)
+	regions, err := parseKeepRegions(src)
+	if err != nil {
+		t.Fatalf("parseKeepRegions() error = %v", err)
+	}
+	want := []keepRegion{
+		{key: "#0", body: []string{"var a = 1"}},
+		{key: "body", body: []string{"\tx := 2", "\t_ = x"}},
+		{key: "#1"},
+	}
+	if !reflect.DeepEqual(regions, want) {
+		t.Errorf("parseKeepRegions() = %#v, want %#v", regions, want)
+	}
+}
+
+func TestParseKeepRegions_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unterminated", "// entdomain:begin keep\nx\n"},
+		{"nested", "// entdomain:begin keep\n// entdomain:begin keep\n// entdomain:end keep\n"},
+		{"stray end", "x\n// entdomain:end keep\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseKeepRegions([]byte(tt.src)); err == nil {
+				t.Error("parseKeepRegions() should fail")
+			}
+		})
+	}
+}
+
+func TestParseKeepRegions_IgnoresSimilarComments(t *testing.T) {
+	regions, err := parseKeepRegions([]byte("// entdomain:begin keeper\n"))
+	if err != nil || len(regions) != 0 {
+		t.Errorf("parseKeepRegions() = %v, %v; want no regions", regions, err)
+	}
+}
+
+func TestMergeKeepRegions(t *testing.T) {
+	generated := []byte(`package ent
+
+// v2
+// entdomain:begin keep custom
+// entdomain:end keep
+`)
+	existing := []byte(`package ent
+
+// v1
+// entdomain:begin keep custom
+func Custom() {}
+// entdomain:end keep
+`)
+	merged, orphans, err := mergeKeepRegions(generated, existing)
+	if err != nil {
+		t.Fatalf("mergeKeepRegions() error = %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("orphans = %v, want none", orphans)
+	}
+	want := `package ent
+
+// v2
+// entdomain:begin keep custom
+func Custom() {}
+// entdomain:end keep
+`
+	if string(merged) != want {
+		t.Errorf("mergeKeepRegions() =\n%s\nwant\n%s", merged, want)
+	}
+}
+
+func TestMergeKeepRegions_KeepsGeneratedDefaultWhenUnmatched(t *testing.T) {
+	generated := []byte("// entdomain:begin keep a\n// default\n// entdomain:end keep\n")
+	merged, _, err := mergeKeepRegions(generated, []byte("package ent\n"))
+	if err != nil {
+		t.Fatalf("mergeKeepRegions() error = %v", err)
+	}
+	if string(merged) != string(generated) {
+		t.Errorf("mergeKeepRegions() = %q, want generated content unchanged", merged)
+	}
+}
+
+func TestMergeKeepRegions_AppendsOrphans(t *testing.T) {
+	generated := []byte("package ent\n")
+	existing := []byte("package ent\n// entdomain:begin keep gone\nvar x = 1\n// entdomain:end keep\n")
+
+	merged, orphans, err := mergeKeepRegions(generated, existing)
+	if err != nil {
+		t.Fatalf("mergeKeepRegions() error = %v", err)
+	}
+	if !reflect.DeepEqual(orphans, []string{"gone"}) {
+		t.Errorf("orphans = %v, want [gone]", orphans)
+	}
+	if !strings.Contains(string(merged), "// entdomain:begin keep gone\nvar x = 1\n// entdomain:end keep") {
+		t.Errorf("orphaned region not appended:\n%s", merged)
+	}
+}
+
+func TestMergeKeepRegions_InvalidExisting(t *testing.T) {
+	_, _, err := mergeKeepRegions([]byte("package ent\n"), []byte("// entdomain:begin keep\n"))
+	if err == nil {
+		t.Error("mergeKeepRegions() should fail on an unterminated region in the existing file")
+	}
+}
+
+func TestWriteFile_PreservesKeepRegions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user_dto.go")
+	existing := "package ent\n\n// entdomain:begin keep custom\nconst Custom = 1\n// entdomain:end keep\n"
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	generated := "package ent\n\nconst Generated = 2\n\n// entdomain:begin keep custom\n// entdomain:end keep\n"
+	if err := writeFile(path, []byte(generated)); err != nil {
+		t.Fatalf("writeFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"const Generated = 2", "const Custom = 1"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("written file missing %q:\n%s", want, content)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package entdomain
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+)
+
+// DefaultOutboxTable is the table SQLOutbox stores messages in when its Table
+// is empty.
+const DefaultOutboxTable = "entdomain_outbox"
+
+// DefaultOutboxBatchSize is the number of messages OutboxRelay reads per query
+// when its BatchSize is zero.
+const DefaultOutboxBatchSize = 100
+
+// OutboxMessage is a DomainEvent as stored in an Outbox: its name and its JSON
+// encoding. It is itself a DomainEvent, the one OutboxRelay publishes.
+type OutboxMessage struct {
+	// ID is assigned by the outbox when the message is stored, in increasing
+	// order. Subscribers can use it to discard redeliveries.
+	ID        int64
+	Name      string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// EventName implements DomainEvent.
+func (m OutboxMessage) EventName() string {
+	return m.Name
+}
+
+// NewOutboxMessage returns the message storing event, with event encoded as
+// JSON.
+func NewOutboxMessage(event DomainEvent) (OutboxMessage, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return OutboxMessage{}, fmt.Errorf("encode %s event: %w", event.EventName(), err)
+	}
+	return OutboxMessage{Name: event.EventName(), Payload: payload, CreatedAt: time.Now()}, nil
+}
+
+// Outbox stores domain events in the transaction of the write that raised
+// them, so an event is stored if and only if its write commits. Generated
+// services of entities declared with DomainConfig.WithEvents append to their
+// Outbox when one is set.
+type Outbox interface {
+	// Append stores messages with exec, the driver of the write's
+	// transaction.
+	Append(ctx context.Context, exec dialect.ExecQuerier, messages ...OutboxMessage) error
+}
+
+// OutboxStore is an Outbox whose messages OutboxRelay can read back.
+type OutboxStore interface {
+	Outbox
+
+	// Pending returns up to limit stored messages, in the order of their IDs.
+	Pending(ctx context.Context, limit int) ([]OutboxMessage, error)
+
+	// Remove deletes the messages with the given IDs once delivered.
+	Remove(ctx context.Context, ids ...int64) error
+}
+
+// SQLOutbox implements OutboxStore with a table of the application database,
+// by default:
+//
+//	CREATE TABLE entdomain_outbox (
+//	    id         BIGSERIAL PRIMARY KEY, -- AUTO_INCREMENT on MySQL
+//	    name       VARCHAR(255) NOT NULL,
+//	    payload    BYTEA NOT NULL,         -- BLOB on MySQL and SQLite
+//	    created_at TIMESTAMP NOT NULL
+//	);
+type SQLOutbox struct {
+	// DB is the database Pending and Remove query. Append writes through the
+	// transaction it is given instead.
+	DB *sql.DB
+
+	// Dialect is the ent dialect of the database, e.g. dialect.Postgres.
+	Dialect string
+
+	// Table is the outbox table; DefaultOutboxTable when empty.
+	Table string
+}
+
+func (o SQLOutbox) table() string {
+	if o.Table == "" {
+		return DefaultOutboxTable
+	}
+	return o.Table
+}
+
+// Append implements Outbox with one INSERT of all messages.
+func (o SQLOutbox) Append(ctx context.Context, exec dialect.ExecQuerier, messages ...OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	insert := entsql.Dialect(o.Dialect).Insert(o.table()).Columns("name", "payload", "created_at")
+	for _, m := range messages {
+		insert.Values(m.Name, m.Payload, m.CreatedAt)
+	}
+	query, args := insert.Query()
+	if err := exec.Exec(ctx, query, args, nil); err != nil {
+		return fmt.Errorf("outbox append: %w", err)
+	}
+	return nil
+}
+
+// Pending implements OutboxStore.
+func (o SQLOutbox) Pending(ctx context.Context, limit int) ([]OutboxMessage, error) {
+	query, args := entsql.Dialect(o.Dialect).
+		Select("id", "name", "payload", "created_at").
+		From(entsql.Table(o.table())).
+		OrderBy("id").
+		Limit(limit).
+		Query()
+	rows, err := o.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("outbox pending: %w", err)
+	}
+	defer rows.Close()
+	var messages []OutboxMessage
+	for rows.Next() {
+		var m OutboxMessage
+		if err := rows.Scan(&m.ID, &m.Name, &m.Payload, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("outbox pending: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// Remove implements OutboxStore.
+func (o SQLOutbox) Remove(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	values := make([]any, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	query, args := entsql.Dialect(o.Dialect).Delete(o.table()).Where(entsql.In("id", values...)).Query()
+	if _, err := o.DB.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("outbox remove: %w", err)
+	}
+	return nil
+}
+
+// OutboxRelay is the Job delivering the messages of Store to Bus. Messages
+// are removed only after Bus accepted them, so each is delivered at least
+// once: a crash or failed Publish leaves it to the next run, and subscribers
+// should tolerate duplicates. Schedule one relay at a time, e.g. under
+// WithAdvisoryLock, to keep duplicates to such failures.
+type OutboxRelay struct {
+	Store OutboxStore
+	Bus   EventBus
+
+	// BatchSize bounds the messages read per query; DefaultOutboxBatchSize
+	// when zero.
+	BatchSize int
+}
+
+// Name implements Job.
+func (r OutboxRelay) Name() string {
+	return "entdomain.outbox_relay"
+}
+
+// Run implements Job. It delivers the pending messages in order until none
+// are left, and stops at the first one Bus rejects.
+func (r OutboxRelay) Run(ctx context.Context) error {
+	size := r.BatchSize
+	if size <= 0 {
+		size = DefaultOutboxBatchSize
+	}
+	for {
+		messages, err := r.Store.Pending(ctx, size)
+		if err != nil {
+			return err
+		}
+		ids := make([]int64, 0, len(messages))
+		for _, m := range messages {
+			if err := r.Bus.Publish(ctx, m); err != nil {
+				err = fmt.Errorf("outbox message %d: %w", m.ID, err)
+				return errors.Join(err, r.Store.Remove(ctx, ids...))
+			}
+			ids = append(ids, m.ID)
+		}
+		if err := r.Store.Remove(ctx, ids...); err != nil {
+			return err
+		}
+		if len(messages) < size {
+			return nil
+		}
+	}
+}
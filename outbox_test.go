@@ -0,0 +1,100 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"entgo.io/ent/dialect"
+)
+
+type recordingExec struct {
+	query string
+	args  []any
+}
+
+func (e *recordingExec) Exec(_ context.Context, query string, args, _ any) error {
+	e.query, e.args = query, args.([]any)
+	return nil
+}
+
+func (e *recordingExec) Query(context.Context, string, any, any) error {
+	return errors.New("unexpected query")
+}
+
+func TestSQLOutboxAppend(t *testing.T) {
+	msg, err := NewOutboxMessage(testEvent{})
+	if err != nil || msg.Name != "post.created" || string(msg.Payload) != "{}" {
+		t.Fatalf("NewOutboxMessage() = %+v, %v", msg, err)
+	}
+	var exec recordingExec
+	outbox := SQLOutbox{Dialect: dialect.Postgres}
+	if err := outbox.Append(context.Background(), &exec, msg, msg); err != nil {
+		t.Fatal(err)
+	}
+	want := `INSERT INTO "entdomain_outbox" ("name", "payload", "created_at") VALUES ($1, $2, $3), ($4, $5, $6)`
+	if exec.query != want || len(exec.args) != 6 {
+		t.Errorf("Append() ran %q with %d args, want %q", exec.query, len(exec.args), want)
+	}
+}
+
+type memoryOutbox struct {
+	messages []OutboxMessage
+}
+
+func (o *memoryOutbox) Append(_ context.Context, _ dialect.ExecQuerier, messages ...OutboxMessage) error {
+	o.messages = append(o.messages, messages...)
+	return nil
+}
+
+func (o *memoryOutbox) Pending(_ context.Context, limit int) ([]OutboxMessage, error) {
+	return o.messages[:min(limit, len(o.messages))], nil
+}
+
+func (o *memoryOutbox) Remove(_ context.Context, ids ...int64) error {
+	removed := make(map[int64]bool)
+	for _, id := range ids {
+		removed[id] = true
+	}
+	var kept []OutboxMessage
+	for _, m := range o.messages {
+		if !removed[m.ID] {
+			kept = append(kept, m)
+		}
+	}
+	o.messages = kept
+	return nil
+}
+
+func TestOutboxRelay(t *testing.T) {
+	store := &memoryOutbox{}
+	for id := int64(1); id <= 5; id++ {
+		store.messages = append(store.messages, OutboxMessage{ID: id, Name: "post.created"})
+	}
+	var delivered []int64
+	fail := errors.New("broker down")
+	down := true
+	bus := EventBusFunc(func(_ context.Context, event DomainEvent) error {
+		m := event.(OutboxMessage)
+		if m.ID == 4 && down {
+			down = false
+			return fail
+		}
+		delivered = append(delivered, m.ID)
+		return nil
+	})
+	relay := OutboxRelay{Store: store, Bus: bus, BatchSize: 2}
+
+	if err := relay.Run(context.Background()); !errors.Is(err, fail) {
+		t.Fatalf("Run() = %v, want the bus error", err)
+	}
+	if len(store.messages) != 2 || store.messages[0].ID != 4 {
+		t.Fatalf("after failed run, pending = %+v, want 4 and 5", store.messages)
+	}
+	if err := relay.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.messages) != 0 || len(delivered) != 5 {
+		t.Errorf("after retry, pending = %+v, delivered %v", store.messages, delivered)
+	}
+}
@@ -0,0 +1,368 @@
+package entdomain
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PatchRequest is a patch of the JSON document of an entity's update fields,
+// as applied by the generated Patch methods. MergePatch and JSONPatch
+// implement it.
+type PatchRequest interface {
+	// ApplyPatch returns doc with the patch applied. doc holds decoded JSON
+	// values and is not modified.
+	ApplyPatch(doc map[string]any) (map[string]any, error)
+}
+
+// MergePatch is an RFC 7386 JSON merge patch: the members of the object
+// replace those of the document, objects are merged recursively, and null
+// removes a member. Decode request bodies of type application/merge-patch+json
+// into it.
+type MergePatch map[string]any
+
+// ApplyPatch implements PatchRequest.
+func (p MergePatch) ApplyPatch(doc map[string]any) (map[string]any, error) {
+	return mergePatch(doc, p), nil
+}
+
+func mergePatch(target any, patch map[string]any) map[string]any {
+	out, _ := target.(map[string]any)
+	out = maps.Clone(out)
+	if out == nil {
+		out = make(map[string]any, len(patch))
+	}
+	for k, v := range patch {
+		switch v := v.(type) {
+		case nil:
+			delete(out, k)
+		case map[string]any:
+			out[k] = mergePatch(out[k], v)
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// PatchOperation is an operation of a JSONPatch. Path and From are JSON
+// pointers (RFC 6901).
+type PatchOperation struct {
+	// Op is "add", "remove", "replace", "move", "copy" or "test".
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// JSONPatch is an RFC 6902 JSON patch: operations applied in order, all or
+// none. Decode request bodies of type application/json-patch+json into it.
+type JSONPatch []PatchOperation
+
+// ApplyPatch implements PatchRequest.
+func (p JSONPatch) ApplyPatch(doc map[string]any) (map[string]any, error) {
+	var root any = copyJSON(doc)
+	for i, op := range p {
+		var err error
+		if root, err = op.apply(root); err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	out, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("patch replaced the document with a %T", root)
+	}
+	return out, nil
+}
+
+func (op PatchOperation) apply(root any) (any, error) {
+	switch op.Op {
+	case "add":
+		return pointerSet(root, op.Path, copyJSON(op.Value), true)
+	case "remove":
+		return pointerRemove(root, op.Path)
+	case "replace":
+		if _, err := pointerGet(root, op.Path); err != nil {
+			return nil, err
+		}
+		return pointerSet(root, op.Path, copyJSON(op.Value), false)
+	case "move", "copy":
+		v, err := pointerGet(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if strings.HasPrefix(op.Path, op.From+"/") {
+				return nil, fmt.Errorf("cannot move %s into itself", op.From)
+			}
+			if root, err = pointerRemove(root, op.From); err != nil {
+				return nil, err
+			}
+		}
+		return pointerSet(root, op.Path, copyJSON(v), true)
+	case "test":
+		v, err := pointerGet(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(v, normalizeJSON(op.Value)) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// splitPointer returns the reference tokens of a JSON pointer.
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses token as an index of arr; "-" is len(arr) when end is
+// allowed.
+func arrayIndex(arr []any, token string, end bool) (int, error) {
+	if token == "-" && end {
+		return len(arr), nil
+	}
+	i, err := strconv.Atoi(token)
+	limit := len(arr) - 1
+	if end {
+		limit = len(arr)
+	}
+	if err != nil || i < 0 || i > limit || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return i, nil
+}
+
+func pointerGet(root any, pointer string) (any, error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	v := root
+	for _, t := range tokens {
+		switch c := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = c[t]; !ok {
+				return nil, fmt.Errorf("no member %q", t)
+			}
+		case []any:
+			i, err := arrayIndex(c, t, false)
+			if err != nil {
+				return nil, err
+			}
+			v = c[i]
+		default:
+			return nil, fmt.Errorf("%q has no members", t)
+		}
+	}
+	return v, nil
+}
+
+// pointerSet sets the value at pointer, inserting into arrays when insert is
+// set, and returns the new root.
+func pointerSet(root any, pointer string, value any, insert bool) (any, error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parent, err := pointerGet(root, pointer[:strings.LastIndex(pointer, "/")])
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch c := parent.(type) {
+	case map[string]any:
+		c[last] = value
+		return root, nil
+	case []any:
+		i, err := arrayIndex(c, last, insert)
+		if err != nil {
+			return nil, err
+		}
+		if !insert {
+			c[i] = value
+			return root, nil
+		}
+		return pointerReplaceArray(root, pointer, slices.Insert(c, i, value))
+	default:
+		return nil, fmt.Errorf("parent of %q has no members", last)
+	}
+}
+
+func pointerRemove(root any, pointer string) (any, error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("cannot remove the document")
+	}
+	parent, err := pointerGet(root, pointer[:strings.LastIndex(pointer, "/")])
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch c := parent.(type) {
+	case map[string]any:
+		if _, ok := c[last]; !ok {
+			return nil, fmt.Errorf("no member %q", last)
+		}
+		delete(c, last)
+		return root, nil
+	case []any:
+		i, err := arrayIndex(c, last, false)
+		if err != nil {
+			return nil, err
+		}
+		return pointerReplaceArray(root, pointer, slices.Delete(c, i, i+1))
+	default:
+		return nil, fmt.Errorf("parent of %q has no members", last)
+	}
+}
+
+// pointerReplaceArray stores arr, the resized parent array of the value at
+// pointer, in its own parent, since resizing can move a slice.
+func pointerReplaceArray(root any, pointer string, arr []any) (any, error) {
+	parentPointer := pointer[:strings.LastIndex(pointer, "/")]
+	if parentPointer == "" {
+		return arr, nil
+	}
+	return pointerSet(root, parentPointer, arr, false)
+}
+
+// copyJSON returns a deep copy of v, a decoded JSON value.
+func copyJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = copyJSON(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = copyJSON(e)
+		}
+		return out
+	default:
+		return normalizeJSON(v)
+	}
+}
+
+// normalizeJSON converts v to the types encoding/json decodes into any, so
+// values compare equal whether given as Go values or decoded from JSON.
+func normalizeJSON(v any) any {
+	switch v.(type) {
+	case nil, bool, float64, string, map[string]any, []any:
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// PatchFields describes the update fields of an entity to DecodePatch.
+type PatchFields struct {
+	// Updatable holds the column names a patch may change.
+	Updatable map[string]bool
+
+	// Clearable holds the column names a patch may remove, clearing the
+	// field: those of optional fields.
+	Clearable map[string]bool
+
+	// Required lists the column names the update request needs even when
+	// unchanged, such as the optimistic lock version. They are decoded from
+	// the patched document.
+	Required []string
+}
+
+// DecodePatch applies patch to doc, the current values of an entity's update
+// fields keyed by column name, and decodes the fields whose values it changes
+// into out, an update request whose JSON names are those columns. It returns
+// the column names of the changed and of the removed fields, sorted; the
+// removed ones must be cleared. Changing fields not Updatable, removing ones
+// not Clearable and failing patches are rejected with errors wrapping
+// ErrValidation.
+func DecodePatch(doc map[string]any, patch PatchRequest, fields PatchFields, out any) (changed, cleared []string, err error) {
+	if patch == nil {
+		return nil, nil, fmt.Errorf("%w: patch is required", ErrValidation)
+	}
+	before := copyJSON(doc).(map[string]any)
+	after, err := patch.ApplyPatch(copyJSON(doc).(map[string]any))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	after = copyJSON(after).(map[string]any)
+
+	set := make(map[string]any)
+	for _, k := range slices.Sorted(maps.Keys(after)) {
+		if old, ok := before[k]; ok && reflect.DeepEqual(old, after[k]) {
+			continue
+		}
+		if !fields.Updatable[k] {
+			return nil, nil, fmt.Errorf("%w: field %q cannot be updated", ErrValidation, k)
+		}
+		if after[k] == nil {
+			if !fields.Clearable[k] {
+				return nil, nil, fmt.Errorf("%w: field %q cannot be null", ErrValidation, k)
+			}
+			cleared = append(cleared, k)
+			continue
+		}
+		set[k] = after[k]
+		changed = append(changed, k)
+	}
+	for _, k := range slices.Sorted(maps.Keys(before)) {
+		if _, ok := after[k]; ok {
+			continue
+		}
+		if !fields.Clearable[k] {
+			return nil, nil, fmt.Errorf("%w: field %q cannot be removed", ErrValidation, k)
+		}
+		cleared = append(cleared, k)
+	}
+	slices.Sort(cleared)
+	if len(changed) == 0 && len(cleared) == 0 {
+		return nil, nil, nil
+	}
+
+	for _, k := range fields.Required {
+		if _, ok := set[k]; !ok && after[k] != nil {
+			set[k] = after[k]
+		}
+	}
+	if len(set) > 0 {
+		if err := convertFilterValue(set, out); err != nil {
+			return nil, nil, fmt.Errorf("%w: invalid patch: %v", ErrValidation, err)
+		}
+	}
+	return changed, cleared, nil
+}
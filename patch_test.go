@@ -0,0 +1,124 @@
+package entdomain
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, s string, out any) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(s), out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	var doc map[string]any
+	decodeJSON(t, `{"title": "a", "tags": ["x"], "meta": {"a": 1, "b": 2}}`, &doc)
+	var patch MergePatch
+	decodeJSON(t, `{"title": "b", "tags": null, "meta": {"b": null, "c": 3}}`, &patch)
+
+	got, err := patch.ApplyPatch(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want map[string]any
+	decodeJSON(t, `{"title": "b", "meta": {"a": 1, "c": 3}}`, &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyPatch() = %v, want %v", got, want)
+	}
+	if doc["title"] != "a" || len(doc["meta"].(map[string]any)) != 2 {
+		t.Errorf("ApplyPatch() modified the document: %v", doc)
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	var doc map[string]any
+	decodeJSON(t, `{"title": "a", "tags": ["x", "y"], "a~b": 1}`, &doc)
+
+	tests := []struct {
+		name, patch, want string
+	}{
+		{"add member", `[{"op": "add", "path": "/body", "value": "hi"}]`, `{"title": "a", "tags": ["x", "y"], "a~b": 1, "body": "hi"}`},
+		{"add to array", `[{"op": "add", "path": "/tags/1", "value": "z"}, {"op": "add", "path": "/tags/-", "value": "w"}]`, `{"title": "a", "tags": ["x", "z", "y", "w"], "a~b": 1}`},
+		{"remove", `[{"op": "remove", "path": "/tags/0"}, {"op": "remove", "path": "/a~0b"}]`, `{"title": "a", "tags": ["y"]}`},
+		{"replace", `[{"op": "test", "path": "/title", "value": "a"}, {"op": "replace", "path": "/title", "value": "b"}]`, `{"title": "b", "tags": ["x", "y"], "a~b": 1}`},
+		{"move and copy", `[{"op": "move", "from": "/title", "path": "/name"}, {"op": "copy", "from": "/tags/1", "path": "/tags/0"}]`, `{"name": "a", "tags": ["y", "x", "y"], "a~b": 1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch JSONPatch
+			decodeJSON(t, tt.patch, &patch)
+			got, err := patch.ApplyPatch(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want map[string]any
+			decodeJSON(t, tt.want, &want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ApplyPatch() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	for _, patch := range []string{
+		`[{"op": "test", "path": "/title", "value": "b"}]`,
+		`[{"op": "replace", "path": "/missing", "value": 1}]`,
+		`[{"op": "remove", "path": "/tags/5"}]`,
+		`[{"op": "add", "path": "title", "value": 1}]`,
+		`[{"op": "move", "from": "/tags", "path": "/tags/0"}]`,
+		`[{"op": "frobnicate", "path": "/title"}]`,
+	} {
+		var p JSONPatch
+		decodeJSON(t, patch, &p)
+		if _, err := p.ApplyPatch(doc); err == nil {
+			t.Errorf("ApplyPatch(%s) should fail", patch)
+		}
+	}
+	if len(doc["tags"].([]any)) != 2 || doc["title"] != "a" {
+		t.Errorf("ApplyPatch() modified the document: %v", doc)
+	}
+}
+
+func TestDecodePatch(t *testing.T) {
+	type request struct {
+		Title   *string `json:"title,omitempty"`
+		Body    *string `json:"body,omitempty"`
+		Version *int    `json:"version"`
+	}
+	doc := map[string]any{"title": "a", "body": "text", "version": 3}
+	fields := PatchFields{
+		Updatable: map[string]bool{"title": true, "body": true, "version": true},
+		Clearable: map[string]bool{"body": true},
+		Required:  []string{"version"},
+	}
+
+	var req request
+	changed, cleared, err := DecodePatch(doc, MergePatch{"title": "b", "body": nil}, fields, &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changed, []string{"title"}) || !reflect.DeepEqual(cleared, []string{"body"}) {
+		t.Errorf("changed %v, cleared %v", changed, cleared)
+	}
+	if req.Title == nil || *req.Title != "b" || req.Body != nil || req.Version == nil || *req.Version != 3 {
+		t.Errorf("decoded %+v", req)
+	}
+
+	changed, cleared, err = DecodePatch(doc, MergePatch{"title": "a"}, fields, &request{})
+	if err != nil || changed != nil || cleared != nil {
+		t.Errorf("no-op patch = %v, %v, %v", changed, cleared, err)
+	}
+
+	for name, patch := range map[string]PatchRequest{
+		"outside the scope": MergePatch{"owner": "x"},
+		"required removed":  MergePatch{"title": nil},
+		"failing":           JSONPatch{{Op: "test", Path: "/title", Value: "z"}},
+		"nil":               nil,
+	} {
+		if _, _, err := DecodePatch(doc, patch, fields, &request{}); !IsValidation(err) {
+			t.Errorf("%s patch: err = %v, want ErrValidation", name, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package entdomain
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Action identifies an operation performed on an entity.
+type Action string
+
+// Standard actions emitted by the generator for every annotated entity.
+const (
+	// ActionCreate covers creating a new entity.
+	ActionCreate Action = "create"
+
+	// ActionRead covers fetching a single entity.
+	ActionRead Action = "read"
+
+	// ActionUpdate covers modifying an existing entity.
+	ActionUpdate Action = "update"
+
+	// ActionDelete covers removing an entity (hard or soft delete).
+	ActionDelete Action = "delete"
+
+	// ActionList covers listing, searching, and paginating entities.
+	ActionList Action = "list"
+)
+
+// Permission is a dotted "{entity}.{action}" identifier such as "user.create".
+// Generated code emits one Permission constant per entity and action.
+type Permission string
+
+// NewPermission builds the Permission for the given entity and action.
+func NewPermission(entity string, action Action) Permission {
+	return Permission(entity + "." + string(action))
+}
+
+// Entity returns the entity part of the permission ("user" for "user.create").
+func (p Permission) Entity() string {
+	if i := strings.LastIndexByte(string(p), '.'); i >= 0 {
+		return string(p[:i])
+	}
+	return string(p)
+}
+
+// Action returns the action part of the permission ("create" for "user.create").
+func (p Permission) Action() Action {
+	if i := strings.LastIndexByte(string(p), '.'); i >= 0 {
+		return Action(p[i+1:])
+	}
+	return ""
+}
+
+// String implements fmt.Stringer.
+func (p Permission) String() string { return string(p) }
+
+// permissionRegistry holds every permission registered by generated code.
+var permissionRegistry = struct {
+	sync.RWMutex
+	perms map[Permission]struct{}
+}{perms: make(map[Permission]struct{})}
+
+// RegisterPermissions adds permissions to the process-wide registry.
+// Generated {entity}_permissions.go files call this from init(), so
+// importing the ent package is enough to make all permissions enumerable.
+// Registering the same permission twice is a no-op.
+func RegisterPermissions(perms ...Permission) {
+	permissionRegistry.Lock()
+	defer permissionRegistry.Unlock()
+	for _, p := range perms {
+		permissionRegistry.perms[p] = struct{}{}
+	}
+}
+
+// Permissions returns every registered permission in lexical order.
+// Use it to seed roles or permission tables at startup.
+func Permissions() []Permission {
+	permissionRegistry.RLock()
+	defer permissionRegistry.RUnlock()
+	perms := make([]Permission, 0, len(permissionRegistry.perms))
+	for p := range permissionRegistry.perms {
+		perms = append(perms, p)
+	}
+	sort.Slice(perms, func(i, j int) bool { return perms[i] < perms[j] })
+	return perms
+}
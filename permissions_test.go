@@ -0,0 +1,77 @@
+package entdomain
+
+import (
+	"testing"
+)
+
+func TestNewPermission(t *testing.T) {
+	p := NewPermission("user", ActionCreate)
+	if p != "user.create" {
+		t.Errorf("NewPermission() = %q, want %q", p, "user.create")
+	}
+	if p.Entity() != "user" {
+		t.Errorf("Entity() = %q, want %q", p.Entity(), "user")
+	}
+	if p.Action() != ActionCreate {
+		t.Errorf("Action() = %q, want %q", p.Action(), ActionCreate)
+	}
+	if p.String() != "user.create" {
+		t.Errorf("String() = %q, want %q", p.String(), "user.create")
+	}
+}
+
+func TestPermission_DottedEntity(t *testing.T) {
+	p := Permission("billing.invoice.read")
+	if p.Entity() != "billing.invoice" {
+		t.Errorf("Entity() = %q, want %q", p.Entity(), "billing.invoice")
+	}
+	if p.Action() != ActionRead {
+		t.Errorf("Action() = %q, want %q", p.Action(), ActionRead)
+	}
+}
+
+func TestPermission_NoAction(t *testing.T) {
+	p := Permission("user")
+	if p.Entity() != "user" {
+		t.Errorf("Entity() = %q, want %q", p.Entity(), "user")
+	}
+	if p.Action() != "" {
+		t.Errorf("Action() = %q, want empty", p.Action())
+	}
+}
+
+func TestRegisterPermissions(t *testing.T) {
+	RegisterPermissions(
+		NewPermission("zz_registry_test", ActionRead),
+		NewPermission("zz_registry_test", ActionCreate),
+	)
+	// Duplicate registration is a no-op
+	RegisterPermissions(NewPermission("zz_registry_test", ActionRead))
+
+	var got []Permission
+	for _, p := range Permissions() {
+		if p.Entity() == "zz_registry_test" {
+			got = append(got, p)
+		}
+	}
+
+	want := []Permission{"zz_registry_test.create", "zz_registry_test.read"}
+	if len(got) != len(want) {
+		t.Fatalf("Permissions() returned %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Permissions()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPermissions_Sorted(t *testing.T) {
+	RegisterPermissions("zz_sort.b", "zz_sort.a")
+	perms := Permissions()
+	for i := 1; i < len(perms); i++ {
+		if perms[i-1] > perms[i] {
+			t.Fatalf("Permissions() not sorted: %q before %q", perms[i-1], perms[i])
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package entdomain
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultTimeLayouts are the layouts ParseTime tries, in order, when the
+// caller passes none: RFC 3339 timestamps (with or without fractional
+// seconds) and plain dates. Generated QueryParams use them for time fields
+// without a "date" or "date-time" format annotation.
+var DefaultTimeLayouts = []string{time.RFC3339Nano, time.DateOnly}
+
+// ParseTime parses value with the first matching layout, or with
+// DefaultTimeLayouts when layouts is empty.
+func ParseTime(value string, layouts ...string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+	var firstErr error
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Time{}, firstErr
+}
+
+// ParseParam parses the query parameter key with parse. It returns nil when
+// the parameter is absent or empty, and an ErrValidation error naming the
+// parameter when parse fails.
+func ParseParam[T any](values url.Values, key string, parse func(string) (T, error)) (*T, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: query parameter %s: %v", ErrValidation, key, err)
+	}
+	return &v, nil
+}
+
+// ParseRange parses the "{key}_from" and "{key}_to" query parameters into an
+// inclusive range. Either bound may be absent; a from bound after the to bound
+// is an ErrValidation error.
+func ParseRange[T cmp.Ordered](values url.Values, key string, parse func(string) (T, error)) (from, to *T, err error) {
+	if from, err = ParseParam(values, key+"_from", parse); err != nil {
+		return nil, nil, err
+	}
+	if to, err = ParseParam(values, key+"_to", parse); err != nil {
+		return nil, nil, err
+	}
+	if from != nil && to != nil && *from > *to {
+		return nil, nil, fmt.Errorf("%w: query parameter %s_from is after %s_to", ErrValidation, key, key)
+	}
+	return from, to, nil
+}
+
+// ParseTimeParam parses the time query parameter key with the given layouts
+// (DefaultTimeLayouts when empty). It returns nil when the parameter is absent.
+func ParseTimeParam(values url.Values, key string, layouts ...string) (*time.Time, error) {
+	return ParseParam(values, key, func(s string) (time.Time, error) { return ParseTime(s, layouts...) })
+}
+
+// ParseTimeRange parses the "{key}_from" and "{key}_to" time query parameters
+// into an inclusive range. A date-only to bound ("2024-01-31") covers that
+// whole day, so it is moved to the last instant of the day.
+func ParseTimeRange(values url.Values, key string, layouts ...string) (from, to *time.Time, err error) {
+	if from, err = ParseTimeParam(values, key+"_from", layouts...); err != nil {
+		return nil, nil, err
+	}
+	if to, err = ParseTimeParam(values, key+"_to", layouts...); err != nil {
+		return nil, nil, err
+	}
+	if to != nil {
+		if _, err := time.Parse(time.DateOnly, values.Get(key+"_to")); err == nil {
+			end := to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			to = &end
+		}
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, fmt.Errorf("%w: query parameter %s_from is after %s_to", ErrValidation, key, key)
+	}
+	return from, to, nil
+}
+
+// ParseString is the identity parser, for string fields in ParseParam.
+func ParseString(s string) (string, error) { return s, nil }
+
+// ParseInt64 parses a base-10 int64, for use with ParseParam.
+func ParseInt64(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) }
+
+// ParseInt32 parses a base-10 int32, for use with ParseParam.
+func ParseInt32(s string) (int32, error) {
+	v, err := strconv.ParseInt(s, 10, 32)
+	return int32(v), err
+}
+
+// ParseFloat64 parses a float64, for use with ParseParam.
+func ParseFloat64(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
@@ -0,0 +1,120 @@
+package entdomain
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		layouts []string
+		want    time.Time
+		wantErr bool
+	}{
+		{"RFC3339", "2024-01-31T10:00:00Z", nil, time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC), false},
+		{"fractional seconds", "2024-01-31T10:00:00.5Z", nil, time.Date(2024, 1, 31, 10, 0, 0, 5e8, time.UTC), false},
+		{"date only", "2024-01-31", nil, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), false},
+		{"explicit layout rejects others", "2024-01-31", []string{time.RFC3339}, time.Time{}, true},
+		{"malformed", "yesterday", nil, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.value, tt.layouts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseParam(t *testing.T) {
+	values := url.Values{"age": {"42"}, "bad": {"x"}, "empty": {""}}
+
+	got, err := ParseParam(values, "age", strconv.Atoi)
+	if err != nil || got == nil || *got != 42 {
+		t.Errorf("ParseParam(age) = %v, %v, want 42", got, err)
+	}
+	for _, key := range []string{"missing", "empty"} {
+		if got, err := ParseParam(values, key, strconv.Atoi); got != nil || err != nil {
+			t.Errorf("ParseParam(%s) = %v, %v, want nil, nil", key, got, err)
+		}
+	}
+	if _, err := ParseParam(values, "bad", strconv.Atoi); !errors.Is(err, ErrValidation) {
+		t.Errorf("ParseParam(bad) error = %v, want ErrValidation", err)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	from, to, err := ParseRange(url.Values{"age_from": {"18"}, "age_to": {"65"}}, "age", strconv.Atoi)
+	if err != nil || *from != 18 || *to != 65 {
+		t.Errorf("ParseRange() = %v, %v, %v", from, to, err)
+	}
+
+	from, to, err = ParseRange(url.Values{"age_to": {"65"}}, "age", strconv.Atoi)
+	if err != nil || from != nil || *to != 65 {
+		t.Errorf("ParseRange() open start = %v, %v, %v", from, to, err)
+	}
+
+	if _, _, err := ParseRange(url.Values{"age_from": {"65"}, "age_to": {"18"}}, "age", strconv.Atoi); !errors.Is(err, ErrValidation) {
+		t.Errorf("ParseRange() inverted error = %v, want ErrValidation", err)
+	}
+}
+
+func TestParseTimeRange(t *testing.T) {
+	t.Run("date-only to covers the whole day", func(t *testing.T) {
+		from, to, err := ParseTimeRange(url.Values{"created_at_from": {"2024-01-01"}, "created_at_to": {"2024-01-31"}}, "created_at")
+		if err != nil {
+			t.Fatalf("ParseTimeRange() error = %v", err)
+		}
+		if want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !from.Equal(want) {
+			t.Errorf("from = %v, want %v", from, want)
+		}
+		if want := time.Date(2024, 1, 31, 23, 59, 59, 999999999, time.UTC); !to.Equal(want) {
+			t.Errorf("to = %v, want %v", to, want)
+		}
+	})
+
+	t.Run("timestamp to is exact", func(t *testing.T) {
+		_, to, err := ParseTimeRange(url.Values{"created_at_to": {"2024-01-31T10:00:00Z"}}, "created_at")
+		if err != nil {
+			t.Fatalf("ParseTimeRange() error = %v", err)
+		}
+		if want := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC); !to.Equal(want) {
+			t.Errorf("to = %v, want %v", to, want)
+		}
+	})
+
+	t.Run("inverted", func(t *testing.T) {
+		_, _, err := ParseTimeRange(url.Values{"created_at_from": {"2024-02-01"}, "created_at_to": {"2024-01-31"}}, "created_at")
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("ParseTimeRange() error = %v, want ErrValidation", err)
+		}
+	})
+
+	t.Run("layout restriction", func(t *testing.T) {
+		_, _, err := ParseTimeRange(url.Values{"created_at_from": {"2024-02-01"}}, "created_at", time.RFC3339)
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("ParseTimeRange() error = %v, want ErrValidation", err)
+		}
+	})
+}
+
+func TestNumericParsers(t *testing.T) {
+	if v, err := ParseInt64("9007199254740993"); err != nil || v != 9007199254740993 {
+		t.Errorf("ParseInt64() = %d, %v", v, err)
+	}
+	if _, err := ParseInt32("2147483648"); err == nil {
+		t.Error("ParseInt32() should reject values overflowing int32")
+	}
+	if v, err := ParseFloat64("1.5"); err != nil || v != 1.5 {
+		t.Errorf("ParseFloat64() = %v, %v", v, err)
+	}
+}
@@ -0,0 +1,27 @@
+package entdomain
+
+import "context"
+
+// RateLimiter throttles operations of generated services. They call Allow
+// before every operation with a key of the resource name and action, such as
+// "user:create" or "user:list", and fail the operation with its error.
+// Implementations refusing a call should return an error matching
+// ErrRateLimited, and usually add the caller from ctx to the key, e.g. with
+// ActorFromContext or TenantFromContext.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) error
+}
+
+// RateLimiterFunc adapts an ordinary function to the RateLimiter interface.
+type RateLimiterFunc func(ctx context.Context, key string) error
+
+// Allow calls f(ctx, key).
+func (f RateLimiterFunc) Allow(ctx context.Context, key string) error {
+	return f(ctx, key)
+}
+
+// RateLimitKey returns the key generated services pass to RateLimiter.Allow
+// for action on resource, e.g. "user:create".
+func RateLimitKey(resource string, action Action) string {
+	return resource + ":" + string(action)
+}
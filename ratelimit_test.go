@@ -0,0 +1,27 @@
+package entdomain
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRateLimiterFunc(t *testing.T) {
+	var keys []string
+	limiter := RateLimiterFunc(func(_ context.Context, key string) error {
+		keys = append(keys, key)
+		if len(keys) > 1 {
+			return ErrRateLimited
+		}
+		return nil
+	})
+	key := RateLimitKey("user", ActionCreate)
+	if err := limiter.Allow(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+	if err := limiter.Allow(context.Background(), key); !IsRateLimited(err) {
+		t.Errorf("second Allow() = %v, want ErrRateLimited", err)
+	}
+	if keys[0] != "user:create" {
+		t.Errorf("key = %q, want user:create", keys[0])
+	}
+}
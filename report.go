@@ -0,0 +1,102 @@
+package entdomain
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// graphEntity is the entity name recorded for graph-level files such as the schema snapshot.
+const graphEntity = "(graph)"
+
+// renderStat describes one rendered file in the generation report.
+type renderStat struct {
+	entity string
+	kind   string
+	render time.Duration
+	size   int
+	funcs  int
+}
+
+// generationReport collects per-file render statistics during one run of the
+// generation hook. A nil report records nothing, so call sites need no checks.
+type generationReport struct {
+	start time.Time
+	stats []renderStat
+}
+
+func newGenerationReport() *generationReport {
+	return &generationReport{start: time.Now()}
+}
+
+// record adds the statistics of a rendered file. render covers template
+// execution, plus parsing the first time a template is used; formatting and
+// writing are only part of the run total.
+func (r *generationReport) record(entity, kind string, render time.Duration, content []byte) {
+	if r == nil {
+		return
+	}
+	r.stats = append(r.stats, renderStat{
+		entity: entity,
+		kind:   kind,
+		render: render,
+		size:   len(content),
+		funcs:  countFuncs(content),
+	})
+}
+
+// countFuncs counts top-level function and method declarations in rendered source.
+func countFuncs(content []byte) int {
+	n := 0
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("func ")) {
+			n++
+		}
+	}
+	return n
+}
+
+// write prints the report as a table, slowest entity first and slowest file
+// first within each entity, followed by the totals of the whole run.
+func (r *generationReport) write(w io.Writer) error {
+	totals := make(map[string]time.Duration)
+	for _, s := range r.stats {
+		totals[s.entity] += s.render
+	}
+	stats := append([]renderStat(nil), r.stats...)
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.entity != b.entity {
+			if totals[a.entity] != totals[b.entity] {
+				return totals[a.entity] > totals[b.entity]
+			}
+			return a.entity < b.entity
+		}
+		return a.render > b.render
+	})
+
+	var size, funcs int
+	var render time.Duration
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENTITY\tFILE\tRENDER\tBYTES\tFUNCS")
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n", s.entity, s.kind, s.render.Round(time.Microsecond), s.size, s.funcs)
+		size += s.size
+		funcs += s.funcs
+		render += s.render
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d files\t%s\t%d\t%d\n", len(stats), render.Round(time.Microsecond), size, funcs)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	entities := len(totals)
+	if _, ok := totals[graphEntity]; ok {
+		entities--
+	}
+	_, err := fmt.Fprintf(w, "entdomain: generated %d entities in %s (including formatting and writing)\n",
+		entities, time.Since(r.start).Round(time.Millisecond))
+	return err
+}
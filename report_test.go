@@ -0,0 +1,52 @@
+package entdomain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCountFuncs(t *testing.T) {
+	src := []byte("package ent\n\nfunc A() {}\n\nfunc (s *S) B() {\n\tfunc() {}()\n}\n\n// func C() is a comment\n")
+	if got := countFuncs(src); got != 2 {
+		t.Errorf("countFuncs() = %d, want 2", got)
+	}
+}
+
+func TestGenerationReport_NilRecord(t *testing.T) {
+	var r *generationReport
+	r.record("User", "dto", time.Millisecond, []byte("func A() {}\n")) // must not panic
+}
+
+func TestGenerationReport_Write(t *testing.T) {
+	r := newGenerationReport()
+	r.record("Tag", "dto", 1*time.Millisecond, []byte("func A() {}\n"))
+	r.record("User", "dto", 2*time.Millisecond, []byte("func A() {}\nfunc B() {}\n"))
+	r.record("User", "base_service", 5*time.Millisecond, []byte("package ent\n"))
+	r.record(graphEntity, "schema_snapshot", 500*time.Microsecond, []byte("package ent\n"))
+
+	var buf bytes.Buffer
+	if err := r.write(&buf); err != nil {
+		t.Fatalf("write() error = %v", err)
+	}
+	out := buf.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("report has %d lines, want 7:\n%s", len(lines), out)
+	}
+
+	// Slowest entity first, slowest file first within it.
+	order := [][2]string{{"ENTITY", "FILE"}, {"User", "base_service"}, {"User", "dto"}, {"Tag", "dto"}, {graphEntity, "schema_snapshot"}}
+	for i, want := range order {
+		if f := strings.Fields(lines[i]); len(f) < 2 || f[0] != want[0] || f[1] != want[1] {
+			t.Errorf("line %d = %q, want %s %s", i, lines[i], want[0], want[1])
+		}
+	}
+	if f := strings.Fields(lines[5]); len(f) != 6 || f[0] != "TOTAL" || f[1] != "4" || f[5] != "3" {
+		t.Errorf("totals line = %q, want 4 files and 3 funcs", lines[5])
+	}
+	if !strings.Contains(lines[6], "generated 2 entities") {
+		t.Errorf("summary line = %q, want 2 entities", lines[6])
+	}
+}
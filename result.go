@@ -0,0 +1,160 @@
+package entdomain
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metadata keys set by CollectResult and generated code.
+const (
+	// MetadataDuration is the time CollectResult took, in milliseconds.
+	MetadataDuration = "duration_ms"
+	// MetadataCache is "hit" or "miss" for reads through CacheLoad, such as
+	// the GetByID of generated cached services.
+	MetadataCache = "cache"
+)
+
+// Result is a value returned with non-fatal Warnings, such as the use of a
+// deprecated field, and Metadata about how it was produced, such as timings
+// or cache hits. Handlers write both as response headers with SetHeaders.
+type Result[R any] struct {
+	Data     R                 `json:"data"`
+	Warnings []string          `json:"warnings,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewResult returns a Result holding data, without warnings or metadata.
+func NewResult[R any](data R) *Result[R] {
+	return &Result[R]{Data: data}
+}
+
+// Warn adds msg to the warnings of r, unless r has it already.
+func (r *Result[R]) Warn(msg string) {
+	if !slices.Contains(r.Warnings, msg) {
+		r.Warnings = append(r.Warnings, msg)
+	}
+}
+
+// SetMetadata sets the metadata value of key.
+func (r *Result[R]) SetMetadata(key, value string) {
+	if r.Metadata == nil {
+		r.Metadata = make(map[string]string)
+	}
+	r.Metadata[key] = value
+}
+
+// SetHeaders writes the warnings and metadata of r to h. Each warning is a
+// "Warning: 299 - "..."" header, as RFC 7234 defines for miscellaneous
+// persistent warnings. MetadataDuration is written as the Server-Timing
+// header, and other keys as "X-" headers: "cache" becomes X-Cache.
+func (r *Result[R]) SetHeaders(h http.Header) {
+	for _, w := range r.Warnings {
+		h.Add("Warning", "299 - "+strconv.QuoteToASCII(w))
+	}
+	for key, value := range r.Metadata {
+		if key == MetadataDuration {
+			h.Set("Server-Timing", "app;dur="+value)
+			continue
+		}
+		h.Set("X-"+http.CanonicalHeaderKey(strings.ReplaceAll(key, "_", "-")), value)
+	}
+}
+
+// MapResult converts the data of r with fn, keeping its warnings and
+// metadata. It returns nil when r is nil.
+func MapResult[T, R any](r *Result[T], fn func(T) R) *Result[R] {
+	if r == nil {
+		return nil
+	}
+	return &Result[R]{Data: fn(r.Data), Warnings: r.Warnings, Metadata: r.Metadata}
+}
+
+// ConvertResult converts the data of r with fn, a converter returning a
+// Result such as a generated {Entity}EntToResult, merging the warnings and
+// metadata of both. It returns nil when r is nil.
+func ConvertResult[T, R any](r *Result[T], fn func(T) *Result[R]) *Result[R] {
+	if r == nil {
+		return nil
+	}
+	out := fn(r.Data)
+	merged := &Result[R]{Data: out.Data, Warnings: slices.Clone(r.Warnings)}
+	for _, w := range out.Warnings {
+		merged.Warn(w)
+	}
+	for _, m := range []map[string]string{r.Metadata, out.Metadata} {
+		for key, value := range m {
+			merged.SetMetadata(key, value)
+		}
+	}
+	return merged
+}
+
+// DeprecatedFieldWarning is the warning generated code adds for a request
+// setting, or a response holding, the deprecated field.
+func DeprecatedFieldWarning(field string) string {
+	return "field " + strconv.Quote(field) + " is deprecated"
+}
+
+type resultNotesKey struct{}
+
+// resultNotes collects the warnings and metadata added during a CollectResult.
+type resultNotes struct {
+	mu     sync.Mutex
+	result Result[struct{}]
+}
+
+// CollectResult calls fn with a ctx collecting the warnings and metadata
+// that AddWarning and SetMetadata add meanwhile, as generated services do,
+// and returns its value in a Result carrying them and MetadataDuration.
+func CollectResult[R any](ctx context.Context, fn func(ctx context.Context) (R, error)) (*Result[R], error) {
+	notes := &resultNotes{}
+	start := time.Now()
+	data, err := fn(context.WithValue(ctx, resultNotesKey{}, notes))
+	if err != nil {
+		return nil, err
+	}
+	notes.mu.Lock()
+	defer notes.mu.Unlock()
+	res := &Result[R]{Data: data, Warnings: notes.result.Warnings, Metadata: notes.result.Metadata}
+	res.SetMetadata(MetadataDuration, strconv.FormatFloat(float64(time.Since(start).Microseconds())/1000, 'f', 3, 64))
+	return res, nil
+}
+
+// AddWarning adds msg to the warnings of the CollectResult running with ctx.
+// Without one, it does nothing.
+func AddWarning(ctx context.Context, msg string) {
+	if notes, ok := ctx.Value(resultNotesKey{}).(*resultNotes); ok {
+		notes.mu.Lock()
+		notes.result.Warn(msg)
+		notes.mu.Unlock()
+	}
+}
+
+// SetMetadata sets the metadata value of key of the CollectResult running
+// with ctx. Without one, it does nothing.
+func SetMetadata(ctx context.Context, key, value string) {
+	if notes, ok := ctx.Value(resultNotesKey{}).(*resultNotes); ok {
+		notes.mu.Lock()
+		notes.result.SetMetadata(key, value)
+		notes.mu.Unlock()
+	}
+}
+
+// IsSet reports whether v, a request or entity field, holds a value: a
+// non-nil pointer, or a non-zero value.
+func IsSet(v any) bool {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return false
+	}
+	if rv.Kind() == reflect.Pointer {
+		return !rv.IsNil()
+	}
+	return !rv.IsZero()
+}
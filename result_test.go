@@ -0,0 +1,95 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCollectResult(t *testing.T) {
+	ctx := context.Background()
+	AddWarning(ctx, "dropped") // no collector: ignored
+
+	res, err := CollectResult(ctx, func(ctx context.Context) (int, error) {
+		AddWarning(ctx, DeprecatedFieldWarning("nick"))
+		AddWarning(ctx, DeprecatedFieldWarning("nick"))
+		SetMetadata(ctx, MetadataCache, "hit")
+		return 7, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Data != 7 || fmt.Sprint(res.Warnings) != `[field "nick" is deprecated]` || res.Metadata[MetadataCache] != "hit" {
+		t.Errorf("res = %+v", res)
+	}
+	if res.Metadata[MetadataDuration] == "" {
+		t.Error("duration not recorded")
+	}
+
+	if _, err := CollectResult(ctx, func(context.Context) (int, error) { return 0, ErrNotFound }); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestResultConversion(t *testing.T) {
+	res := NewResult(2)
+	res.Warn("a")
+	res.SetMetadata(MetadataCache, "miss")
+
+	mapped := MapResult(res, func(n int) string { return strings.Repeat("x", n) })
+	if mapped.Data != "xx" || len(mapped.Warnings) != 1 || mapped.Metadata[MetadataCache] != "miss" {
+		t.Errorf("MapResult = %+v", mapped)
+	}
+	converted := ConvertResult(res, func(n int) *Result[int] {
+		out := NewResult(n * 10)
+		out.Warn("a")
+		out.Warn("b")
+		out.SetMetadata("source", "replica")
+		return out
+	})
+	if converted.Data != 20 || fmt.Sprint(converted.Warnings) != "[a b]" || len(converted.Metadata) != 2 {
+		t.Errorf("ConvertResult = %+v", converted)
+	}
+	if len(res.Warnings) != 1 {
+		t.Errorf("ConvertResult changed its input: %v", res.Warnings)
+	}
+	if MapResult[int, int](nil, nil) != nil || ConvertResult[int, int](nil, nil) != nil {
+		t.Error("nil results should convert to nil")
+	}
+}
+
+func TestResultSetHeaders(t *testing.T) {
+	res := NewResult(struct{}{})
+	res.Warn(`field "nick" is deprecated`)
+	res.Warn("value truncated\u2026")
+	res.SetMetadata(MetadataDuration, "1.500")
+	res.SetMetadata(MetadataCache, "hit")
+	res.SetMetadata("read_replica", "true")
+
+	h := http.Header{}
+	res.SetHeaders(h)
+	want := http.Header{
+		"Warning":        {`299 - "field \"nick\" is deprecated"`, `299 - "value truncated\u2026"`},
+		"Server-Timing":  {"app;dur=1.500"},
+		"X-Cache":        {"hit"},
+		"X-Read-Replica": {"true"},
+	}
+	if fmt.Sprint(h) != fmt.Sprint(want) {
+		t.Errorf("headers = %v, want %v", h, want)
+	}
+}
+
+func TestIsSet(t *testing.T) {
+	s := ""
+	for v, want := range map[any]bool{nil: false, "": false, "a": true, 0: false, 3: true, &s: true, (*string)(nil): false} {
+		if got := IsSet(v); got != want {
+			t.Errorf("IsSet(%#v) = %v, want %v", v, got, want)
+		}
+	}
+	if IsSet([]string(nil)) || !IsSet([]string{}) {
+		t.Error("IsSet of slices: nil is unset, empty is set")
+	}
+}
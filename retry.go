@@ -0,0 +1,273 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, without calling the database, by calls a
+// CircuitBreaker refuses while it is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Defaults of RetryPolicy and CircuitBreaker fields left zero.
+const (
+	DefaultRetryAttempts    = 3
+	DefaultRetryBaseDelay   = 50 * time.Millisecond
+	DefaultRetryMaxDelay    = 2 * time.Second
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// RetryPolicy retries calls failing with transient database errors, waiting
+// an exponential backoff with jitter between attempts, optionally behind a
+// CircuitBreaker. A nil *RetryPolicy runs calls once. Generated services
+// retry the transactions of WithTx with their Retry policy.
+type RetryPolicy struct {
+	// MaxAttempts bounds the calls made, the first one included;
+	// DefaultRetryAttempts when zero.
+	MaxAttempts int
+	// BaseDelay is the backoff after the first failure, doubling after each
+	// further one up to MaxDelay. Each wait is randomized between half and
+	// all of it. Zero means DefaultRetryBaseDelay and DefaultRetryMaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Retryable reports whether a failed call is retried; IsRetryable when
+	// nil.
+	Retryable func(err error) bool
+	// Breaker, when set, fails calls fast while the database keeps failing.
+	Breaker *CircuitBreaker
+}
+
+// Do calls fn until it succeeds, fails with an error Retryable rejects, or
+// MaxAttempts calls were made, and returns its last error. It stops waiting
+// when ctx is done, returning the last error of fn. fn must be safe to call
+// again; in a transaction, retry the whole transaction rather than a call
+// inside it.
+func (p *RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p == nil {
+		return fn(ctx)
+	}
+	attempts := p.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultRetryAttempts
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	for attempt := 1; ; attempt++ {
+		if p.Breaker != nil {
+			if err := p.Breaker.Allow(); err != nil {
+				return err
+			}
+		}
+		err := fn(ctx)
+		if p.Breaker != nil {
+			p.Breaker.Record(err)
+		}
+		if err == nil || attempt >= attempts || !retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// backoff returns the wait after the given failed attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	base, limit := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	if limit <= 0 {
+		limit = max(base, DefaultRetryMaxDelay)
+	}
+	d := base
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	d = min(d, limit)
+	return d/2 + rand.N(d/2+1)
+}
+
+// Retry is RetryPolicy.Do for calls returning a result.
+func Retry[R any](ctx context.Context, p *RetryPolicy, fn func(ctx context.Context) (R, error)) (R, error) {
+	var result R
+	err := p.Do(ctx, func(ctx context.Context) (err error) {
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+// IsRetryable reports whether err is a transient database error that a
+// retry may not hit again: a serialization failure or deadlock (SQLSTATE
+// 40001 and 40P01, MySQL 1213), a lock wait timeout, or a busy SQLite
+// database. Errors carrying an SQLSTATE, as those of pgx and lib/pq do, are
+// classified by it; others by their message. Cancellations and deadlines of
+// ctx are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var coded interface{ SQLState() string }
+	if errors.As(err, &coded) {
+		switch coded.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, transient := range []string{
+		"deadlock",
+		"could not serialize access",
+		"serialization failure",
+		"lock wait timeout",
+		"database is locked",
+		"sqlite_busy",
+	} {
+		if strings.Contains(msg, transient) {
+			return true
+		}
+	}
+	return false
+}
+
+// CircuitBreaker fails calls with ErrCircuitOpen once Threshold calls in a
+// row failed, sparing a struggling database the load of further attempts.
+// After Cooldown, one trial call is let through: its success closes the
+// breaker, its failure keeps it open for another Cooldown. Errors of the
+// caller, such as ErrNotFound, ErrValidation or a canceled ctx, do not count
+// as failures. A CircuitBreaker is safe for concurrent use and should be
+// shared by the calls to one database.
+type CircuitBreaker struct {
+	// Threshold is the number of failures in a row opening the breaker;
+	// DefaultBreakerThreshold when zero.
+	Threshold int
+	// Cooldown is how long the breaker stays open; DefaultBreakerCooldown
+	// when zero.
+	Cooldown time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// Allow returns ErrCircuitOpen while the breaker is open, and nil when a
+// call may proceed. Callers report the outcome of allowed calls to Record.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold() {
+		return nil
+	}
+	now := b.clock()
+	if now.Sub(b.openedAt) < b.cooldown() {
+		return ErrCircuitOpen
+	}
+	// Let this call through as the trial; the others wait another Cooldown.
+	b.openedAt = now
+	return nil
+}
+
+// Record reports the outcome of a call Allow let through.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !breakerFailure(err) {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold() {
+		b.openedAt = b.clock()
+	}
+}
+
+// Open reports whether the breaker is refusing calls.
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold() && b.clock().Sub(b.openedAt) < b.cooldown()
+}
+
+func (b *CircuitBreaker) threshold() int {
+	if b.Threshold <= 0 {
+		return DefaultBreakerThreshold
+	}
+	return b.Threshold
+}
+
+func (b *CircuitBreaker) cooldown() time.Duration {
+	if b.Cooldown <= 0 {
+		return DefaultBreakerCooldown
+	}
+	return b.Cooldown
+}
+
+func (b *CircuitBreaker) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
+
+// breakerFailure reports whether err tells of a failing database rather than
+// of a call that was refused or canceled.
+func breakerFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	for _, caller := range []error{ErrNotFound, ErrAlreadyExists, ErrValidation, ErrForbidden, ErrConflict, ErrPreconditionFailed, ErrRateLimited, ErrTxRequired} {
+		if errors.Is(err, caller) {
+			return false
+		}
+	}
+	return true
+}
+
+// RetryingRepository runs the calls of a repository, such as a generated
+// {Entity}Repository or the base service itself, under Policy:
+//
+//	users := entdomain.NewRetryingRepository[ent.UserRepository](svc, policy)
+//	u, err := entdomain.RetryCall(ctx, users, func(ctx context.Context, r ent.UserRepository) (*ent.User, error) {
+//		return r.GetByID(ctx, id)
+//	})
+//
+// Calls are retried as a whole, so they must be safe to repeat, and should
+// not be made inside a transaction; retry WithTx instead.
+type RetryingRepository[T any] struct {
+	Next   T
+	Policy *RetryPolicy
+}
+
+// NewRetryingRepository returns a RetryingRepository calling next under
+// policy.
+func NewRetryingRepository[T any](next T, policy *RetryPolicy) *RetryingRepository[T] {
+	return &RetryingRepository[T]{Next: next, Policy: policy}
+}
+
+// Do runs fn with the repository under the retry policy.
+func (r *RetryingRepository[T]) Do(ctx context.Context, fn func(ctx context.Context, repo T) error) error {
+	return r.Policy.Do(ctx, func(ctx context.Context) error {
+		return fn(ctx, r.Next)
+	})
+}
+
+// RetryCall is RetryingRepository.Do for calls returning a result.
+func RetryCall[T, R any](ctx context.Context, r *RetryingRepository[T], fn func(ctx context.Context, repo T) (R, error)) (R, error) {
+	return Retry(ctx, r.Policy, func(ctx context.Context) (R, error) {
+		return fn(ctx, r.Next)
+	})
+}
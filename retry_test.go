@@ -0,0 +1,141 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "pq: error " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{sqlStateError("40001"), true},
+		{fmt.Errorf("update user: %w", sqlStateError("40P01")), true},
+		{sqlStateError("23505"), false},
+		{errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), true},
+		{errors.New("database is locked"), true},
+		{errors.New("connection refused"), false},
+		{ErrNotFound, false},
+		{fmt.Errorf("deadlock: %w", context.Canceled), false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	ctx := context.Background()
+	p := &RetryPolicy{BaseDelay: time.Microsecond}
+	calls := 0
+	got, err := Retry(ctx, p, func(context.Context) (int, error) {
+		if calls++; calls < 3 {
+			return 0, sqlStateError("40001")
+		}
+		return 42, nil
+	})
+	if err != nil || got != 42 || calls != 3 {
+		t.Errorf("Retry = %d, %v after %d calls, want 42 after 3", got, err, calls)
+	}
+
+	calls = 0
+	err = p.Do(ctx, func(context.Context) error { calls++; return sqlStateError("40001") })
+	if calls != DefaultRetryAttempts || !IsRetryable(err) {
+		t.Errorf("exhausted: %d calls, err = %v", calls, err)
+	}
+	calls = 0
+	if err := p.Do(ctx, func(context.Context) error { calls++; return ErrValidation }); !errors.Is(err, ErrValidation) || calls != 1 {
+		t.Errorf("non-retryable: %d calls, err = %v", calls, err)
+	}
+	calls = 0
+	if err := (*RetryPolicy)(nil).Do(ctx, func(context.Context) error { calls++; return sqlStateError("40001") }); err == nil || calls != 1 {
+		t.Errorf("nil policy: %d calls, err = %v", calls, err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	calls = 0
+	slow := &RetryPolicy{BaseDelay: time.Hour}
+	if err := slow.Do(canceled, func(context.Context) error { calls++; return sqlStateError("40001") }); !IsRetryable(err) || calls != 1 {
+		t.Errorf("canceled: %d calls, err = %v", calls, err)
+	}
+
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		d := (&RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}).backoff(attempt)
+		if d < want/2 || d > want {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", attempt, d, want/2, want)
+		}
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := &CircuitBreaker{Threshold: 2, Cooldown: time.Minute, now: func() time.Time { return now }}
+	failure := errors.New("connection refused")
+
+	b.Record(failure)
+	b.Record(ErrNotFound) // a caller error resets the count
+	b.Record(failure)
+	if b.Open() {
+		t.Fatal("opened before Threshold failures in a row")
+	}
+	b.Record(failure)
+	if !b.Open() || !errors.Is(b.Allow(), ErrCircuitOpen) {
+		t.Fatal("not open after Threshold failures")
+	}
+
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("trial call refused: %v", err)
+	}
+	if !errors.Is(b.Allow(), ErrCircuitOpen) {
+		t.Fatal("a second call passed during the trial")
+	}
+	b.Record(failure)
+	if !b.Open() {
+		t.Fatal("failed trial closed the breaker")
+	}
+
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatal(err)
+	}
+	b.Record(nil)
+	if b.Open() || b.Allow() != nil {
+		t.Fatal("successful trial left the breaker open")
+	}
+
+	// A policy with an open breaker fails fast.
+	b.Record(failure)
+	b.Record(failure)
+	calls := 0
+	p := &RetryPolicy{Breaker: b}
+	if err := p.Do(context.Background(), func(context.Context) error { calls++; return nil }); !errors.Is(err, ErrCircuitOpen) || calls != 0 {
+		t.Errorf("open breaker: %d calls, err = %v", calls, err)
+	}
+}
+
+func TestRetryingRepository(t *testing.T) {
+	type repo struct{ calls int }
+	r := NewRetryingRepository(&repo{}, &RetryPolicy{BaseDelay: time.Microsecond})
+	got, err := RetryCall(context.Background(), r, func(_ context.Context, repo *repo) (string, error) {
+		if repo.calls++; repo.calls == 1 {
+			return "", errors.New("deadlock detected")
+		}
+		return "ok", nil
+	})
+	if err != nil || got != "ok" || r.Next.calls != 2 {
+		t.Errorf("RetryCall = %q, %v after %d calls", got, err, r.Next.calls)
+	}
+}
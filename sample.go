@@ -0,0 +1,18 @@
+package entdomain
+
+import (
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+)
+
+// OrderRandom is the order option sorting rows randomly, as generated Sample
+// methods read them: ORDER BY RAND() on MySQL and ORDER BY RANDOM() on
+// PostgreSQL and SQLite. The database sorts every matching row to do so,
+// which suits small or well filtered tables.
+func OrderRandom(s *sql.Selector) {
+	if s.Dialect() == dialect.MySQL {
+		s.OrderExpr(sql.Expr("RAND()"))
+		return
+	}
+	s.OrderExpr(sql.Expr("RANDOM()"))
+}
@@ -0,0 +1,24 @@
+package entdomain
+
+import (
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+)
+
+func TestOrderRandom(t *testing.T) {
+	tests := map[string]string{
+		dialect.MySQL:    "SELECT `products`.`id` FROM `products` ORDER BY RAND()",
+		dialect.Postgres: `SELECT "products"."id" FROM "products" ORDER BY RANDOM()`,
+		dialect.SQLite:   "SELECT `products`.`id` FROM `products` ORDER BY RANDOM()",
+	}
+	for d, want := range tests {
+		products := sql.Table("products")
+		s := sql.Dialect(d).Select(products.C("id")).From(products)
+		OrderRandom(s)
+		if query, _ := s.Query(); query != want {
+			t.Errorf("%s: query = %s, want %s", d, query, want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+	"entgo.io/ent/dialect"
+)
+
+// ErrSchemaDrift is returned by SchemaDrift.Err when the live database does
+// not match the schema the domain code was generated from.
+var ErrSchemaDrift = errors.New("schema drift detected")
+
+// SchemaSnapshot records the table shape of annotated entities at generation
+// time. Generated code exposes it as DomainSchemaSnapshot (see WithSchemaSnapshot).
+type SchemaSnapshot struct {
+	Tables []TableSnapshot `json:"tables"`
+}
+
+// TableSnapshot is the expected shape of one entity's table.
+type TableSnapshot struct {
+	Entity  string           `json:"entity"`
+	Name    string           `json:"name"`
+	Columns []ColumnSnapshot `json:"columns"`
+}
+
+// ColumnSnapshot is the expected shape of one column.
+type ColumnSnapshot struct {
+	Name     string `json:"name"`
+	Nullable bool   `json:"nullable,omitempty"`
+}
+
+// LiveColumn is a column as found in the database.
+type LiveColumn struct {
+	Name     string
+	Nullable bool
+}
+
+// DriftKind classifies a difference between snapshot and database.
+type DriftKind string
+
+const (
+	// DriftMissingTable means an entity's table does not exist.
+	DriftMissingTable DriftKind = "missing_table"
+
+	// DriftMissingColumn means a generated field has no column.
+	DriftMissingColumn DriftKind = "missing_column"
+
+	// DriftNullability means a column's NULL constraint differs from the schema.
+	DriftNullability DriftKind = "nullability"
+
+	// DriftUnknownColumn means the table has a column the schema does not
+	// declare. Often harmless (e.g. added ahead of a deploy) but worth surfacing.
+	DriftUnknownColumn DriftKind = "unknown_column"
+)
+
+// Drift is one difference between a SchemaSnapshot and the live database.
+type Drift struct {
+	Kind   DriftKind
+	Table  string
+	Column string
+	Detail string
+}
+
+// String implements fmt.Stringer.
+func (d Drift) String() string {
+	s := string(d.Kind) + ": " + d.Table
+	if d.Column != "" {
+		s += "." + d.Column
+	}
+	if d.Detail != "" {
+		s += " (" + d.Detail + ")"
+	}
+	return s
+}
+
+// SchemaDrift is the result of comparing a snapshot with a database.
+type SchemaDrift []Drift
+
+// Err returns nil when there is no drift, otherwise an error wrapping
+// ErrSchemaDrift that lists every difference. Use it for fail-fast startup checks.
+func (d SchemaDrift) Err() error {
+	if len(d) == 0 {
+		return nil
+	}
+	parts := make([]string, len(d))
+	for i, drift := range d {
+		parts[i] = drift.String()
+	}
+	return fmt.Errorf("%w: %s", ErrSchemaDrift, strings.Join(parts, "; "))
+}
+
+// CompareSchema compares snapshot with live, keyed by table name. Results
+// are ordered by table, then by snapshot column order, with unknown columns last.
+func CompareSchema(snapshot SchemaSnapshot, live map[string][]LiveColumn) SchemaDrift {
+	var drift SchemaDrift
+	for _, table := range snapshot.Tables {
+		columns, ok := live[table.Name]
+		if !ok {
+			drift = append(drift, Drift{Kind: DriftMissingTable, Table: table.Name, Detail: "entity " + table.Entity})
+			continue
+		}
+
+		byName := make(map[string]LiveColumn, len(columns))
+		for _, c := range columns {
+			byName[c.Name] = c
+		}
+		expected := make(map[string]bool, len(table.Columns))
+		for _, want := range table.Columns {
+			expected[want.Name] = true
+			got, ok := byName[want.Name]
+			switch {
+			case !ok:
+				drift = append(drift, Drift{Kind: DriftMissingColumn, Table: table.Name, Column: want.Name})
+			case got.Nullable != want.Nullable:
+				drift = append(drift, Drift{
+					Kind:   DriftNullability,
+					Table:  table.Name,
+					Column: want.Name,
+					Detail: fmt.Sprintf("schema nullable=%t, database nullable=%t", want.Nullable, got.Nullable),
+				})
+			}
+		}
+
+		var unknown []string
+		for _, c := range columns {
+			if !expected[c.Name] {
+				unknown = append(unknown, c.Name)
+			}
+		}
+		sort.Strings(unknown)
+		for _, name := range unknown {
+			drift = append(drift, Drift{Kind: DriftUnknownColumn, Table: table.Name, Column: name})
+		}
+	}
+	return drift
+}
+
+// CheckSchemaDrift inspects the snapshot's tables in the default schema of db
+// with Atlas, the engine behind ent's migrations, and compares them with
+// snapshot. dialectName is one of dialect.Postgres, dialect.MySQL, or
+// dialect.SQLite. The error reports inspection failures only; call Err on
+// the result to turn drift into an error.
+func CheckSchemaDrift(ctx context.Context, db schema.ExecQuerier, dialectName string, snapshot SchemaSnapshot) (SchemaDrift, error) {
+	var (
+		drv migrate.Driver
+		err error
+	)
+	switch dialectName {
+	case dialect.Postgres:
+		drv, err = postgres.Open(db)
+	case dialect.MySQL:
+		drv, err = mysql.Open(db)
+	case dialect.SQLite:
+		drv, err = sqlite.Open(db)
+	default:
+		return nil, fmt.Errorf("schema drift: unsupported dialect %q", dialectName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("schema drift: open %s inspector: %w", dialectName, err)
+	}
+
+	names := make([]string, len(snapshot.Tables))
+	for i, t := range snapshot.Tables {
+		names[i] = t.Name
+	}
+	s, err := drv.InspectSchema(ctx, "", &schema.InspectOptions{Mode: schema.InspectTables, Tables: names})
+	if err != nil {
+		return nil, fmt.Errorf("schema drift: inspect: %w", err)
+	}
+
+	live := make(map[string][]LiveColumn, len(s.Tables))
+	for _, t := range s.Tables {
+		columns := make([]LiveColumn, len(t.Columns))
+		for i, c := range t.Columns {
+			columns[i] = LiveColumn{Name: c.Name, Nullable: c.Type != nil && c.Type.Null}
+		}
+		live[t.Name] = columns
+	}
+	return CompareSchema(snapshot, live), nil
+}
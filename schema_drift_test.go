@@ -0,0 +1,82 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testSnapshot() SchemaSnapshot {
+	return SchemaSnapshot{Tables: []TableSnapshot{
+		{
+			Entity: "User",
+			Name:   "users",
+			Columns: []ColumnSnapshot{
+				{Name: "id"},
+				{Name: "name"},
+				{Name: "nickname", Nullable: true},
+			},
+		},
+		{
+			Entity:  "Post",
+			Name:    "posts",
+			Columns: []ColumnSnapshot{{Name: "id"}},
+		},
+	}}
+}
+
+func TestCompareSchema_NoDrift(t *testing.T) {
+	live := map[string][]LiveColumn{
+		"users": {{Name: "id"}, {Name: "name"}, {Name: "nickname", Nullable: true}},
+		"posts": {{Name: "id"}},
+	}
+	drift := CompareSchema(testSnapshot(), live)
+	if len(drift) != 0 {
+		t.Errorf("CompareSchema() = %v, want no drift", drift)
+	}
+	if err := drift.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestCompareSchema_Drift(t *testing.T) {
+	live := map[string][]LiveColumn{
+		"users": {{Name: "id"}, {Name: "nickname"}, {Name: "zeta"}, {Name: "legacy"}},
+	}
+	got := CompareSchema(testSnapshot(), live)
+	want := SchemaDrift{
+		{Kind: DriftMissingColumn, Table: "users", Column: "name"},
+		{Kind: DriftNullability, Table: "users", Column: "nickname", Detail: "schema nullable=true, database nullable=false"},
+		{Kind: DriftUnknownColumn, Table: "users", Column: "legacy"},
+		{Kind: DriftUnknownColumn, Table: "users", Column: "zeta"},
+		{Kind: DriftMissingTable, Table: "posts", Detail: "entity Post"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareSchema() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestSchemaDrift_Err(t *testing.T) {
+	drift := SchemaDrift{
+		{Kind: DriftMissingColumn, Table: "users", Column: "name"},
+		{Kind: DriftMissingTable, Table: "posts", Detail: "entity Post"},
+	}
+	err := drift.Err()
+	if !errors.Is(err, ErrSchemaDrift) {
+		t.Fatalf("Err() = %v, want wrapping ErrSchemaDrift", err)
+	}
+	for _, want := range []string{"missing_column: users.name", "missing_table: posts (entity Post)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Err() = %q, missing %q", err, want)
+		}
+	}
+}
+
+func TestCheckSchemaDrift_UnsupportedDialect(t *testing.T) {
+	_, err := CheckSchemaDrift(context.Background(), nil, "oracle", testSnapshot())
+	if err == nil || !strings.Contains(err.Error(), "unsupported dialect") {
+		t.Errorf("CheckSchemaDrift() error = %v, want unsupported dialect", err)
+	}
+}
@@ -0,0 +1,43 @@
+package entdomain
+
+import "context"
+
+// DefaultReindexBatchSize is the number of rows fetched and indexed per batch
+// when ReindexOptions.BatchSize is not set.
+const DefaultReindexBatchSize = 500
+
+// SearchDocument is a single entity projected for an external search index.
+type SearchDocument struct {
+	// ID is the entity's primary key in string form.
+	ID string `json:"id"`
+
+	// Body is the indexed payload. Generated code uses the entity's Response
+	// DTO, so only response-scope, non-sensitive fields reach the index.
+	Body any `json:"body"`
+}
+
+// SearchIndexer writes documents to a search backend (Elasticsearch,
+// OpenSearch, Meilisearch, ...). Implementations should upsert by ID so
+// reindexing is idempotent.
+type SearchIndexer interface {
+	IndexBatch(ctx context.Context, resource string, docs []SearchDocument) error
+}
+
+// ReindexOptions configures a generated Reindex run.
+type ReindexOptions struct {
+	// BatchSize is the number of rows per keyset page and IndexBatch call.
+	// Defaults to DefaultReindexBatchSize.
+	BatchSize int
+
+	// Progress, when set, is called after each batch with the running total
+	// of indexed documents.
+	Progress func(indexed int)
+}
+
+// BatchSizeOrDefault returns BatchSize, falling back to DefaultReindexBatchSize.
+func (o ReindexOptions) BatchSizeOrDefault() int {
+	if o.BatchSize > 0 {
+		return o.BatchSize
+	}
+	return DefaultReindexBatchSize
+}
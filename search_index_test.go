@@ -0,0 +1,22 @@
+package entdomain
+
+import "testing"
+
+func TestReindexOptions_BatchSizeOrDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ReindexOptions
+		want int
+	}{
+		{"zero uses default", ReindexOptions{}, DefaultReindexBatchSize},
+		{"negative uses default", ReindexOptions{BatchSize: -1}, DefaultReindexBatchSize},
+		{"explicit", ReindexOptions{BatchSize: 50}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.BatchSizeOrDefault(); got != tt.want {
+				t.Errorf("BatchSizeOrDefault() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package entdomain
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// ShardIndex maps a shard key to a shard index in [0, shards) using a
+// 64-bit FNV-1a hash of the key's canonical string form. The mapping is
+// stable across processes and releases, so the same key always lands on
+// the same shard as long as the shard count does not change.
+//
+// Strings, byte slices, integers, and fmt.Stringer values (e.g. uuid.UUID)
+// are hashed directly; other types fall back to fmt.Sprint. ShardIndex
+// panics if shards is not positive.
+func ShardIndex(key any, shards int) int {
+	if shards <= 0 {
+		panic(fmt.Sprintf("entdomain: ShardIndex called with %d shards", shards))
+	}
+	h := fnv.New64a()
+	switch k := key.(type) {
+	case string:
+		h.Write([]byte(k))
+	case []byte:
+		h.Write(k)
+	case int:
+		h.Write(strconv.AppendInt(nil, int64(k), 10))
+	case int32:
+		h.Write(strconv.AppendInt(nil, int64(k), 10))
+	case int64:
+		h.Write(strconv.AppendInt(nil, k, 10))
+	case uint64:
+		h.Write(strconv.AppendUint(nil, k, 10))
+	case fmt.Stringer:
+		h.Write([]byte(k.String()))
+	default:
+		h.Write([]byte(fmt.Sprint(k)))
+	}
+	return int(h.Sum64() % uint64(shards))
+}
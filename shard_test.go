@@ -0,0 +1,63 @@
+package entdomain
+
+import (
+	"testing"
+)
+
+type stringerKey string
+
+func (s stringerKey) String() string { return string(s) }
+
+func TestShardIndex_Stable(t *testing.T) {
+	// Same key must always map to the same shard
+	for _, key := range []any{"eu-west", int64(42), 42, int32(42), uint64(42), []byte("eu-west")} {
+		first := ShardIndex(key, 8)
+		for i := 0; i < 10; i++ {
+			if got := ShardIndex(key, 8); got != first {
+				t.Fatalf("ShardIndex(%v) not stable: %d vs %d", key, got, first)
+			}
+		}
+	}
+}
+
+func TestShardIndex_CanonicalForms(t *testing.T) {
+	// Equivalent representations hash identically
+	if ShardIndex("eu-west", 16) != ShardIndex([]byte("eu-west"), 16) {
+		t.Error("string and []byte keys should map to the same shard")
+	}
+	if ShardIndex(42, 16) != ShardIndex(int64(42), 16) {
+		t.Error("int and int64 keys should map to the same shard")
+	}
+	if ShardIndex(stringerKey("eu-west"), 16) != ShardIndex("eu-west", 16) {
+		t.Error("fmt.Stringer keys should hash their String() form")
+	}
+}
+
+func TestShardIndex_Range(t *testing.T) {
+	seen := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		idx := ShardIndex(i, 4)
+		if idx < 0 || idx >= 4 {
+			t.Fatalf("ShardIndex(%d, 4) = %d, out of range", i, idx)
+		}
+		seen[idx] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("expected keys to spread over all 4 shards, got %d", len(seen))
+	}
+}
+
+func TestShardIndex_SingleShard(t *testing.T) {
+	if got := ShardIndex("anything", 1); got != 0 {
+		t.Errorf("ShardIndex(_, 1) = %d, want 0", got)
+	}
+}
+
+func TestShardIndex_PanicsOnZeroShards(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ShardIndex with 0 shards should panic")
+		}
+	}()
+	ShardIndex("key", 0)
+}
@@ -0,0 +1,144 @@
+package entdomain
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable time source for SnowflakeGenerator tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestSnowflake(t *testing.T, node int64, clock *fakeClock) *SnowflakeGenerator {
+	t.Helper()
+	g, err := NewSnowflakeGenerator(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.now = clock.now
+	return g
+}
+
+func mustSnowflake(t *testing.T, g *SnowflakeGenerator) int64 {
+	t.Helper()
+	id, err := g.NewID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := id.Int64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestNewSnowflakeGenerator_NodeRange(t *testing.T) {
+	for _, node := range []int64{-1, MaxSnowflakeNode + 1} {
+		if _, err := NewSnowflakeGenerator(node); !errors.Is(err, ErrValidation) {
+			t.Errorf("NewSnowflakeGenerator(%d) err = %v, want ErrValidation", node, err)
+		}
+	}
+	if _, err := NewSnowflakeGenerator(MaxSnowflakeNode); err != nil {
+		t.Errorf("NewSnowflakeGenerator(%d) = %v", MaxSnowflakeNode, err)
+	}
+}
+
+func TestSnowflakeGenerator_Layout(t *testing.T) {
+	at := SnowflakeEpoch.Add(90 * time.Minute)
+	g := newTestSnowflake(t, 5, &fakeClock{t: at})
+
+	first := mustSnowflake(t, g)
+	second := mustSnowflake(t, g)
+	if got := SnowflakeTime(first); !got.Equal(at) {
+		t.Errorf("SnowflakeTime() = %v, want %v", got, at)
+	}
+	if node := first >> snowflakeSequenceBits & MaxSnowflakeNode; node != 5 {
+		t.Errorf("node bits = %d, want 5", node)
+	}
+	if second != first+1 {
+		t.Errorf("same-millisecond IDs = %d, %d, want consecutive", first, second)
+	}
+}
+
+func TestSnowflakeGenerator_SequenceOverflow(t *testing.T) {
+	clock := &fakeClock{t: SnowflakeEpoch.Add(time.Hour)}
+	g := newTestSnowflake(t, 0, clock)
+
+	prev := mustSnowflake(t, g)
+	for i := 0; i < snowflakeSequenceMask+10; i++ {
+		id := mustSnowflake(t, g)
+		if id <= prev {
+			t.Fatalf("id %d after %d is not increasing", id, prev)
+		}
+		prev = id
+	}
+	if got := SnowflakeTime(prev); !got.After(clock.t) {
+		t.Errorf("after overflow SnowflakeTime() = %v, want a borrowed millisecond after %v", got, clock.t)
+	}
+}
+
+func TestSnowflakeGenerator_ClockRollback(t *testing.T) {
+	clock := &fakeClock{t: SnowflakeEpoch.Add(time.Hour)}
+	g := newTestSnowflake(t, 1, clock)
+	before := mustSnowflake(t, g)
+
+	// A small step back keeps issuing from the last timestamp.
+	clock.t = clock.t.Add(-10 * time.Millisecond)
+	after := mustSnowflake(t, g)
+	if after <= before {
+		t.Errorf("id %d after a small rollback is not above %d", after, before)
+	}
+
+	// Stepping back further than MaxRollback fails until the clock catches up.
+	clock.t = clock.t.Add(-2 * DefaultSnowflakeMaxRollback)
+	if _, err := g.NewID(); !errors.Is(err, ErrClockRollback) {
+		t.Fatalf("NewID() err = %v, want ErrClockRollback", err)
+	}
+	clock.t = clock.t.Add(3 * DefaultSnowflakeMaxRollback)
+	if id := mustSnowflake(t, g); id <= after {
+		t.Errorf("id %d after recovery is not above %d", id, after)
+	}
+}
+
+func TestSnowflakeGenerator_BeforeEpoch(t *testing.T) {
+	g := newTestSnowflake(t, 0, &fakeClock{t: SnowflakeEpoch.Add(-time.Millisecond)})
+	if _, err := g.NewID(); err == nil {
+		t.Error("expected a time before SnowflakeEpoch to fail")
+	}
+}
+
+func TestSnowflakeGenerator_Concurrent(t *testing.T) {
+	g, err := NewSnowflakeGenerator(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const workers, perWorker = 8, 500
+	ids := make(chan ID, workers*perWorker)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				id, err := g.NewID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ids <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	seen := make(map[ID]bool, workers*perWorker)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate id %s", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,72 @@
+package entdomain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent/entc/gen"
+)
+
+// strictCheck reports the configuration mistakes that generation otherwise
+// tolerates silently for a single schema: such fields or schemas are skipped,
+// or annotations are ignored. Each returned error names the schema and field
+// that need fixing.
+func strictCheck(node *gen.Type) []error {
+	fields := domainFields(node)
+	if len(fields) == 0 {
+		return []error{fmt.Errorf("%s: no fields carry a DomainField annotation, so nothing is generated", node.Name)}
+	}
+
+	var errs []error
+	var defaultSorts, shardKeys []string
+	for _, field := range fields {
+		annotation := getDomainFieldAnnotation(field)
+		if annotation.Sortable && isComplexFieldType(field.Type.String()) {
+			errs = append(errs, fmt.Errorf("%s.%s: sortable field of type %s cannot be sorted", node.Name, field.Name, field.Type))
+		}
+		if annotation.DefaultSort != "" {
+			defaultSorts = append(defaultSorts, field.Name)
+			if !annotation.Sortable {
+				errs = append(errs, fmt.Errorf("%s.%s: default sort field is not sortable", node.Name, field.Name))
+			}
+			if order := strings.ToLower(annotation.DefaultSort); order != "asc" && order != "desc" {
+				errs = append(errs, fmt.Errorf("%s.%s: default sort order %q is neither \"asc\" nor \"desc\"", node.Name, field.Name, annotation.DefaultSort))
+			}
+		}
+		if annotation.UniqueLookup && !field.Unique {
+			errs = append(errs, fmt.Errorf("%s.%s: unique lookup field is not declared Unique() in the schema", node.Name, field.Name))
+		}
+		if annotation.ShardKey {
+			shardKeys = append(shardKeys, field.Name)
+		}
+		if alias := annotation.SearchAlias; alias != "" {
+			if field.Type.String() != "string" {
+				errs = append(errs, fmt.Errorf("%s.%s: search alias %q needs a string field, not %s", node.Name, field.Name, alias, field.Type))
+			}
+			if searchAliasClash(node, alias) {
+				errs = append(errs, fmt.Errorf("%s.%s: search alias %q is also a field name", node.Name, field.Name, alias))
+			}
+		}
+	}
+	if len(defaultSorts) > 1 {
+		errs = append(errs, fmt.Errorf("%s: several default sort fields %v, only one is allowed", node.Name, defaultSorts))
+	}
+	if len(shardKeys) > 1 {
+		errs = append(errs, fmt.Errorf("%s: several shard key fields %v, only one is allowed", node.Name, shardKeys))
+	}
+	return errs
+}
+
+// validateStrict runs strictCheck on every schema of the graph and joins the
+// problems into one error, so a single generation run reports all of them.
+func validateStrict(nodes []*gen.Type) error {
+	var errs []error
+	for _, node := range nodes {
+		errs = append(errs, strictCheck(node)...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("strict mode: %w", errors.Join(errs...))
+}
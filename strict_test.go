@@ -0,0 +1,105 @@
+package entdomain
+
+import (
+	"strings"
+	"testing"
+
+	"entgo.io/ent/entc/gen"
+	"entgo.io/ent/schema/field"
+)
+
+func TestStrictCheck(t *testing.T) {
+	unique := func(f *gen.Field) *gen.Field { f.Unique = true; return f }
+	tags := newField("tags", &field.TypeInfo{Type: field.TypeJSON, Ident: "[]string"}, ptr(DefaultField().AsSortable()))
+
+	tests := []struct {
+		name string
+		node *gen.Type
+		want []string
+	}{
+		{
+			name: "valid",
+			node: newTestType("User",
+				unique(newStringField("email", ptr(DefaultField().AsUniqueLookup()))),
+				newTimeField("created_at", ptr(OutputOnlyField().AsDefaultSort("desc"))),
+			),
+		},
+		{
+			name: "no annotated fields",
+			node: newTestType("Tag", newStringField("label", nil)),
+			want: []string{"Tag: no fields carry a DomainField annotation"},
+		},
+		{
+			name: "complex sortable field",
+			node: newTestType("Post", tags),
+			want: []string{"Post.tags: sortable field of type []string cannot be sorted"},
+		},
+		{
+			name: "default sort problems",
+			node: newTestType("Post",
+				newStringField("title", &DomainField{Scopes: AllFieldScopes, DefaultSort: "up"}),
+				newTimeField("created_at", ptr(DefaultField().AsDefaultSort("asc"))),
+			),
+			want: []string{
+				"Post.title: default sort field is not sortable",
+				`Post.title: default sort order "up"`,
+				"Post: several default sort fields [title created_at]",
+			},
+		},
+		{
+			name: "lookup without Unique",
+			node: newTestType("User", newStringField("email", ptr(DefaultField().AsUniqueLookup()))),
+			want: []string{"User.email: unique lookup field is not declared Unique()"},
+		},
+		{
+			name: "several shard keys",
+			node: newTestType("User",
+				newStringField("tenant_id", ptr(DefaultField().AsShardKey())),
+				newStringField("region", ptr(DefaultField().AsShardKey())),
+			),
+			want: []string{"User: several shard key fields [tenant_id region]"},
+		},
+		{
+			name: "search alias problems",
+			node: newTestType("User",
+				newIntField("age", ptr(DefaultField().WithSearchAlias("who"))),
+				newStringField("nickname", ptr(DefaultField().WithSearchAlias("email"))),
+				newStringField("email", nil),
+			),
+			want: []string{
+				`User.age: search alias "who" needs a string field, not int`,
+				`User.nickname: search alias "email" is also a field name`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := strictCheck(tt.node)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("strictCheck() = %v, want %d errors", errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateStrict(t *testing.T) {
+	ok := newTestType("User", newStringField("name", ptr(DefaultField())))
+	if err := validateStrict([]*gen.Type{ok}); err != nil {
+		t.Fatalf("validateStrict() error = %v, want nil", err)
+	}
+
+	err := validateStrict([]*gen.Type{ok, newTestType("Tag"), newTestType("Label")})
+	if err == nil {
+		t.Fatal("validateStrict() should fail for schemas without annotated fields")
+	}
+	for _, want := range []string{"strict mode", "Tag:", "Label:"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("validateStrict() error = %q, want it to contain %q", err, want)
+		}
+	}
+}
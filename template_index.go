@@ -8,3 +8,27 @@ var baseServiceTemplate = mustLoadTemplate("base_service")
 
 // baseHandlerTemplate is the base handler template (ent→response conversion).
 var baseHandlerTemplate = mustLoadTemplate("base_handler")
+
+// permissionsTemplate is the RBAC permission constants template.
+var permissionsTemplate = mustLoadTemplate("permissions")
+
+// domainServiceExtTemplate is the once-only custom service scaffold template.
+var domainServiceExtTemplate = mustLoadTemplate("domain_service_ext")
+
+// exampleTestTemplate is the compiled-but-not-run example tests template.
+var exampleTestTemplate = mustLoadTemplate("example_test")
+
+// benchTestTemplate is the SQLite-backed benchmark template.
+var benchTestTemplate = mustLoadTemplate("bench_test")
+
+// domainMocksTemplate is the repository mocks template.
+var domainMocksTemplate = mustLoadTemplate("domain_mocks")
+
+// domainTracingTemplate is the tracing decorator template.
+var domainTracingTemplate = mustLoadTemplate("domain_tracing")
+
+// importerTemplate is the CSV/JSON Lines bulk importer template.
+var importerTemplate = mustLoadTemplate("importer")
+
+// schemaSnapshotTemplate is the graph-level table shape snapshot template.
+var schemaSnapshotTemplate = mustLoadTemplate("schema_snapshot")
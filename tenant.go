@@ -0,0 +1,102 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoTenant is returned by tenant-aware resolvers when the context carries no tenant.
+var ErrNoTenant = errors.New("no tenant in context")
+
+// ClientResolver selects the client a generated service should use for the
+// current call. C is typically the generated *ent.Client.
+type ClientResolver[C any] interface {
+	Client(ctx context.Context) (C, error)
+}
+
+// ClientResolverFunc adapts an ordinary function to the ClientResolver interface.
+type ClientResolverFunc[C any] func(ctx context.Context) (C, error)
+
+// Client calls f(ctx).
+func (f ClientResolverFunc[C]) Client(ctx context.Context) (C, error) {
+	return f(ctx)
+}
+
+// TenantClients is a ClientResolver that maps the tenant from TenantFromContext
+// to a dedicated client, for database-per-tenant isolation. Clients are either
+// registered up front or opened lazily through Open and cached afterwards.
+// It is safe for concurrent use.
+type TenantClients[C any] struct {
+	// Open, when set, is called the first time an unregistered tenant is seen.
+	// The returned client is cached for subsequent calls.
+	Open func(ctx context.Context, tenantID string) (C, error)
+
+	mu      sync.RWMutex
+	clients map[string]C
+}
+
+// NewTenantClients creates a TenantClients that lazily opens clients with open.
+// Pass nil to require every tenant to be registered explicitly.
+func NewTenantClients[C any](open func(ctx context.Context, tenantID string) (C, error)) *TenantClients[C] {
+	return &TenantClients[C]{Open: open}
+}
+
+// Register associates a tenant with a client, replacing any previous one.
+func (t *TenantClients[C]) Register(tenantID string, client C) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.clients == nil {
+		t.clients = make(map[string]C)
+	}
+	t.clients[tenantID] = client
+}
+
+// Tenants returns the IDs of all tenants with a registered or opened client.
+func (t *TenantClients[C]) Tenants() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	ids := make([]string, 0, len(t.clients))
+	for id := range t.clients {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Client implements ClientResolver. It returns ErrNoTenant when ctx carries
+// no tenant, and an error for tenants that are neither registered nor openable.
+func (t *TenantClients[C]) Client(ctx context.Context) (C, error) {
+	var zero C
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return zero, ErrNoTenant
+	}
+
+	t.mu.RLock()
+	client, ok := t.clients[tenantID]
+	t.mu.RUnlock()
+	if ok {
+		return client, nil
+	}
+
+	if t.Open == nil {
+		return zero, fmt.Errorf("no client registered for tenant %q", tenantID)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Another goroutine may have opened it while we waited for the lock.
+	if client, ok := t.clients[tenantID]; ok {
+		return client, nil
+	}
+	client, err := t.Open(ctx, tenantID)
+	if err != nil {
+		return zero, fmt.Errorf("failed to open client for tenant %q: %w", tenantID, err)
+	}
+	if t.clients == nil {
+		t.clients = make(map[string]C)
+	}
+	t.clients[tenantID] = client
+	return client, nil
+}
@@ -0,0 +1,85 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type fakeClient struct{ name string }
+
+func TestClientResolverFunc(t *testing.T) {
+	want := &fakeClient{name: "primary"}
+	r := ClientResolverFunc[*fakeClient](func(context.Context) (*fakeClient, error) { return want, nil })
+	got, err := r.Client(context.Background())
+	if err != nil || got != want {
+		t.Errorf("Client() = (%v, %v), want (%v, nil)", got, err, want)
+	}
+}
+
+func TestTenantClients_Registered(t *testing.T) {
+	tc := NewTenantClients[*fakeClient](nil)
+	acme := &fakeClient{name: "acme"}
+	tc.Register("acme", acme)
+
+	got, err := tc.Client(WithTenant(context.Background(), "acme"))
+	if err != nil || got != acme {
+		t.Fatalf("Client() = (%v, %v), want (%v, nil)", got, err, acme)
+	}
+
+	if _, err := tc.Client(context.Background()); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Client() without tenant: got %v, want ErrNoTenant", err)
+	}
+
+	_, err = tc.Client(WithTenant(context.Background(), "globex"))
+	if err == nil {
+		t.Fatal("Client() for unregistered tenant should fail without Open")
+	}
+	assertContains(t, err.Error(), "globex")
+}
+
+func TestTenantClients_LazyOpen(t *testing.T) {
+	var mu sync.Mutex
+	opened := map[string]int{}
+	tc := NewTenantClients(func(_ context.Context, tenantID string) (*fakeClient, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		opened[tenantID]++
+		if tenantID == "broken" {
+			return nil, errors.New("dial failed")
+		}
+		return &fakeClient{name: tenantID}, nil
+	})
+
+	ctx := WithTenant(context.Background(), "acme")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := tc.Client(ctx)
+			if err != nil || c.name != "acme" {
+				t.Errorf("Client() = (%v, %v)", c, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if opened["acme"] != 1 {
+		t.Errorf("Open called %d times for acme, want 1", opened["acme"])
+	}
+
+	_, err := tc.Client(WithTenant(context.Background(), "broken"))
+	if err == nil {
+		t.Fatal("expected error from failing Open")
+	}
+	assertContains(t, err.Error(), "dial failed")
+
+	tenants := tc.Tenants()
+	sort.Strings(tenants)
+	if len(tenants) != 1 || tenants[0] != "acme" {
+		t.Errorf("Tenants() = %v, want [acme]", tenants)
+	}
+}
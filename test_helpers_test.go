@@ -1,6 +1,8 @@
 package entdomain
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -73,6 +75,19 @@ func newUUIDTestType(name string, fields ...*gen.Field) *gen.Type {
 	}
 }
 
+// newCompositeTestType creates an edge-schema gen.Type keyed by the given
+// fields, which also become its regular fields.
+func newCompositeTestType(name string, keys ...*gen.Field) *gen.Type {
+	node := &gen.Type{
+		Name:   name,
+		Fields: keys,
+	}
+	node.EdgeSchema.ID = keys
+	node.EdgeSchema.To = &gen.Edge{Name: "to"}
+	node.EdgeSchema.From = &gen.Edge{Name: "from"}
+	return node
+}
+
 // ptr returns a pointer to a DomainField value.
 func ptr(d DomainField) *DomainField {
 	return &d
@@ -91,3 +106,31 @@ func assertNotContains(t *testing.T, s, substr string) {
 		t.Errorf("expected output NOT to contain %q, got:\n%s", substr, s)
 	}
 }
+
+// renderBaseService renders the base service of node with ext, as written to
+// the ent package of a temporary target, with the given ent features enabled.
+func renderBaseService(t *testing.T, ext *Extension, node *gen.Type, features ...gen.Feature) string {
+	t.Helper()
+	cfg := &gen.Config{Target: t.TempDir(), Package: "example.com/app/ent", Features: features}
+	node.Config = cfg
+	if err := ext.generateBaseServiceFile(&gen.Graph{Config: cfg}, node); err != nil {
+		t.Fatalf("generateBaseServiceFile() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(cfg.Target, ext.generatedFilename(node, "base_service")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+// generatedFunc returns the source of the function declared by signature,
+// such as "func (s *BaseUserService) GetByID(", in the generated src.
+func generatedFunc(t *testing.T, src, signature string) string {
+	t.Helper()
+	start := strings.Index(src, signature)
+	if start < 0 {
+		t.Fatalf("generated code has no %s", signature)
+	}
+	end := strings.Index(src[start:], "\n}\n")
+	return src[start : start+end+3]
+}
@@ -0,0 +1,174 @@
+package entdomain
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// The layers of span names: generated {Entity}TracedRepository values wrap
+// a service, or a repository such as the base service, under one of them.
+const (
+	LayerService    = "service"
+	LayerRepository = "repository"
+)
+
+// Redacted replaces the values of sensitive fields in span attributes.
+const Redacted = "[redacted]"
+
+// Attribute is a key-value pair describing a span. Values are strings,
+// bools, int64s, float64s or string slices, see Attr.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attr returns the attribute key=value, converting value to one of the
+// Attribute value types: other integers become int64s, fmt.Stringers such as
+// UUIDs their strings, and other values their fmt.Sprint strings.
+func Attr(key string, value any) Attribute {
+	switch v := value.(type) {
+	case string, bool, int64, float64, []string:
+		return Attribute{Key: key, Value: v}
+	case int:
+		return Attribute{Key: key, Value: int64(v)}
+	case int8:
+		return Attribute{Key: key, Value: int64(v)}
+	case int16:
+		return Attribute{Key: key, Value: int64(v)}
+	case int32:
+		return Attribute{Key: key, Value: int64(v)}
+	case uint:
+		return Attribute{Key: key, Value: int64(v)}
+	case uint8:
+		return Attribute{Key: key, Value: int64(v)}
+	case uint16:
+		return Attribute{Key: key, Value: int64(v)}
+	case uint32:
+		return Attribute{Key: key, Value: int64(v)}
+	case float32:
+		return Attribute{Key: key, Value: float64(v)}
+	case fmt.Stringer:
+		return Attribute{Key: key, Value: v.String()}
+	default:
+		return Attribute{Key: key, Value: fmt.Sprint(v)}
+	}
+}
+
+// Span is a traced operation, ended by the code that started it.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts the spans of generated {Entity}TracedRepository values. It is
+// satisfied by a small adapter of an OpenTelemetry trace.Tracer; the
+// returned ctx carries the span, so spans started from it nest under it.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// TracerFunc adapts an ordinary function to the Tracer interface.
+type TracerFunc func(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+
+// Start calls f(ctx, name, attrs...).
+func (f TracerFunc) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return f(ctx, name, attrs...)
+}
+
+// SpanName returns the name of the span of operation on resource in layer,
+// e.g. "user.repository.GetByID".
+func SpanName(resource, layer, operation string) string {
+	return resource + "." + layer + "." + operation
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// StartSpan starts a span with tracer, or returns ctx and a span doing
+// nothing when tracer is nil.
+func StartSpan(ctx context.Context, tracer Tracer, name string, attrs ...Attribute) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return tracer.Start(ctx, name, attrs...)
+}
+
+// EndSpan records err, unless nil, with span and ends it.
+func EndSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// FieldAttr returns the attribute of a filter on field with value, keyed
+// "filter.<field>", with the value Redacted when sensitive holds field.
+func FieldAttr(field string, value any, sensitive map[string]bool) Attribute {
+	if sensitive[field] {
+		value = Redacted
+	}
+	return Attr("filter."+field, value)
+}
+
+// ListAttributes returns the attributes of the page req requests: its page,
+// size and sort.
+func ListAttributes(req *ListRequest) []Attribute {
+	if req == nil {
+		return nil
+	}
+	attrs := []Attribute{Attr("page", req.Page), Attr("size", req.Size)}
+	if req.SortBy != "" {
+		attrs = append(attrs, Attr("sort_by", req.SortBy))
+	}
+	if req.Order != "" {
+		attrs = append(attrs, Attr("order", req.Order))
+	}
+	return attrs
+}
+
+// SearchAttributes returns ListAttributes of req plus its query and filters:
+// "filter.<field>" for Filters and "filter.<field>.<op>" for the Where and
+// Group filters. The values of fields in sensitive are Redacted.
+func SearchAttributes(req *SearchRequest, sensitive map[string]bool) []Attribute {
+	if req == nil {
+		return nil
+	}
+	attrs := ListAttributes(&req.ListRequest)
+	if req.Query != "" {
+		attrs = append(attrs, Attr("query", req.Query))
+	}
+	for _, field := range slices.Sorted(maps.Keys(req.Filters)) {
+		attrs = append(attrs, FieldAttr(field, req.Filters[field], sensitive))
+	}
+	filters := req.Where
+	var walk func(g *FilterGroup)
+	walk = func(g *FilterGroup) {
+		if g.Filter != nil {
+			filters = append(filters, *g.Filter)
+		}
+		for i := range g.And {
+			walk(&g.And[i])
+		}
+		for i := range g.Or {
+			walk(&g.Or[i])
+		}
+	}
+	if req.Group != nil {
+		walk(req.Group)
+	}
+	for _, f := range filters {
+		value := f.Value
+		if sensitive[f.Field] {
+			value = Redacted
+		}
+		attrs = append(attrs, Attr("filter."+f.Field+"."+string(f.Op), value))
+	}
+	return attrs
+}
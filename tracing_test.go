@@ -0,0 +1,94 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type recordedSpan struct {
+	name  string
+	attrs []Attribute
+	err   error
+	ended bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) { s.attrs = append(s.attrs, attrs...) }
+func (s *recordedSpan) RecordError(err error)            { s.err = err }
+func (s *recordedSpan) End()                             { s.ended = true }
+
+func TestAttr(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		value, want any
+	}{
+		{"a", "a"},
+		{3, int64(3)},
+		{uint8(3), int64(3)},
+		{float32(1.5), 1.5},
+		{true, true},
+		{[]string{"a"}, []string{"a"}},
+		{id, id.String()},
+		{[]int{1, 2}, "[1 2]"},
+	}
+	for _, tt := range tests {
+		if got := Attr("k", tt.value); !reflect.DeepEqual(got.Value, tt.want) {
+			t.Errorf("Attr(%v) = %#v, want %#v", tt.value, got.Value, tt.want)
+		}
+	}
+}
+
+func TestStartSpan(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), nil, "user.repository.GetByID")
+	if ctx == nil || span == nil {
+		t.Fatal("StartSpan() with a nil Tracer returned nil")
+	}
+	EndSpan(span, errors.New("ignored"))
+
+	type spanKey struct{}
+	var started *recordedSpan
+	tracer := TracerFunc(func(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+		started = &recordedSpan{name: name, attrs: attrs}
+		return context.WithValue(ctx, spanKey{}, started), started
+	})
+	name := SpanName("user", LayerRepository, "GetByID")
+	ctx, span = StartSpan(context.Background(), tracer, name, Attr("id", "1"))
+	if ctx.Value(spanKey{}) != started || started.name != "user.repository.GetByID" || len(started.attrs) != 1 {
+		t.Errorf("StartSpan() started %+v", started)
+	}
+	err := errors.New("boom")
+	EndSpan(span, err)
+	if !started.ended || started.err != err {
+		t.Errorf("EndSpan() left %+v", started)
+	}
+}
+
+func TestSearchAttributes(t *testing.T) {
+	req := &SearchRequest{
+		ListRequest: ListRequest{Page: 2, Size: 10, SortBy: "name"},
+		Query:       "ann",
+		Filters:     map[string]any{"status": "active", "token": "secret"},
+		Where:       []Filter{{Field: "token", Op: OpEq, Value: "secret"}},
+		Group:       &FilterGroup{Or: []FilterGroup{{Filter: &Filter{Field: "age", Op: OpGt, Value: 30}}}},
+	}
+	got := SearchAttributes(req, map[string]bool{"token": true})
+	want := []Attribute{
+		{"page", int64(2)},
+		{"size", int64(10)},
+		{"sort_by", "name"},
+		{"query", "ann"},
+		{"filter.status", "active"},
+		{"filter.token", Redacted},
+		{"filter.token.eq", Redacted},
+		{"filter.age.gt", int64(30)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchAttributes() = %v, want %v", got, want)
+	}
+	if SearchAttributes(nil, nil) != nil || ListAttributes(nil) != nil {
+		t.Error("attributes of a nil request should be nil")
+	}
+}
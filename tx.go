@@ -0,0 +1,44 @@
+package entdomain
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// TxManager runs functions in a database transaction. Generated service calls
+// made with the ctx passed to fn join the transaction whatever their entity,
+// so a unit of work spanning several entities commits or rolls back as a
+// whole:
+//
+//	err := txm.WithTx(ctx, func(ctx context.Context) error {
+//		u, err := users.Create(ctx, userReq)
+//		if err != nil {
+//			return err
+//		}
+//		_, err = accounts.Create(ctx, accountRequestFor(u))
+//		return err
+//	})
+//
+// Every generated base service is a TxManager, so services can depend on the
+// interface rather than on a concrete service of another entity.
+type TxManager interface {
+	// WithTx runs fn inside a transaction, committed when fn returns nil and
+	// rolled back otherwise. When ctx already carries a transaction, fn
+	// joins it.
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// TxManagerFunc adapts an ordinary function to TxManager.
+type TxManagerFunc func(ctx context.Context, fn func(ctx context.Context) error) error
+
+// WithTx implements TxManager.
+func (f TxManagerFunc) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return f(ctx, fn)
+}
+
+// ForUpdate is the query modifier that locks the rows read (SELECT ... FOR
+// UPDATE) until the transaction ends. Generated code uses it on ent builds
+// with the sql/modifier feature but not sql/lock, which has ForUpdate on the
+// query itself.
+func ForUpdate(s *sql.Selector) { s.ForUpdate() }
@@ -0,0 +1,38 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+)
+
+func TestTxManagerFunc(t *testing.T) {
+	type key struct{}
+	var m TxManager = TxManagerFunc(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(context.WithValue(ctx, key{}, "tx"))
+	})
+
+	fail := errors.New("fail")
+	err := m.WithTx(context.Background(), func(ctx context.Context) error {
+		if ctx.Value(key{}) != "tx" {
+			t.Error("fn should receive the transaction context")
+		}
+		return fail
+	})
+	if !errors.Is(err, fail) {
+		t.Errorf("WithTx() = %v, want the error of fn", err)
+	}
+}
+
+func TestForUpdate(t *testing.T) {
+	users := sql.Table("users")
+	s := sql.Dialect(dialect.Postgres).Select(users.C("id")).From(users)
+	ForUpdate(s)
+	query, _ := s.Query()
+	if want := `SELECT "users"."id" FROM "users" FOR UPDATE`; query != want {
+		t.Errorf("query = %s, want %s", query, want)
+	}
+}
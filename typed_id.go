@@ -0,0 +1,159 @@
+package entdomain
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// TypedID is an ID holding a primary key of type T. With WithTypedIDs, the
+// generator embeds it in one named type per entity,
+//
+//	type UserID struct{ entdomain.TypedID[uuid.UUID] }
+//
+// and uses those types in service signatures, so passing an OrderID where a
+// UserID is expected no longer compiles even though both wrap a uuid.UUID.
+type TypedID[T comparable] struct {
+	key T
+}
+
+// NewTypedID wraps key.
+func NewTypedID[T comparable](key T) TypedID[T] { return TypedID[T]{key: key} }
+
+// Key returns the wrapped primary key, as passed to ent.
+func (id TypedID[T]) Key() T { return id.key }
+
+// String implements ID. Keys implementing fmt.Stringer use it, integers are
+// formatted in base 10.
+func (id TypedID[T]) String() string {
+	switch k := any(id.key).(type) {
+	case string:
+		return k
+	case fmt.Stringer:
+		return k.String()
+	case int:
+		return strconv.Itoa(k)
+	case int64:
+		return strconv.FormatInt(k, 10)
+	}
+	return fmt.Sprint(id.key)
+}
+
+// IsZero implements ID. The zero key is the zero ID.
+func (id TypedID[T]) IsZero() bool {
+	var zero T
+	return id.key == zero
+}
+
+// Int64 implements ID. Integer keys convert directly and string keys are
+// parsed; other key types have no integer form.
+func (id TypedID[T]) Int64() (int64, error) {
+	switch k := any(id.key).(type) {
+	case int:
+		return int64(k), nil
+	case int32:
+		return int64(k), nil
+	case int64:
+		return k, nil
+	case string:
+		return StringID(k).Int64()
+	}
+	return 0, fmt.Errorf("%w: id %s has no integer form", ErrValidation, id)
+}
+
+// MarshalJSON implements json.Marshaler, encoding the key as json.Marshal
+// would: integers as numbers, UUIDs and strings as strings.
+func (id TypedID[T]) MarshalJSON() ([]byte, error) { return json.Marshal(id.key) }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (id *TypedID[T]) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &id.key); err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	return nil
+}
+
+// AppendText appends the String form of the ID to dst, without allocating
+// for string and integer keys.
+func (id TypedID[T]) AppendText(dst []byte) ([]byte, error) {
+	switch k := any(id.key).(type) {
+	case string:
+		return append(dst, k...), nil
+	case int:
+		return strconv.AppendInt(dst, int64(k), 10), nil
+	case int64:
+		return strconv.AppendInt(dst, k, 10), nil
+	}
+	return append(dst, id.String()...), nil
+}
+
+// MarshalText implements encoding.TextMarshaler with the String form.
+func (id TypedID[T]) MarshalText() ([]byte, error) { return []byte(id.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler for string and integer
+// keys and for keys implementing encoding.TextUnmarshaler (such as uuid.UUID).
+func (id *TypedID[T]) UnmarshalText(text []byte) error {
+	var err error
+	switch k := any(&id.key).(type) {
+	case *string:
+		*k = string(text)
+	case *int:
+		*k, err = strconv.Atoi(string(text))
+	case *int64:
+		*k, err = strconv.ParseInt(string(text), 10, 64)
+	case encoding.TextUnmarshaler:
+		err = k.UnmarshalText(text)
+	default:
+		return fmt.Errorf("%w: cannot parse %T ids from text", ErrValidation, id.key)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: invalid id %q: %v", ErrValidation, text, err)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, storing the key as the column value.
+func (id TypedID[T]) Value() (driver.Value, error) {
+	if v, ok := any(id.key).(driver.Valuer); ok {
+		return v.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(id.key)
+}
+
+// Scan implements sql.Scanner for keys implementing sql.Scanner and for
+// string and integer keys.
+func (id *TypedID[T]) Scan(src any) error {
+	if s, ok := any(&id.key).(sql.Scanner); ok {
+		if err := s.Scan(src); err != nil {
+			return fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		return nil
+	}
+	switch k := any(&id.key).(type) {
+	case *string:
+		var s StringID
+		if err := s.Scan(src); err != nil {
+			return err
+		}
+		*k = string(s)
+		return nil
+	case *int:
+		var v Int64ID
+		if err := v.Scan(src); err != nil {
+			return err
+		}
+		*k = int(v)
+		return nil
+	case *int64:
+		var v Int64ID
+		if err := v.Scan(src); err != nil {
+			return err
+		}
+		*k = int64(v)
+		return nil
+	}
+	return fmt.Errorf("%w: cannot scan %T into %T ids", ErrValidation, src, id.key)
+}
@@ -0,0 +1,153 @@
+package entdomain
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Compile-time checks that TypedID satisfies the ID interfaces.
+var (
+	_ ID            = TypedID[uuid.UUID]{}
+	_ driver.Valuer = TypedID[int]{}
+	_ sql.Scanner   = (*TypedID[string])(nil)
+)
+
+// userID and orderID mirror what the generator emits with WithTypedIDs.
+type (
+	userID  struct{ TypedID[uuid.UUID] }
+	orderID struct{ TypedID[uuid.UUID] }
+)
+
+func TestTypedID(t *testing.T) {
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	tests := []struct {
+		name    string
+		id      ID
+		str     string
+		zero    bool
+		int64   int64
+		int64OK bool
+	}{
+		{"uuid", NewTypedID(u), u.String(), false, 0, false},
+		{"zero uuid", NewTypedID(uuid.Nil), uuid.Nil.String(), true, 0, false},
+		{"int", NewTypedID(42), "42", false, 42, true},
+		{"int64", NewTypedID(int64(-7)), "-7", false, -7, true},
+		{"numeric string", NewTypedID("17"), "17", false, 17, true},
+		{"string", NewTypedID("alice"), "alice", false, 0, false},
+		{"zero string", NewTypedID(""), "", true, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.String(); got != tt.str {
+				t.Errorf("String() = %q, want %q", got, tt.str)
+			}
+			if got := tt.id.IsZero(); got != tt.zero {
+				t.Errorf("IsZero() = %v, want %v", got, tt.zero)
+			}
+			got, err := tt.id.Int64()
+			if tt.int64OK {
+				if err != nil || got != tt.int64 {
+					t.Errorf("Int64() = %d, %v, want %d", got, err, tt.int64)
+				}
+			} else if !errors.Is(err, ErrValidation) {
+				t.Errorf("Int64() err = %v, want ErrValidation", err)
+			}
+		})
+	}
+}
+
+func TestTypedID_Embedded(t *testing.T) {
+	u := uuid.New()
+	a := userID{NewTypedID(u)}
+	b := orderID{NewTypedID(u)}
+	if a.Key() != b.Key() {
+		t.Fatal("keys differ")
+	}
+	if a != (userID{NewTypedID(u)}) {
+		t.Error("equal keys should give equal typed IDs")
+	}
+	if a.String() != u.String() {
+		t.Errorf("promoted String() = %q, want %q", a.String(), u.String())
+	}
+}
+
+func TestTypedID_JSON(t *testing.T) {
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	type payload struct {
+		User  userID       `json:"user"`
+		Count TypedID[int] `json:"count"`
+	}
+	in := payload{User: userID{NewTypedID(u)}, Count: NewTypedID(3)}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"user":"` + u.String() + `","count":3}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+	var out payload
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+	if err := json.Unmarshal([]byte(`{"count":"x"}`), &out); !errors.Is(err, ErrValidation) {
+		t.Errorf("Unmarshal bad key err = %v, want ErrValidation", err)
+	}
+}
+
+func TestTypedID_Text(t *testing.T) {
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	var uid TypedID[uuid.UUID]
+	if err := uid.UnmarshalText([]byte(u.String())); err != nil || uid.Key() != u {
+		t.Errorf("UnmarshalText(uuid) = %v, %v", uid.Key(), err)
+	}
+	var iid TypedID[int64]
+	if err := iid.UnmarshalText([]byte("99")); err != nil || iid.Key() != 99 {
+		t.Errorf("UnmarshalText(int64) = %v, %v", iid.Key(), err)
+	}
+	if err := iid.UnmarshalText([]byte("x")); !errors.Is(err, ErrValidation) {
+		t.Errorf("UnmarshalText(bad int) err = %v, want ErrValidation", err)
+	}
+	var fid TypedID[float64]
+	if err := fid.UnmarshalText([]byte("1.5")); !errors.Is(err, ErrValidation) {
+		t.Errorf("UnmarshalText(float) err = %v, want ErrValidation", err)
+	}
+	if text, err := NewTypedID(int64(5)).MarshalText(); err != nil || string(text) != "5" {
+		t.Errorf("MarshalText = %s, %v", text, err)
+	}
+}
+
+func TestTypedID_SQL(t *testing.T) {
+	u := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	if v, err := NewTypedID(u).Value(); err != nil || v != u.String() {
+		t.Errorf("Value(uuid) = %v, %v", v, err)
+	}
+	if v, err := NewTypedID(7).Value(); err != nil || v != int64(7) {
+		t.Errorf("Value(int) = %#v, %v", v, err)
+	}
+
+	var uid TypedID[uuid.UUID]
+	if err := uid.Scan(u.String()); err != nil || uid.Key() != u {
+		t.Errorf("Scan(uuid) = %v, %v", uid.Key(), err)
+	}
+	var iid TypedID[int]
+	if err := iid.Scan(int64(12)); err != nil || iid.Key() != 12 {
+		t.Errorf("Scan(int) = %v, %v", iid.Key(), err)
+	}
+	var sid TypedID[string]
+	if err := sid.Scan([]byte("bob")); err != nil || sid.Key() != "bob" {
+		t.Errorf("Scan(string) = %v, %v", sid.Key(), err)
+	}
+	var fid TypedID[float64]
+	if err := fid.Scan(1.5); !errors.Is(err, ErrValidation) {
+		t.Errorf("Scan(float) err = %v, want ErrValidation", err)
+	}
+}
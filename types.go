@@ -1,6 +1,11 @@
 package entdomain
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
 
 const (
 	// DefaultPageSize is the default number of items per page when not specified or invalid.
@@ -8,17 +13,101 @@ const (
 
 	// MaxPageSize is the maximum allowed number of items per page.
 	MaxPageSize = 1000
+
+	// MaxQueryTimeout is the longest Timeout a list request may ask for.
+	MaxQueryTimeout = 5 * time.Minute
 )
 
+// Page directions of cursor pagination (ListRequest.Direction).
+const (
+	// DirectionAfter reads the page after Cursor, the first page without
+	// one (Relay first/after). It is the default.
+	DirectionAfter = "after"
+
+	// DirectionBefore reads the page before Cursor, the last page without
+	// one (Relay last/before).
+	DirectionBefore = "before"
+)
+
+// SortField is one key of a multi-field sort: the column name of a sortable
+// field, or of the ID, and its direction.
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// SortAsc returns the ascending sort key on field.
+func SortAsc(field string) SortField { return SortField{Field: field} }
+
+// SortDesc returns the descending sort key on field.
+func SortDesc(field string) SortField { return SortField{Field: field, Desc: true} }
+
+// ParseSort parses a comma-separated sort spec such as "status,-created_at",
+// where a leading "-" sorts that field descending, as used in query strings.
+func ParseSort(spec string) ([]SortField, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	parts := strings.Split(spec, ",")
+	fields := make([]SortField, len(parts))
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		name, desc := strings.CutPrefix(p, "-")
+		if name == "" {
+			return nil, fmt.Errorf("%w: invalid sort spec %q", ErrValidation, spec)
+		}
+		fields[i] = SortField{Field: name, Desc: desc}
+	}
+	return fields, nil
+}
+
 // ListRequest represents a paginated list request with optional sorting.
 // Supports both offset-based (Page/Size) and cursor-based (Cursor/Size) pagination.
 // When Cursor is set, keyset pagination is used; otherwise offset pagination applies.
 type ListRequest struct {
-	Size   int    `json:"size,omitempty" form:"size" validate:"omitempty,min=1,max=100"`
+	Size   int    `json:"size,omitempty" form:"size" validate:"omitempty,min=1,max=1000"`
 	Page   int    `json:"page,omitempty" form:"page" validate:"omitempty,min=0"`
 	SortBy string `json:"sort_by,omitempty" form:"sort_by"`
 	Order  string `json:"order,omitempty" form:"order" validate:"omitempty,oneof=asc desc"`
-	Cursor string `json:"cursor,omitempty" form:"cursor"` // opaque cursor for keyset pagination
+	// Sort orders by several fields in turn and replaces SortBy/Order, which
+	// remain as the single-field shorthand. See SortFields.
+	Sort []SortField `json:"sort,omitempty" form:"-"`
+	// Fields limits the columns read to these response fields, plus the ID
+	// and sort fields. Empty reads every column.
+	Fields []string `json:"fields,omitempty" form:"fields"`
+	// Edges eager loads these edges of the listed entities, by the names
+	// their Response nests them under.
+	Edges []string `json:"edges,omitempty" form:"edges"`
+	// Distinct drops duplicate rows, such as those joins in custom predicates
+	// produce.
+	Distinct bool `json:"distinct,omitempty" form:"distinct"`
+	// DistinctOn keeps one row per combination of these fields, the first in
+	// sort order (PostgreSQL DISTINCT ON). The fields must lead the sort, and
+	// such pages are read by offset only.
+	DistinctOn []string `json:"distinct_on,omitempty" form:"distinct_on"`
+	// ForUpdate locks the rows read (SELECT ... FOR UPDATE) until the
+	// transaction ends, so List and Search then require a ctx from WithTx.
+	// It is set by service code, never bound from a request.
+	ForUpdate bool `json:"-" form:"-"`
+	// IncludeArchived also lists the archived rows of entities declared with
+	// DomainConfig.WithArchivableField.
+	IncludeArchived bool `json:"include_archived,omitempty" form:"include_archived"`
+	// IncludeDeleted also lists the soft-deleted rows of entities with a
+	// deleted_at field, as ListDeleted does. It is set by service code, never
+	// bound from a request.
+	IncludeDeleted bool `json:"-" form:"-"`
+	// Timeout bounds how long List and Search may run, counting included;
+	// the queries are cancelled when it passes. Zero leaves only the deadline
+	// of the caller's context.
+	Timeout time.Duration `json:"timeout,omitempty" form:"timeout"`
+	// CountMode selects how Total is computed; empty means CountExact.
+	CountMode CountMode `json:"count_mode,omitempty" form:"count_mode"`
+	// WithoutTotal skips the count, as CountNone does, for clients such as
+	// infinite scroll that only need PageInfo.HasNextPage.
+	WithoutTotal bool   `json:"without_total,omitempty" form:"without_total"`
+	Cursor       string `json:"cursor,omitempty" form:"cursor"` // opaque cursor for keyset pagination
+	// Direction is DirectionAfter (default) or DirectionBefore, to page backwards from Cursor.
+	Direction string `json:"direction,omitempty" form:"direction" validate:"omitempty,oneof=after before"`
 }
 
 // SetDefaults fills in zero-valued fields with sensible defaults.
@@ -51,9 +140,189 @@ func (r *ListRequest) Validate() error {
 		return fmt.Errorf("order must be 'asc' or 'desc'")
 	}
 
+	if r.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+	if r.Timeout > MaxQueryTimeout {
+		return fmt.Errorf("timeout cannot exceed %s", MaxQueryTimeout)
+	}
+
+	if r.Direction != "" && r.Direction != DirectionAfter && r.Direction != DirectionBefore {
+		return fmt.Errorf("direction must be 'after' or 'before'")
+	}
+
+	switch r.CountMode {
+	case "", CountExact, CountEstimated, CountNone:
+	default:
+		return fmt.Errorf("count_mode must be 'exact', 'estimated' or 'none'")
+	}
+	if r.WithoutTotal && r.CountMode != "" && r.CountMode != CountNone {
+		return fmt.Errorf("without_total cannot be combined with count_mode %q", r.CountMode)
+	}
+
+	if len(r.Sort) > 0 && r.SortBy != "" {
+		return fmt.Errorf("sort and sort_by cannot be combined")
+	}
+	seen := make(map[string]bool, len(r.Sort))
+	for _, f := range r.Sort {
+		if f.Field == "" {
+			return fmt.Errorf("sort field cannot be empty")
+		}
+		if seen[f.Field] {
+			return fmt.Errorf("sort field %q is repeated", f.Field)
+		}
+		seen[f.Field] = true
+	}
+
+	selected := make(map[string]bool, len(r.Fields))
+	for _, f := range r.Fields {
+		if f == "" {
+			return fmt.Errorf("selected field cannot be empty")
+		}
+		if selected[f] {
+			return fmt.Errorf("selected field %q is repeated", f)
+		}
+		selected[f] = true
+	}
+
+	edges := make(map[string]bool, len(r.Edges))
+	for _, e := range r.Edges {
+		if e == "" {
+			return fmt.Errorf("edge cannot be empty")
+		}
+		if edges[e] {
+			return fmt.Errorf("edge %q is repeated", e)
+		}
+		edges[e] = true
+	}
+
+	distinct := make(map[string]bool, len(r.DistinctOn))
+	for _, f := range r.DistinctOn {
+		if f == "" {
+			return fmt.Errorf("distinct_on field cannot be empty")
+		}
+		if distinct[f] {
+			return fmt.Errorf("distinct_on field %q is repeated", f)
+		}
+		distinct[f] = true
+	}
+	if r.ForUpdate && (r.Distinct || len(r.DistinctOn) > 0) {
+		return fmt.Errorf("for_update cannot be combined with distinct")
+	}
+
 	return nil
 }
 
+// EffectiveCountMode returns the CountMode the request is served with:
+// CountNone when WithoutTotal is set, otherwise CountMode.
+func (r *ListRequest) EffectiveCountMode() CountMode {
+	if r.WithoutTotal {
+		return CountNone
+	}
+	return r.CountMode
+}
+
+// WithTimeout returns ctx bounded by Timeout, or ctx itself when Timeout is
+// zero. Callers must call the returned cancel function when done.
+func (r *ListRequest) WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.Timeout)
+}
+
+// SortFields returns the requested sort keys: Sort, or else SortBy with the
+// direction of Order. It returns nil when neither is set, and generated
+// services then apply the default sort field.
+func (r *ListRequest) SortFields() []SortField {
+	if len(r.Sort) > 0 {
+		return r.Sort
+	}
+	if r.SortBy != "" {
+		return []SortField{{Field: r.SortBy, Desc: r.Order == "desc"}}
+	}
+	return nil
+}
+
+// SearchRequest is a ListRequest narrowed by a free-text query and field
+// filters. Generated Search methods page through results by offset (Page)
+// or, when Cursor is set, by keyset from a previous page's PageInfo.EndCursor.
+type SearchRequest struct {
+	ListRequest
+
+	// Query matches entities whose searchable text fields contain it.
+	Query string `json:"query,omitempty" form:"q"`
+
+	// Filters maps filterable field names to the value they must equal. On
+	// optional fields, nil or Null selects NULL values and NotNull the others.
+	// Values are converted to the field type as for Where, so JSON numbers
+	// match integer fields; values that do not convert fail with ErrValidation.
+	Filters map[string]any `json:"filters,omitempty"`
+
+	// Where holds typed filters with operators, see FilterOn. All of them
+	// must match, as must Filters.
+	Where []Filter `json:"where,omitempty"`
+
+	// Group is a tree of AND/OR filters, see AllOf and AnyOf. It must
+	// match in addition to Filters and Where.
+	Group *FilterGroup `json:"group,omitempty"`
+}
+
+// HasFilters reports whether the request narrows the results with a query or
+// any kind of filter.
+func (r *SearchRequest) HasFilters() bool {
+	return r.Query != "" || len(r.Filters) > 0 || len(r.Where) > 0 || r.Group != nil
+}
+
+// Validate checks the embedded ListRequest, the Where filters and the Group
+// tree, and rejects combining a cursor with an offset page.
+func (r *SearchRequest) Validate() error {
+	if r == nil {
+		return fmt.Errorf("search request cannot be nil")
+	}
+	if err := r.ListRequest.Validate(); err != nil {
+		return err
+	}
+	if r.Cursor != "" && r.Page > 1 {
+		return fmt.Errorf("cursor and page cannot be combined")
+	}
+	if r.Direction == DirectionBefore && r.Page > 1 {
+		return fmt.Errorf("direction 'before' pages by cursor and cannot be combined with page")
+	}
+	for _, f := range r.Where {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Group != nil {
+		return r.Group.Validate()
+	}
+	return nil
+}
+
+// ListResult is one page of entities read by a generated service, before
+// conversion to a response DTO. Cursor pages carry a PageInfo; Warnings
+// report parts of the request that could only be partially honored.
+type ListResult[T any] struct {
+	Items    []T       `json:"items"`
+	Total    int       `json:"total"`
+	PageInfo *PageInfo `json:"pageInfo,omitempty"`
+	Warnings []string  `json:"warnings,omitempty"`
+}
+
+// MapListResult converts the items of r with fn, keeping its total, page
+// info and warnings. It returns nil when r is nil.
+func MapListResult[T, R any](r *ListResult[T], fn func(T) R) *ListResult[R] {
+	if r == nil {
+		return nil
+	}
+	items := make([]R, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = fn(item)
+	}
+	return &ListResult[R]{Items: items, Total: r.Total, PageInfo: r.PageInfo, Warnings: r.Warnings}
+}
+
 // Ptr returns a pointer to the given value.
 func Ptr[T any](v T) *T { return &v }
 
@@ -1,7 +1,14 @@
 package entdomain
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestListRequestValidation(t *testing.T) {
@@ -63,6 +70,126 @@ func TestListRequestValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:    "backward direction",
+			req:     &ListRequest{Size: 10, Direction: DirectionBefore},
+			wantErr: false,
+		},
+		{
+			name:    "invalid direction",
+			req:     &ListRequest{Size: 10, Direction: "sideways"},
+			wantErr: true,
+		},
+		{
+			name:    "multi-field sort",
+			req:     &ListRequest{Sort: []SortField{SortAsc("status"), SortDesc("created_at")}},
+			wantErr: false,
+		},
+		{
+			name:    "sort with sort_by",
+			req:     &ListRequest{SortBy: "name", Sort: []SortField{SortAsc("status")}},
+			wantErr: true,
+		},
+		{
+			name:    "repeated sort field",
+			req:     &ListRequest{Sort: []SortField{SortAsc("status"), SortDesc("status")}},
+			wantErr: true,
+		},
+		{
+			name:    "empty sort field",
+			req:     &ListRequest{Sort: []SortField{{Desc: true}}},
+			wantErr: true,
+		},
+		{
+			name:    "selected fields",
+			req:     &ListRequest{Fields: []string{"name", "email"}},
+			wantErr: false,
+		},
+		{
+			name:    "repeated selected field",
+			req:     &ListRequest{Fields: []string{"name", "name"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty selected field",
+			req:     &ListRequest{Fields: []string{""}},
+			wantErr: true,
+		},
+		{
+			name:    "edges",
+			req:     &ListRequest{Edges: []string{"posts", "author"}},
+			wantErr: false,
+		},
+		{
+			name:    "repeated edge",
+			req:     &ListRequest{Edges: []string{"posts", "posts"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty edge",
+			req:     &ListRequest{Edges: []string{""}},
+			wantErr: true,
+		},
+		{
+			name:    "distinct on",
+			req:     &ListRequest{Distinct: true, DistinctOn: []string{"status"}},
+			wantErr: false,
+		},
+		{
+			name:    "repeated distinct on field",
+			req:     &ListRequest{DistinctOn: []string{"status", "status"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty distinct on field",
+			req:     &ListRequest{DistinctOn: []string{""}},
+			wantErr: true,
+		},
+		{
+			name:    "for update",
+			req:     &ListRequest{ForUpdate: true, Sort: []SortField{SortAsc("name")}},
+			wantErr: false,
+		},
+		{
+			name:    "for update with distinct",
+			req:     &ListRequest{ForUpdate: true, Distinct: true},
+			wantErr: true,
+		},
+		{
+			name:    "timeout",
+			req:     &ListRequest{Timeout: 5 * time.Second},
+			wantErr: false,
+		},
+		{
+			name:    "negative timeout",
+			req:     &ListRequest{Timeout: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "timeout above max",
+			req:     &ListRequest{Timeout: MaxQueryTimeout + time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "without total",
+			req:     &ListRequest{WithoutTotal: true, CountMode: CountNone},
+			wantErr: false,
+		},
+		{
+			name:    "without total and exact count",
+			req:     &ListRequest{WithoutTotal: true, CountMode: CountExact},
+			wantErr: true,
+		},
+		{
+			name:    "estimated count",
+			req:     &ListRequest{CountMode: CountEstimated},
+			wantErr: false,
+		},
+		{
+			name:    "invalid count mode",
+			req:     &ListRequest{CountMode: "fast"},
+			wantErr: true,
+		},
 		{
 			name:    "nil request",
 			req:     nil,
@@ -80,6 +207,14 @@ func TestListRequestValidation(t *testing.T) {
 	}
 }
 
+func TestListRequestSizeTagMatchesMaxPageSize(t *testing.T) {
+	f, _ := reflect.TypeOf(ListRequest{}).FieldByName("Size")
+	want := "max=" + strconv.Itoa(MaxPageSize)
+	if tag := f.Tag.Get("validate"); !strings.Contains(tag+",", want+",") {
+		t.Errorf("Size validate tag = %q, want %s as Validate enforces", tag, want)
+	}
+}
+
 func TestListRequestDefaults(t *testing.T) {
 	req := &ListRequest{}
 	req.SetDefaults()
@@ -94,3 +229,175 @@ func TestListRequestDefaults(t *testing.T) {
 	}
 }
 
+func TestListRequestWithTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	got, cancel := (&ListRequest{}).WithTimeout(ctx)
+	cancel()
+	if got != ctx {
+		t.Error("zero Timeout should return ctx unchanged")
+	}
+
+	got, cancel = (&ListRequest{Timeout: time.Minute}).WithTimeout(ctx)
+	defer cancel()
+	deadline, ok := got.Deadline()
+	if !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("Deadline() = %v, %v, want within a minute", deadline, ok)
+	}
+}
+
+func TestListRequestSortFields(t *testing.T) {
+	tests := []struct {
+		name string
+		req  ListRequest
+		want []SortField
+	}{
+		{"none", ListRequest{Order: "desc"}, nil},
+		{"sort_by", ListRequest{SortBy: "name"}, []SortField{{Field: "name"}}},
+		{"sort_by desc", ListRequest{SortBy: "name", Order: "desc"}, []SortField{{Field: "name", Desc: true}}},
+		{"sort", ListRequest{Sort: []SortField{SortDesc("age"), SortAsc("name")}}, []SortField{{Field: "age", Desc: true}, {Field: "name"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.SortFields(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	got, err := ParseSort("status, -created_at")
+	if want := []SortField{SortAsc("status"), SortDesc("created_at")}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSort = %v, %v, want %v", got, err, want)
+	}
+	if got, err := ParseSort(""); err != nil || got != nil {
+		t.Errorf("ParseSort(\"\") = %v, %v", got, err)
+	}
+	for _, spec := range []string{"name,", "-", "a,,b"} {
+		if _, err := ParseSort(spec); !errors.Is(err, ErrValidation) {
+			t.Errorf("ParseSort(%q) err = %v, want ErrValidation", spec, err)
+		}
+	}
+}
+
+func TestSearchRequestHasFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		req  SearchRequest
+		want bool
+	}{
+		{"none", SearchRequest{ListRequest: ListRequest{SortBy: "name"}}, false},
+		{"query", SearchRequest{Query: "ann"}, true},
+		{"filters", SearchRequest{Filters: map[string]any{"status": "active"}}, true},
+		{"where", SearchRequest{Where: []Filter{FilterOn("age").Gt(1)}}, true},
+		{"group", SearchRequest{Group: &FilterGroup{}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.HasFilters(); got != tt.want {
+				t.Errorf("HasFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRequestValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *SearchRequest
+		wantErr bool
+	}{
+		{"nil request", nil, true},
+		{"query and filters", &SearchRequest{Query: "ann", Filters: map[string]any{"status": "active"}}, false},
+		{"cursor", &SearchRequest{ListRequest: ListRequest{Size: 10, Cursor: "eyJpZCI6MX0"}}, false},
+		{"cursor on the first page", &SearchRequest{ListRequest: ListRequest{Page: 1, Cursor: "eyJpZCI6MX0"}}, false},
+		{"cursor with a later page", &SearchRequest{ListRequest: ListRequest{Page: 2, Cursor: "eyJpZCI6MX0"}}, true},
+		{"backward from a cursor", &SearchRequest{ListRequest: ListRequest{Direction: DirectionBefore, Cursor: "eyJpZCI6MX0"}}, false},
+		{"backward with a later page", &SearchRequest{ListRequest: ListRequest{Page: 2, Direction: DirectionBefore}}, true},
+		{"where filters", &SearchRequest{Where: []Filter{FilterOn("age").Gte(18)}}, false},
+		{"invalid where filter", &SearchRequest{Where: []Filter{{Field: "age", Op: "between"}}}, true},
+		{"filter group", &SearchRequest{Group: &FilterGroup{Or: []FilterGroup{{Filter: &Filter{Field: "age", Op: OpLt, Value: 18}}}}}, false},
+		{"empty filter group", &SearchRequest{Group: &FilterGroup{}}, true},
+		{"invalid list request", &SearchRequest{ListRequest: ListRequest{Order: "up"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// FuzzListRequestJSON checks that any JSON body decoding into a ListRequest
+// can be defaulted and validated without panicking, that valid requests
+// stay within bounds, and that they survive a JSON round trip.
+func FuzzListRequestJSON(f *testing.F) {
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`{"size":20,"page":2,"sort_by":"name","order":"asc"}`))
+	f.Add([]byte(`{"size":-1,"order":"sideways"}`))
+	f.Add([]byte(`{"size":1e3,"cursor":"eyJpZCI6MX0"}`))
+	f.Add([]byte(`{"page":9223372036854775807}`))
+	f.Add([]byte(`{"sort":[{"field":"status"},{"field":"created_at","desc":true}]}`))
+	f.Add([]byte(`{"fields":["name","email"]}`))
+	f.Add([]byte(`null`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var req ListRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return
+		}
+
+		req.SetDefaults()
+		if err := req.Validate(); err != nil {
+			return
+		}
+		if req.Size < 1 || req.Size > MaxPageSize {
+			t.Errorf("valid request has size %d outside [1, %d]", req.Size, MaxPageSize)
+		}
+		if req.Page < 0 {
+			t.Errorf("valid request has negative page %d", req.Page)
+		}
+		if req.Order != "" && req.Order != "asc" && req.Order != "desc" {
+			t.Errorf("valid request has order %q", req.Order)
+		}
+
+		if len(req.Sort) == 0 {
+			req.Sort = nil // an empty list is omitted on the way back
+		}
+		if len(req.Fields) == 0 {
+			req.Fields = nil
+		}
+		encoded, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("json.Marshal(%#v) error = %v", req, err)
+		}
+		var again ListRequest
+		if err := json.Unmarshal(encoded, &again); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error = %v", encoded, err)
+		}
+		if !reflect.DeepEqual(again, req) {
+			t.Errorf("round trip changed request: %#v -> %#v", req, again)
+		}
+	})
+}
+
+func TestMapListResult(t *testing.T) {
+	if MapListResult[int, string](nil, strconv.Itoa) != nil {
+		t.Error("MapListResult(nil) should be nil")
+	}
+	in := &ListResult[int]{
+		Items:    []int{1, 2},
+		Total:    5,
+		PageInfo: &PageInfo{HasNextPage: true, EndCursor: "c"},
+		Warnings: []string{"w"},
+	}
+	out := MapListResult(in, strconv.Itoa)
+	if len(out.Items) != 2 || out.Items[0] != "1" || out.Items[1] != "2" {
+		t.Errorf("Items = %v", out.Items)
+	}
+	if out.Total != 5 || out.PageInfo != in.PageInfo || len(out.Warnings) != 1 {
+		t.Errorf("MapListResult = %+v", out)
+	}
+}
@@ -0,0 +1,127 @@
+package entdomain
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by the ULID text form.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDID is an ID holding a ULID: a 48-bit millisecond timestamp followed by
+// 80 random bits. Its 26-character text form sorts in creation order, which
+// keeps string primary keys index-friendly.
+type ULIDID [16]byte
+
+// NewULID returns a ULID for the current time with random bits from crypto/rand.
+func NewULID() (ULIDID, error) {
+	return NewULIDAt(time.Now(), rand.Reader)
+}
+
+// NewULIDAt returns a ULID for t with random bits read from entropy.
+// ULIDs created within the same millisecond are not ordered among themselves.
+func NewULIDAt(t time.Time, entropy io.Reader) (ULIDID, error) {
+	var id ULIDID
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+	if _, err := io.ReadFull(entropy, id[6:]); err != nil {
+		return ULIDID{}, fmt.Errorf("ulid entropy: %w", err)
+	}
+	return id, nil
+}
+
+// ParseULID parses the 26-character text form of a ULID. Letters are
+// case-insensitive, and I, L and O are read as 1, 1 and 0.
+func ParseULID(s string) (ULIDID, error) {
+	if len(s) != 26 {
+		return ULIDID{}, fmt.Errorf("%w: ulid %q must be 26 characters", ErrValidation, s)
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := crockfordValue(s[i])
+		if v < 0 || (i == 0 && v > 7) {
+			return ULIDID{}, fmt.Errorf("%w: invalid ulid %q", ErrValidation, s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	var id ULIDID
+	for i := 7; i >= 0; i-- {
+		id[i] = byte(hi)
+		id[i+8] = byte(lo)
+		hi >>= 8
+		lo >>= 8
+	}
+	return id, nil
+}
+
+// crockfordValue returns the value of a Crockford base32 character, or -1.
+func crockfordValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'z':
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'I', 'L':
+		return 1
+	case 'O':
+		return 0
+	}
+	for i := 10; i < len(crockford); i++ {
+		if crockford[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// Time returns the creation time encoded in the ULID, in millisecond precision.
+func (id ULIDID) Time() time.Time {
+	var ms uint64
+	for _, b := range id[:6] {
+		ms = ms<<8 | uint64(b)
+	}
+	return time.UnixMilli(int64(ms))
+}
+
+// String implements ID, returning the 26-character Crockford base32 form.
+func (id ULIDID) String() string {
+	var dst [26]byte
+	id.encode(&dst)
+	return string(dst[:])
+}
+
+// encode writes the Crockford base32 form of id to dst.
+func (id ULIDID) encode(dst *[26]byte) {
+	var hi, lo uint64
+	for i := 0; i < 8; i++ {
+		hi = hi<<8 | uint64(id[i])
+		lo = lo<<8 | uint64(id[i+8])
+	}
+	for i := len(dst) - 1; i >= 0; i-- {
+		dst[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+}
+
+// IsZero implements ID.
+func (id ULIDID) IsZero() bool { return id == ULIDID{} }
+
+// Int64 implements ID. ULIDs have no integer form, so it always fails.
+func (id ULIDID) Int64() (int64, error) {
+	return 0, fmt.Errorf("%w: ulid id %s has no integer form", ErrValidation, id)
+}
+
+// ULIDGenerator is the "ulid" IDGenerator: it returns NewULID values.
+type ULIDGenerator struct{}
+
+// NewID implements IDGenerator.
+func (ULIDGenerator) NewID() (ID, error) { return NewULID() }
@@ -0,0 +1,68 @@
+package entdomain
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var _ ID = ULIDID{}
+
+func TestULID_RoundTrip(t *testing.T) {
+	at := time.UnixMilli(1469922850259)
+	id, err := NewULIDAt(at, bytes.NewReader(bytes.Repeat([]byte{0xff}, 10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id.String(), "01ARZ3NDEKZZZZZZZZZZZZZZZZ"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !id.Time().Equal(at) {
+		t.Errorf("Time() = %v, want %v", id.Time(), at)
+	}
+
+	parsed, err := ParseULID(strings.ToLower(id.String()))
+	if err != nil || parsed != id {
+		t.Errorf("ParseULID() = %v, %v, want %v", parsed, err, id)
+	}
+}
+
+func TestULID_Ordering(t *testing.T) {
+	a, err := NewULIDAt(time.UnixMilli(1000), bytes.NewReader(make([]byte, 10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() >= b.String() {
+		t.Errorf("ULID text forms should sort by time: %s >= %s", a, b)
+	}
+}
+
+func TestParseULID_Invalid(t *testing.T) {
+	for _, s := range []string{"", "01ARZ3NDEK", "81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FA!"} {
+		if _, err := ParseULID(s); !errors.Is(err, ErrValidation) {
+			t.Errorf("ParseULID(%q) error = %v, want ErrValidation", s, err)
+		}
+	}
+}
+
+func TestULIDID(t *testing.T) {
+	if !(ULIDID{}).IsZero() {
+		t.Error("IsZero() = false for the zero ULID")
+	}
+	id, err := ULIDGenerator{}.NewID()
+	if err != nil || id.IsZero() || len(id.String()) != 26 {
+		t.Errorf("ULIDGenerator.NewID() = %v, %v", id, err)
+	}
+	if _, err := id.Int64(); !errors.Is(err, ErrValidation) {
+		t.Errorf("Int64() error = %v, want ErrValidation", err)
+	}
+	if _, err := NewULIDAt(time.Now(), bytes.NewReader(nil)); err == nil {
+		t.Error("NewULIDAt() with exhausted entropy should fail")
+	}
+}
@@ -0,0 +1,42 @@
+package entdomain
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// DefaultBatchChunkSize is the number of rows generated batch methods write
+// per statement when ExtensionConfig.BatchChunkSize is zero. It stays below
+// the bind parameter limits of the supported databases for typical rows.
+const DefaultBatchChunkSize = 500
+
+// BatchUpdate is one item of a generated UpdateBatch: the ID of the entity
+// and the update to apply to it.
+type BatchUpdate[ID, U any] struct {
+	ID      ID
+	Request U
+}
+
+// DecodeUpdateFields decodes a sparse update given as a map of column names to
+// values into out, an update request whose JSON names are those columns.
+// Keys missing from allowed and nil values are rejected, and so is an empty
+// map. Values are converted through a JSON round trip, as in FilterValue, so
+// decoded JSON bodies work as well as Go values. Errors wrap ErrValidation.
+func DecodeUpdateFields(fields map[string]any, allowed map[string]bool, out any) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: no fields to update", ErrValidation)
+	}
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		if !allowed[k] {
+			return fmt.Errorf("%w: field %q cannot be updated", ErrValidation, k)
+		}
+		if fields[k] == nil {
+			return fmt.Errorf("%w: field %q cannot be null", ErrValidation, k)
+		}
+	}
+	if err := convertFilterValue(fields, out); err != nil {
+		return fmt.Errorf("%w: invalid update fields: %v", ErrValidation, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package entdomain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecodeUpdateFields(t *testing.T) {
+	type request struct {
+		Name *string    `json:"name,omitempty"`
+		Age  *int       `json:"age,omitempty"`
+		Seen *time.Time `json:"seen_at,omitempty"`
+	}
+	allowed := map[string]bool{"name": true, "age": true, "seen_at": true}
+
+	var req request
+	err := DecodeUpdateFields(map[string]any{"age": 18.0, "seen_at": "2024-01-02T15:04:05Z"}, allowed, &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Name != nil || req.Age == nil || *req.Age != 18 || req.Seen == nil || req.Seen.Year() != 2024 {
+		t.Errorf("decoded %+v", req)
+	}
+
+	for name, fields := range map[string]map[string]any{
+		"empty":      {},
+		"unknown":    {"name": "ann", "email": "a@x"},
+		"null":       {"name": nil},
+		"wrong type": {"age": "old"},
+	} {
+		if err := DecodeUpdateFields(fields, allowed, &request{}); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: err = %v, want ErrValidation", name, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StructValidator validates a struct by its `validate` tags. The
+// *validator.Validate of github.com/go-playground/validator/v10 implements
+// it; generated services with a Validator run it on their requests.
+type StructValidator interface {
+	Struct(s any) error
+}
+
+// FieldViolation is a validation rule a field of a request failed.
+type FieldViolation struct {
+	// Field is the name the validator reports, the Go field name unless a
+	// tag name function is registered with it, e.g. one reading json tags.
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+	// Message describes the violation to the caller, in its locale, when
+	// the service has ValidationMessages for it.
+	Message string `json:"message,omitempty"`
+}
+
+// ValidationErrors lists the fields of a request that failed validation, so
+// handlers can report each one. It matches ErrValidation.
+type ValidationErrors []FieldViolation
+
+// Error implements error.
+func (e ValidationErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, v := range e {
+		parts[i] = v.Field + ": " + v.describe()
+	}
+	return ErrValidation.Error() + ": " + strings.Join(parts, "; ")
+}
+
+// describe returns the Message of v, or its rule and param when it has none.
+func (v FieldViolation) describe() string {
+	switch {
+	case v.Message != "":
+		return v.Message
+	case v.Param != "":
+		return v.Rule + "=" + v.Param
+	default:
+		return v.Rule
+	}
+}
+
+// Is reports whether target is ErrValidation.
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// fieldError is the part of validator.FieldError ValidateStruct reads.
+type fieldError interface {
+	Field() string
+	Tag() string
+	Param() string
+}
+
+// ValidateStruct validates s with v. The field errors of go-playground's
+// validator.ValidationErrors are returned as ValidationErrors; other errors
+// are wrapped in ErrValidation. A nil v accepts every struct.
+func ValidateStruct(v StructValidator, s any) error {
+	if v == nil {
+		return nil
+	}
+	err := v.Struct(s)
+	if err == nil {
+		return nil
+	}
+	if violations := fieldViolations(err); violations != nil {
+		return violations
+	}
+	return fmt.Errorf("%w: %v", ErrValidation, err)
+}
+
+// fieldViolations converts err, a slice of field errors such as
+// validator.ValidationErrors, without depending on the validator package.
+// It returns nil for other errors.
+func fieldViolations(err error) ValidationErrors {
+	rv := reflect.ValueOf(err)
+	if rv.Kind() != reflect.Slice || rv.Len() == 0 {
+		return nil
+	}
+	violations := make(ValidationErrors, rv.Len())
+	for i := range rv.Len() {
+		fe, ok := rv.Index(i).Interface().(fieldError)
+		if !ok {
+			return nil
+		}
+		violations[i] = FieldViolation{Field: fe.Field(), Rule: fe.Tag(), Param: fe.Param()}
+	}
+	return violations
+}
+
+// ValidationMessages supplies the messages of validation failures by locale.
+// Generated services with ValidationMessages set the Message of the
+// ValidationErrors their Validator reports, in the locale of WithLocale.
+type ValidationMessages interface {
+	// Message returns the message of v in locale, or "" when there is none.
+	Message(locale string, v FieldViolation) string
+}
+
+// MessageTable is ValidationMessages from message templates keyed by locale
+// and rule, e.g. {"de": {"required": "{field} ist erforderlich"}}, where
+// {field} and {param} stand for those of the violation. A locale without the
+// rule falls back to its language ("de" for "de-CH"), then to the "" locale.
+type MessageTable map[string]map[string]string
+
+// Message implements ValidationMessages.
+func (t MessageTable) Message(locale string, v FieldViolation) string {
+	for {
+		if tmpl, ok := t[locale][v.Rule]; ok {
+			return strings.NewReplacer("{field}", v.Field, "{param}", v.Param).Replace(tmpl)
+		}
+		if locale == "" {
+			return ""
+		}
+		if i := strings.LastIndexAny(locale, "-_"); i > 0 {
+			locale = locale[:i]
+		} else {
+			locale = ""
+		}
+	}
+}
+
+// LocalizeValidation sets the Message of the violations of err, when it is
+// ValidationErrors, from messages in the locale of ctx (LocaleFrom). Other
+// errors, and any error when messages is nil, are returned unchanged.
+func LocalizeValidation(ctx context.Context, messages ValidationMessages, err error) error {
+	var violations ValidationErrors
+	if messages == nil || !errors.As(err, &violations) {
+		return err
+	}
+	locale := LocaleFrom(ctx)
+	localized := make(ValidationErrors, len(violations))
+	for i, v := range violations {
+		if message := messages.Message(locale, v); message != "" {
+			v.Message = message
+		}
+		localized[i] = v
+	}
+	return localized
+}
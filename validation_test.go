@@ -0,0 +1,76 @@
+package entdomain
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// testFieldError and testFieldErrors mimic validator.FieldError and
+// validator.ValidationErrors.
+type testFieldError struct{ field, tag, param string }
+
+func (e testFieldError) Field() string { return e.field }
+func (e testFieldError) Tag() string   { return e.tag }
+func (e testFieldError) Param() string { return e.param }
+
+type testFieldErrors []testFieldError
+
+func (testFieldErrors) Error() string { return "invalid" }
+
+type testValidator struct{ err error }
+
+func (v testValidator) Struct(any) error { return v.err }
+
+func TestValidateStruct(t *testing.T) {
+	if err := ValidateStruct(nil, struct{}{}); err != nil {
+		t.Errorf("nil validator: %v", err)
+	}
+	if err := ValidateStruct(testValidator{}, struct{}{}); err != nil {
+		t.Errorf("passing validator: %v", err)
+	}
+
+	err := ValidateStruct(testValidator{testFieldErrors{{"email", "email", ""}, {"name", "min", "3"}}}, struct{}{})
+	var violations ValidationErrors
+	if !errors.As(err, &violations) || len(violations) != 2 || violations[1] != (FieldViolation{Field: "name", Rule: "min", Param: "3"}) {
+		t.Fatalf("field errors: got %#v", err)
+	}
+	if !IsValidation(err) {
+		t.Error("ValidationErrors should match ErrValidation")
+	}
+	if want := "validation failed: email: email; name: min=3"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	err = ValidateStruct(testValidator{errors.New("not a struct")}, 1)
+	if !IsValidation(err) || errors.As(err, &violations) {
+		t.Errorf("other errors: got %#v, want wrapped ErrValidation", err)
+	}
+}
+
+func TestLocalizeValidation(t *testing.T) {
+	messages := MessageTable{
+		"":   {"required": "{field} is required"},
+		"de": {"min": "{field} muss mindestens {param} lang sein"},
+	}
+	err := ValidationErrors{{Field: "name", Rule: "min", Param: "3"}, {Field: "email", Rule: "required"}, {Field: "age", Rule: "gte"}}
+
+	got := LocalizeValidation(WithLocale(context.Background(), "de-CH"), messages, err)
+	want := "validation failed: name: name muss mindestens 3 lang sein; email: email is required; age: gte"
+	if !IsValidation(got) || got.Error() != want {
+		t.Errorf("LocalizeValidation() = %q, want %q", got, want)
+	}
+	if err[0].Message != "" {
+		t.Error("LocalizeValidation() modified err")
+	}
+
+	got = LocalizeValidation(context.Background(), messages, err)
+	if violations := got.(ValidationErrors); violations[0].Message != "" || violations[1].Message != "email is required" {
+		t.Errorf("LocalizeValidation() without a locale = %#v", got)
+	}
+
+	other := errors.New("boom")
+	if LocalizeValidation(context.Background(), messages, other) != other || LocalizeValidation(context.Background(), nil, err).Error() != err.Error() {
+		t.Error("LocalizeValidation() should return other errors, and any without messages, unchanged")
+	}
+}